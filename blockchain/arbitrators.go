@@ -0,0 +1,151 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"math"
+
+	"github.com/wuyazero/Elastos.ELA/config"
+	. "github.com/wuyazero/Elastos.ELA/core"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
+)
+
+// compressedPublicKeyLength is the length of an arbitrator public key, which
+// is compared in bytes with the public keys of the cross chain scripts.
+const compressedPublicKeyLength = 33
+
+// GetArbitratorsMajority returns the least count of the arbitrators signing
+// an UpdateArbitrators transaction, which is more than two thirds of them.
+func GetArbitratorsMajority(count int) int {
+	return count*2/3 + 1
+}
+
+// GetArbitratorsProgramHash returns the program hash of the multi-sign script
+// of the arbitrators, signed by the majority of them.
+func GetArbitratorsProgramHash(arbitrators [][]byte) (*Uint168, error) {
+	publicKeys := make([]*crypto.PublicKey, 0, len(arbitrators))
+	for _, arbitrator := range arbitrators {
+		publicKey, err := crypto.DecodePoint(arbitrator)
+		if err != nil {
+			return nil, err
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	m := uint(GetArbitratorsMajority(len(publicKeys)))
+	code, err := crypto.CreateMultiSignRedeemScript(m, publicKeys)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.ToProgramHash(code)
+}
+
+// getArbitratorsKey returns the key of the arbitrators set by the block at the
+// height.
+func getArbitratorsKey(height uint32) []byte {
+	return getHeightKey([]byte{byte(ST_Arbitrators)}, height)
+}
+
+// PersistArbitrators stores the arbitrators set by the UpdateArbitrators
+// transaction of the block, which are the arbitrators since the block.
+func (c *ChainStore) PersistArbitrators(b *Block) error {
+	for _, txn := range b.Transactions {
+		payload, ok := txn.Payload.(*PayloadUpdateArbitrators)
+		if !ok {
+			continue
+		}
+		value := new(bytes.Buffer)
+		if err := payload.Serialize(value, UpdateArbitratorsPayloadVersion); err != nil {
+			return err
+		}
+		c.BatchPut(getArbitratorsKey(b.Header.Height), value.Bytes())
+	}
+	return nil
+}
+
+// RollbackArbitrators removes the arbitrators set by the block.
+func (c *ChainStore) RollbackArbitrators(b *Block) error {
+	for _, txn := range b.Transactions {
+		if txn.TxType == UpdateArbitrators {
+			c.BatchDelete(getArbitratorsKey(b.Header.Height))
+		}
+	}
+	return nil
+}
+
+// GetArbitrators returns the arbitrators after the block at the height, which
+// are the arbitrators set by the last UpdateArbitrators transaction till the
+// height, or the arbitrators of the chain params if there is none.
+func (c *ChainStore) GetArbitrators(height uint32) ([][]byte, error) {
+	iter := c.NewIterator([]byte{byte(ST_Arbitrators)})
+	defer iter.Release()
+	// the last set till the height is the one before the first set after it
+	var found bool
+	if height < math.MaxUint32 && iter.Seek(getArbitratorsKey(height+1)) {
+		found = iter.Prev()
+	} else {
+		found = iter.Last()
+	}
+	if !found {
+		return config.Parameters.GetArbitrators()
+	}
+	payload := new(PayloadUpdateArbitrators)
+	err := payload.Deserialize(bytes.NewReader(iter.Value()), UpdateArbitratorsPayloadVersion)
+	if err != nil {
+		return nil, err
+	}
+	return payload.Arbitrators, nil
+}
+
+// GetArbitratorGroup returns the arbitrators after the block at the height and
+// the index of the arbitrator on duty, the arbitrators take turns by height.
+func GetArbitratorGroup(height uint32) ([][]byte, int, error) {
+	arbitrators, err := DefaultLedger.Store.GetArbitrators(height)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(arbitrators) == 0 {
+		return nil, 0, errors.New("arbitrators not found")
+	}
+	return arbitrators, int(height % uint32(len(arbitrators))), nil
+}
+
+func checkUpdateArbitratorsPayload(txn *Transaction) error {
+	payload, ok := txn.Payload.(*PayloadUpdateArbitrators)
+	if !ok {
+		return errors.New("invalid update arbitrators payload")
+	}
+	count := len(payload.Arbitrators)
+	if count == 0 || count > config.Parameters.ChainParam.MaxArbitrators {
+		return errors.New("invalid arbitrators count")
+	}
+	arbitrators := make(map[string]struct{}, count)
+	for _, arbitrator := range payload.Arbitrators {
+		if len(arbitrator) != compressedPublicKeyLength {
+			return errors.New("arbitrator public key must be compressed")
+		}
+		if _, err := crypto.DecodePoint(arbitrator); err != nil {
+			return errors.New("invalid arbitrator public key")
+		}
+		if _, ok := arbitrators[string(arbitrator)]; ok {
+			return errors.New("duplicated arbitrator")
+		}
+		arbitrators[string(arbitrator)] = struct{}{}
+	}
+	return nil
+}
+
+// getCurrentArbitratorsProgramHash returns the program hash of the current
+// arbitrators, which signs the transactions of the arbitrators.
+func getCurrentArbitratorsProgramHash() (*Uint168, error) {
+	arbitrators, err := DefaultLedger.Store.GetArbitrators(DefaultLedger.Store.GetHeight())
+	if err != nil {
+		return nil, err
+	}
+	return GetArbitratorsProgramHash(arbitrators)
+}
+
+func updateArbitratorsConflictKeys(txn *Transaction) []string {
+	return []string{"arbitrators"}
+}
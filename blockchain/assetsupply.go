@@ -0,0 +1,73 @@
+package blockchain
+
+import (
+	"errors"
+	"io"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+	. "github.com/wuyazero/Elastos.ELA/core"
+)
+
+// AssetSupply is the supply state of an asset registered by a RegisterAsset
+// transaction, the native ELA asset has no supply state.
+type AssetSupply struct {
+	// Controller is the program hash which signs the minting and burning.
+	Controller Uint168
+	// MaxSupply is the maximum amount can be issued, zero means unlimited.
+	MaxSupply Fixed64
+	// Issued is the total amount issued by registering and minting.
+	Issued Fixed64
+	// Burned is the total amount destroyed by burning.
+	Burned Fixed64
+}
+
+// Circulating returns the amount of the asset in circulation.
+func (s *AssetSupply) Circulating() Fixed64 {
+	return s.Issued - s.Burned
+}
+
+func (s *AssetSupply) Serialize(w io.Writer) error {
+	if err := s.Controller.Serialize(w); err != nil {
+		return errors.New("[AssetSupply], Controller serialize failed.")
+	}
+	if err := s.MaxSupply.Serialize(w); err != nil {
+		return errors.New("[AssetSupply], MaxSupply serialize failed.")
+	}
+	if err := s.Issued.Serialize(w); err != nil {
+		return errors.New("[AssetSupply], Issued serialize failed.")
+	}
+	if err := s.Burned.Serialize(w); err != nil {
+		return errors.New("[AssetSupply], Burned serialize failed.")
+	}
+	return nil
+}
+
+func (s *AssetSupply) Deserialize(r io.Reader) error {
+	if err := s.Controller.Deserialize(r); err != nil {
+		return errors.New("[AssetSupply], Controller deserialize failed.")
+	}
+	if err := s.MaxSupply.Deserialize(r); err != nil {
+		return errors.New("[AssetSupply], MaxSupply deserialize failed.")
+	}
+	if err := s.Issued.Deserialize(r); err != nil {
+		return errors.New("[AssetSupply], Issued deserialize failed.")
+	}
+	if err := s.Burned.Deserialize(r); err != nil {
+		return errors.New("[AssetSupply], Burned deserialize failed.")
+	}
+	return nil
+}
+
+// getSupplyChange returns the asset which supply is changed by the transaction
+// and the amount issued, the amount is negative if the asset is burned.
+func getSupplyChange(txn *Transaction) (Uint256, Fixed64, bool) {
+	switch payload := txn.Payload.(type) {
+	case *PayloadRegisterAsset:
+		return payload.AssetID(), payload.Amount, true
+	case *PayloadMintAsset:
+		return payload.AssetID, payload.Amount, true
+	case *PayloadBurnAsset:
+		return payload.AssetID, -payload.Amount, true
+	}
+	return Uint256{}, 0, false
+}
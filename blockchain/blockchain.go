@@ -126,7 +126,7 @@ func GetGenesisBlock() (*Block, error) {
 	coinBase.Outputs = []*Output{
 		{
 			AssetID:     elaCoin.Hash(),
-			Value:       3300 * 10000 * 100000000,
+			Value:       config.Parameters.ChainParam.GenesisSupply,
 			ProgramHash: FoundationAddress,
 		},
 	}
@@ -1244,6 +1244,31 @@ func (s timeSorter) Less(i, j int) bool {
 	return s[i] < s[j]
 }
 
+// calcPastMedianTimeByHeight returns the past median time of the block at the
+// given height in the best chain, the headers are read from the store because
+// the block may be too old to have a node in memory.
+func calcPastMedianTimeByHeight(height uint32) (time.Time, error) {
+	timestamps := make([]int64, 0, medianTimeBlocks)
+	for i := 0; i < medianTimeBlocks; i++ {
+		hash, err := DefaultLedger.Store.GetBlockHash(height)
+		if err != nil {
+			return time.Time{}, err
+		}
+		header, err := DefaultLedger.Store.GetHeader(hash)
+		if err != nil {
+			return time.Time{}, err
+		}
+		timestamps = append(timestamps, int64(header.Timestamp))
+		if height == 0 {
+			break
+		}
+		height--
+	}
+
+	sort.Sort(timeSorter(timestamps))
+	return time.Unix(timestamps[len(timestamps)/2], 0), nil
+}
+
 func CalcPastMedianTime(node *BlockNode) time.Time {
 	timestamps := make([]int64, medianTimeBlocks)
 	numNodes := 0
@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"time"
@@ -17,8 +18,44 @@ import (
 
 const (
 	MaxTimeOffsetSeconds = 2 * 60 * 60
+
+	// LockTimeThreshold is the number below which a lock time is interpreted
+	// to be a block height, otherwise it is interpreted to be a Unix timestamp.
+	LockTimeThreshold = 5e8 // Tue Nov  5 00:53:20 1985 UTC
+
+	// MaxTxInSequenceNum is the sequence of a final input, the lock time of a
+	// transaction is ignored if all of its inputs are final.
+	MaxTxInSequenceNum = math.MaxUint32
+
+	// SequenceLockTimeDisabled is the flag of an input sequence, the relative
+	// lock time is not applied to the input if it is set.
+	SequenceLockTimeDisabled = 1 << 31
+
+	// SequenceLockTimeEnabled is the flag of an input sequence opting in to
+	// the relative lock time, the sequences of the legacy inputs keep their
+	// meaning.
+	SequenceLockTimeEnabled = 1 << 30
+
+	// SequenceLockTimeIsSeconds is the flag of an input sequence, the relative
+	// lock time is in units of 512 seconds if it is set, otherwise blocks.
+	SequenceLockTimeIsSeconds = 1 << 22
+
+	// SequenceLockTimeMask is the mask of the relative lock time value.
+	SequenceLockTimeMask = 0x0000ffff
+
+	// SequenceLockTimeGranularity is the number of bits to shift a time based
+	// relative lock time value to convert it to seconds, 2^9 = 512 seconds.
+	SequenceLockTimeGranularity = 9
 )
 
+// SequenceLock is the last block height and past median time at which the
+// transaction is still locked by the relative lock times of its inputs, -1
+// means no restriction.
+type SequenceLock struct {
+	Seconds     int64
+	BlockHeight int64
+}
+
 func PowCheckBlockSanity(block *Block, powLimit *big.Int, timeSource MedianTimeSource) error {
 	header := block.Header
 	hash := header.Hash()
@@ -83,7 +120,7 @@ func PowCheckBlockSanity(block *Block, powLimit *big.Int, timeSource MedianTimeS
 	txIds := make([]Uint256, 0, len(transactions))
 	existingTxIds := make(map[Uint256]struct{})
 	existingTxInputs := make(map[string]struct{})
-	existingSideTxs := make(map[Uint256]struct{})
+	existingConflictKeys := make(map[string]struct{})
 	for _, txn := range transactions {
 		txId := txn.Hash()
 		// Check for duplicate transactions.
@@ -106,16 +143,13 @@ func PowCheckBlockSanity(block *Block, powLimit *big.Int, timeSource MedianTimeS
 			existingTxInputs[referKey] = struct{}{}
 		}
 
-		if txn.IsWithdrawFromSideChainTx() {
-			witPayload := txn.Payload.(*PayloadWithdrawFromSideChain)
-
-			// Check for duplicate sidechain tx in a block
-			for _, hash := range witPayload.SideChainTransactionHashes {
-				if _, exists := existingSideTxs[hash]; exists {
-					return errors.New("[PowCheckBlockSanity] block contains duplicate sidechain Tx")
-				}
-				existingSideTxs[hash] = struct{}{}
+		// Check for conflicting transactions in a block, such as withdraw
+		// transactions of the same sidechain tx or registering the same asset
+		for _, key := range getConflictKeys(txn) {
+			if _, exists := existingConflictKeys[key]; exists {
+				return errors.New("[PowCheckBlockSanity] block contains conflicting transactions of " + key)
 			}
+			existingConflictKeys[key] = struct{}{}
 		}
 
 		// Append transaction to list
@@ -135,12 +169,21 @@ func PowCheckBlockSanity(block *Block, powLimit *big.Int, timeSource MedianTimeS
 func CheckBlockContext(block *Block) error {
 	var rewardInCoinbase = Fixed64(0)
 	var totalTxFee = Fixed64(0)
+	var mintedAssets = make(map[Uint256]Fixed64)
 
 	for index, tx := range block.Transactions {
 		if errCode := CheckTransactionContext(tx); errCode != Success {
 			return errors.New("CheckTransactionContext failed when verify block")
 		}
 
+		// Minted amount of an asset in the block must not exceed max supply
+		if payload, ok := tx.Payload.(*PayloadMintAsset); ok {
+			mintedAssets[payload.AssetID] += payload.Amount
+			if err := CheckAssetMaxSupply(payload.AssetID, mintedAssets[payload.AssetID]); err != nil {
+				return err
+			}
+		}
+
 		if index == 0 {
 			// Calculate reward in coinbase
 			for _, output := range tx.Outputs {
@@ -152,10 +195,41 @@ func CheckBlockContext(block *Block) error {
 		totalTxFee += GetTxFee(tx, DefaultLedger.Blockchain.AssetID)
 	}
 
-	// Reward in coinbase must match inflation 4% per year
-	if rewardInCoinbase-totalTxFee != RewardAmountPerBlock {
+	// Reward in coinbase must match the subsidy of the block
+	subsidy := CalcBlockSubsidy(block.Header.Height, config.Parameters.ChainParam)
+	if rewardInCoinbase-totalTxFee != subsidy {
 		return errors.New("reward amount in coinbase not correct")
 	}
+	if block.Header.Height < config.Parameters.ChainParam.RewardSplitActivationHeight {
+		return nil
+	}
+	return CheckCoinbaseRewards(block.Transactions[0], rewardInCoinbase, GetElectedProducers())
+}
+
+// CheckCoinbaseRewards checks the coinbase pays the foundation, the miner and
+// the elected producers their exact shares of the total reward, in this order.
+func CheckCoinbaseRewards(coinbase *Transaction, totalReward Fixed64, elected []*Producer) error {
+	rewards := CalcCoinbaseRewards(totalReward, config.Parameters.ChainParam)
+	delegates, err := CalcDelegateRewards(rewards.Delegate, elected)
+	if err != nil {
+		return err
+	}
+	if len(coinbase.Outputs) != 2+len(delegates) {
+		return fmt.Errorf("coinbase outputs count should be %d", 2+len(delegates))
+	}
+	foundation, miner := coinbase.Outputs[0], coinbase.Outputs[1]
+	if !foundation.ProgramHash.IsEqual(FoundationAddress) || foundation.Value != rewards.Foundation {
+		return errors.New("reward to foundation in coinbase not correct")
+	}
+	if miner.Value != rewards.Miner {
+		return errors.New("reward to miner in coinbase not correct")
+	}
+	for i, delegate := range delegates {
+		output := coinbase.Outputs[2+i]
+		if !output.ProgramHash.IsEqual(delegate.ProgramHash) || output.Value != delegate.Amount {
+			return errors.New("reward to delegate in coinbase not correct")
+		}
+	}
 	return nil
 }
 
@@ -186,7 +260,7 @@ func PowCheckBlockContext(block *Block, prevNode *BlockNode, ledger *Ledger) err
 	}
 
 	for _, tx := range block.Transactions[1:] {
-		if !IsFinalizedTransaction(tx, block.Height) {
+		if !IsFinalizedTransaction(tx, block.Height, medianTime) {
 			return errors.New("block contains unfinalized transaction")
 		}
 	}
@@ -217,25 +291,134 @@ func CheckProofOfWork(header *Header, powLimit *big.Int) error {
 	return nil
 }
 
-func IsFinalizedTransaction(msgTx *Transaction, blockHeight uint32) bool {
+// IsLockTimeActivated returns if time based lock times and the MaxUint32 final
+// sequence are activated at the block height.
+func IsLockTimeActivated(blockHeight uint32) bool {
+	return blockHeight >= config.Parameters.ChainParam.LockTimeActivationHeight
+}
+
+// isSameLockTimeType returns if both lock times are block heights or both are
+// Unix timestamps.
+func isSameLockTimeType(lockTime1, lockTime2 uint32) bool {
+	return (lockTime1 < LockTimeThreshold) == (lockTime2 < LockTimeThreshold)
+}
+
+// IsRelativeLockTimeActivated returns if the input sequences are interpreted as
+// relative lock times at the block height.
+func IsRelativeLockTimeActivated(blockHeight uint32) bool {
+	return blockHeight >= config.Parameters.ChainParam.RelativeLockTimeActivationHeight
+}
+
+// CalcSequenceLock calculates the sequence lock of the transaction, the block
+// heights of the referenced outputs are read from the store.
+func CalcSequenceLock(txn *Transaction) (*SequenceLock, error) {
+	lock := &SequenceLock{Seconds: -1, BlockHeight: -1}
+	if txn.IsCoinBaseTx() {
+		return lock, nil
+	}
+
+	for _, input := range txn.Inputs {
+		sequence := input.Sequence
+		if sequence&SequenceLockTimeDisabled != 0 ||
+			sequence&SequenceLockTimeEnabled == 0 {
+			continue
+		}
+
+		_, height, err := DefaultLedger.Store.GetTransaction(input.Previous.TxID)
+		if err != nil {
+			return nil, errors.New("referenced transaction not found")
+		}
+
+		relativeLock := int64(sequence & SequenceLockTimeMask)
+		if sequence&SequenceLockTimeIsSeconds != 0 {
+			// The time is relative to the past median time of the block
+			// before the one containing the referenced output.
+			prevHeight := height
+			if prevHeight > 0 {
+				prevHeight--
+			}
+			medianTime, err := calcPastMedianTimeByHeight(prevHeight)
+			if err != nil {
+				return nil, err
+			}
+			seconds := medianTime.Unix() + relativeLock<<SequenceLockTimeGranularity - 1
+			if seconds > lock.Seconds {
+				lock.Seconds = seconds
+			}
+		} else {
+			blockHeight := int64(height) + relativeLock - 1
+			if blockHeight > lock.BlockHeight {
+				lock.BlockHeight = blockHeight
+			}
+		}
+	}
+	return lock, nil
+}
+
+// IsSequenceLockActive checks if the sequence lock allows the transaction to be
+// included in the block of the given height, medianTime is the past median time
+// of the previous block.
+func IsSequenceLockActive(lock *SequenceLock, blockHeight uint32, medianTime time.Time) bool {
+	return lock.Seconds < medianTime.Unix() && lock.BlockHeight < int64(blockHeight)
+}
+
+// CheckTransactionSequenceLock checks the relative lock times of the inputs
+// allow the transaction to be included in the block of the given height.
+func CheckTransactionSequenceLock(txn *Transaction, blockHeight uint32, medianTime time.Time) error {
+	if !IsRelativeLockTimeActivated(blockHeight) {
+		return nil
+	}
+	lock, err := CalcSequenceLock(txn)
+	if err != nil {
+		return err
+	}
+	if !IsSequenceLockActive(lock, blockHeight, medianTime) {
+		return errors.New("transaction sequence locks not met")
+	}
+	return nil
+}
+
+// IsFinalizedTransaction checks if the transaction is finalized in the block of
+// the given height, medianTime is the past median time of the previous block.
+func IsFinalizedTransaction(msgTx *Transaction, blockHeight uint32, medianTime time.Time) bool {
 	// Lock time of zero means the transaction is finalized.
 	lockTime := msgTx.LockTime
 	if lockTime == 0 {
 		return true
 	}
 
-	//FIXME only height
-	if lockTime < blockHeight {
-		return true
+	// Before the activation, the lock time is always a block height.
+	if !IsLockTimeActivated(blockHeight) {
+		if lockTime < blockHeight {
+			return true
+		}
+	} else {
+		blockTimeOrHeight := int64(blockHeight)
+		if lockTime >= LockTimeThreshold {
+			blockTimeOrHeight = medianTime.Unix()
+		}
+		if int64(lockTime) < blockTimeOrHeight {
+			return true
+		}
 	}
 
 	// At this point, the transaction's lock time hasn't occurred yet, but
 	// the transaction might still be finalized if the sequence number
 	// for all transaction inputs is maxed out.
 	for _, txIn := range msgTx.Inputs {
-		if txIn.Sequence != math.MaxUint16 {
+		if !IsFinalSequence(txIn.Sequence, blockHeight) {
 			return false
 		}
 	}
 	return true
 }
+
+// IsFinalSequence returns if the input sequence is final in the block of the
+// given height, the lock time of a transaction is ignored if all of its inputs
+// are final. Before the activation inputs of MaxUint16 sequence are final.
+func IsFinalSequence(sequence uint32, blockHeight uint32) bool {
+	if !IsLockTimeActivated(blockHeight) {
+		return sequence == math.MaxUint16
+	}
+	return sequence == MaxTxInSequenceNum
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/wuyazero/Elastos.ELA/core"
 	"github.com/wuyazero/Elastos.ELA/log"
@@ -84,3 +86,61 @@ func TestCheckBlockSanity(t *testing.T) {
 		t.Error(err.Error())
 	}
 }
+
+func TestIsFinalizedTransaction(t *testing.T) {
+	activationHeight := config.Parameters.ChainParam.LockTimeActivationHeight
+	config.Parameters.ChainParam.LockTimeActivationHeight = 100
+	defer func() {
+		config.Parameters.ChainParam.LockTimeActivationHeight = activationHeight
+	}()
+
+	medianTime := time.Unix(1600000000, 0)
+	tests := []struct {
+		name      string
+		lockTime  uint32
+		sequence  uint32
+		height    uint32
+		finalized bool
+	}{
+		{"zero lock time", 0, 0, 50, true},
+
+		// before the activation
+		{"height reached", 49, 0, 50, true},
+		{"height not reached", 50, 0, 50, false},
+		{"height not reached final sequence", 50, math.MaxUint16, 50, true},
+		{"height not reached new final sequence", 50, math.MaxUint32, 50, false},
+		{"timestamp as height", 1599999999, 0, 50, false},
+
+		// after the activation
+		{"activated height reached", 199, 0, 200, true},
+		{"activated height not reached", 200, 0, 200, false},
+		{"activated final sequence", 200, math.MaxUint32, 200, true},
+		{"activated old final sequence", 200, math.MaxUint16, 200, false},
+		{"max height not reached", LockTimeThreshold - 1, 0, 200, false},
+		{"time reached", 1599999999, 0, 200, true},
+		{"time not reached", 1600000000, 0, 200, false},
+		{"time not reached final sequence", 1600000001, math.MaxUint32, 200, true},
+		{"threshold time reached", LockTimeThreshold, 0, 200, true},
+	}
+
+	for _, test := range tests {
+		tx := &core.Transaction{
+			LockTime: test.lockTime,
+			Inputs: []*core.Input{
+				{Sequence: test.sequence},
+				{Sequence: test.sequence},
+			},
+		}
+		assert.Equal(t, test.finalized, IsFinalizedTransaction(tx, test.height, medianTime), test.name)
+	}
+
+	// all inputs must be final
+	tx := &core.Transaction{
+		LockTime: 200,
+		Inputs: []*core.Input{
+			{Sequence: math.MaxUint32},
+			{Sequence: math.MaxUint32 - 1},
+		},
+	}
+	assert.False(t, IsFinalizedTransaction(tx, 200, medianTime))
+}
@@ -116,7 +116,7 @@ func (c *ChainStore) PersistUnspendUTXOs(b *Block) error {
 	curHeight := b.Header.Height
 
 	for _, txn := range b.Transactions {
-		if txn.TxType == RegisterAsset {
+		if txn.TxType == RegisterAsset && !isAssetActivated(curHeight) {
 			continue
 		}
 
@@ -213,7 +213,7 @@ func (c *ChainStore) RollbackUnspendUTXOs(b *Block) error {
 	unspendUTXOs := make(map[Uint168]map[Uint256]map[uint32][]*UTXO)
 	height := b.Header.Height
 	for _, txn := range b.Transactions {
-		if txn.TxType == RegisterAsset {
+		if txn.TxType == RegisterAsset && !isAssetActivated(height) {
 			continue
 		}
 		for index, output := range txn.Outputs {
@@ -302,7 +302,7 @@ func (c *ChainStore) PersistTransactions(b *Block) error {
 		}
 		if txn.TxType == RegisterAsset {
 			regPayload := txn.Payload.(*PayloadRegisterAsset)
-			if err := c.PersistAsset(txn.Hash(), regPayload.Asset); err != nil {
+			if err := c.PersistAsset(registeredAssetID(b, txn), regPayload.Asset); err != nil {
 				return err
 			}
 		}
@@ -311,6 +311,19 @@ func (c *ChainStore) PersistTransactions(b *Block) error {
 			for _, hash := range witPayload.SideChainTransactionHashes {
 				c.PersistSidechainTx(hash)
 			}
+			if err := c.PersistWithdraw(txn, b.Header.Height); err != nil {
+				return err
+			}
+		}
+		if txn.TxType == Record {
+			if err := c.PersistRecord(txn, b.Header.Height); err != nil {
+				return err
+			}
+		}
+		if txn.TxType == SideChainPow {
+			if err := c.PersistSideChainPow(txn, b.Header.Height); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -322,7 +335,7 @@ func (c *ChainStore) RollbackTransactions(b *Block) error {
 			return err
 		}
 		if txn.TxType == RegisterAsset {
-			if err := c.RollbackAsset(txn.Hash()); err != nil {
+			if err := c.RollbackAsset(registeredAssetID(b, txn)); err != nil {
 				return err
 			}
 		}
@@ -333,12 +346,131 @@ func (c *ChainStore) RollbackTransactions(b *Block) error {
 					return err
 				}
 			}
+			if err := c.RollbackWithdraw(txn, b.Header.Height); err != nil {
+				return err
+			}
+		}
+		if txn.TxType == Record {
+			if err := c.RollbackRecord(txn, b.Header.Height); err != nil {
+				return err
+			}
+		}
+		if txn.TxType == SideChainPow {
+			if err := c.RollbackSideChainPow(txn); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// newAssetSupplyChanges returns the accumulator of the supply changes of the
+// assets made by a block.
+func (c *ChainStore) newAssetSupplyChanges() *stateChanges {
+	return newStateChanges(func(key string) (interface{}, error) {
+		assetID, err := Uint256FromBytes([]byte(key))
+		if err != nil {
+			return nil, err
+		}
+		return c.GetAssetSupply(*assetID)
+	})
+}
+
+// commitAssetSupplies writes the accumulated supply changes to the batch.
+func (c *ChainStore) commitAssetSupplies(supplies *stateChanges) error {
+	return supplies.commit(
+		func(key string, state interface{}) error {
+			assetID, err := Uint256FromBytes([]byte(key))
+			if err != nil {
+				return err
+			}
+			return c.PersistAssetSupply(*assetID, state.(*AssetSupply))
+		},
+		func(key string) error {
+			assetID, err := Uint256FromBytes([]byte(key))
+			if err != nil {
+				return err
+			}
+			return c.RollbackAssetSupply(*assetID)
+		})
+}
+
+// PersistAssetSupplies updates the supply states of the assets registered,
+// minted or burned in the block.
+func (c *ChainStore) PersistAssetSupplies(b *Block) error {
+	// the native asset registered in the genesis block and the assets
+	// registered before the activation have no supply state
+	if !isAssetActivated(b.Header.Height) {
+		return nil
+	}
+
+	supplies := c.newAssetSupplyChanges()
+	for _, txn := range b.Transactions {
+		assetID, amount, ok := getSupplyChange(txn)
+		if !ok {
+			continue
+		}
+		if payload, ok := txn.Payload.(*PayloadRegisterAsset); ok {
+			supplies.put(string(assetID[:]), &AssetSupply{
+				Controller: payload.Controller,
+				MaxSupply:  payload.Asset.MaxSupply,
+			})
+		}
+		state, err := supplies.get(string(assetID[:]))
+		if err != nil {
+			return err
+		}
+		supply := state.(*AssetSupply)
+		if amount > 0 {
+			supply.Issued += amount
+		} else {
+			supply.Burned -= amount
+		}
+	}
+	return c.commitAssetSupplies(supplies)
+}
+
+// RollbackAssetSupplies reverts the supply changes made by the block.
+func (c *ChainStore) RollbackAssetSupplies(b *Block) error {
+	if !isAssetActivated(b.Header.Height) {
+		return nil
+	}
+
+	supplies := c.newAssetSupplyChanges()
+	for _, txn := range b.Transactions {
+		assetID, amount, ok := getSupplyChange(txn)
+		if !ok {
+			continue
+		}
+		if txn.TxType == RegisterAsset {
+			supplies.remove(string(assetID[:]))
+			continue
+		}
+		state, err := supplies.get(string(assetID[:]))
+		if err != nil {
+			return err
+		}
+		supply := state.(*AssetSupply)
+		if amount > 0 {
+			supply.Issued -= amount
+		} else {
+			supply.Burned += amount
+		}
+	}
+	return c.commitAssetSupplies(supplies)
+}
+
+// registeredAssetID returns the ID of the asset registered by txn, the native
+// asset registered in the genesis block and the assets registered before the
+// activation are identified by the transaction hash.
+func registeredAssetID(b *Block, txn *Transaction) Uint256 {
+	if !isAssetActivated(b.Header.Height) {
+		return txn.Hash()
+	}
+	return txn.Payload.(*PayloadRegisterAsset).AssetID()
+}
+
 func (c *ChainStore) RollbackTransaction(txn *Transaction) error {
 
 	key := new(bytes.Buffer)
@@ -375,7 +507,7 @@ func (c *ChainStore) PersistUnspend(b *Block) error {
 	unspentPrefix := []byte{byte(IX_Unspent)}
 	unspents := make(map[Uint256][]uint16)
 	for _, txn := range b.Transactions {
-		if txn.TxType == RegisterAsset {
+		if txn.TxType == RegisterAsset && !isAssetActivated(b.Header.Height) {
 			continue
 		}
 		txnHash := txn.Hash()
@@ -428,7 +560,7 @@ func (c *ChainStore) RollbackUnspend(b *Block) error {
 	unspentPrefix := []byte{byte(IX_Unspent)}
 	unspents := make(map[Uint256][]uint16)
 	for _, txn := range b.Transactions {
-		if txn.TxType == RegisterAsset {
+		if txn.TxType == RegisterAsset && !isAssetActivated(b.Header.Height) {
 			continue
 		}
 		// remove all utxos created by this transaction
@@ -170,6 +170,17 @@ func (c *ChainStore) InitWithGenesisBlock(genesisBlock *Block) (uint32, error) {
 	c.currentBlockHeight, err = ReadUint32(r)
 	endHeight := c.currentBlockHeight
 
+	// index the blocks persisted before the indexes existed
+	if err := c.reindex(SYS_RecordIndex, "records", endHeight, c.PersistRecord); err != nil {
+		return 0, err
+	}
+	if err := c.reindex(SYS_SideChainPowIndex, "side chain pows", endHeight, c.PersistSideChainPow); err != nil {
+		return 0, err
+	}
+	if err := c.reindex(SYS_WithdrawIndex, "withdraws", endHeight, c.PersistWithdraw); err != nil {
+		return 0, err
+	}
+
 	startHeight := uint32(0)
 	if endHeight > MinMemoryNodes {
 		startHeight = endHeight - MinMemoryNodes
@@ -365,6 +376,42 @@ func (c *ChainStore) GetAsset(hash Uint256) (*Asset, error) {
 	return asset, nil
 }
 
+// the supply state is stored next to the asset, with a suffix appended to the
+// asset key.
+func getAssetSupplyKey(assetId Uint256) []byte {
+	key := []byte{byte(ST_Info)}
+	key = append(key, assetId.Bytes()...)
+	return append(key, assetSupplySuffix)
+}
+
+func (c *ChainStore) PersistAssetSupply(assetId Uint256, supply *AssetSupply) error {
+	w := new(bytes.Buffer)
+	if err := supply.Serialize(w); err != nil {
+		return err
+	}
+
+	c.BatchPut(getAssetSupplyKey(assetId), w.Bytes())
+	return nil
+}
+
+func (c *ChainStore) GetAssetSupply(assetId Uint256) (*AssetSupply, error) {
+	data, err := c.Get(getAssetSupplyKey(assetId))
+	if err != nil {
+		return nil, err
+	}
+
+	supply := new(AssetSupply)
+	if err := supply.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return supply, nil
+}
+
+func (c *ChainStore) RollbackAssetSupply(assetId Uint256) error {
+	c.BatchDelete(getAssetSupplyKey(assetId))
+	return nil
+}
+
 func (c *ChainStore) PersistSidechainTx(sidechainTxHash Uint256) {
 	key := []byte{byte(IX_SideChain_Tx)}
 	key = append(key, sidechainTxHash.Bytes()...)
@@ -383,6 +430,14 @@ func (c *ChainStore) GetSidechainTx(sidechainTxHash Uint256) (byte, error) {
 	return data[0], nil
 }
 
+func (c *ChainStore) PersistFeeEstimator(data []byte) error {
+	return c.Put([]byte{byte(SYS_FeeEstimator)}, data)
+}
+
+func (c *ChainStore) GetFeeEstimator() ([]byte, error) {
+	return c.Get([]byte{byte(SYS_FeeEstimator)})
+}
+
 func (c *ChainStore) GetTransaction(txId Uint256) (*Transaction, uint32, error) {
 	key := append([]byte{byte(DATA_Transaction)}, txId.Bytes()...)
 	value, err := c.Get(key)
@@ -405,7 +460,7 @@ func (c *ChainStore) GetTransaction(txId Uint256) (*Transaction, uint32, error)
 }
 
 func (c *ChainStore) GetTxReference(tx *Transaction) (map[*Input]*Output, error) {
-	if tx.TxType == RegisterAsset {
+	if tx.TxType == RegisterAsset && !isAssetActivated(c.GetHeight()+1) {
 		return nil, nil
 	}
 	//UTXO input /  Outputs
@@ -490,6 +545,10 @@ func (c *ChainStore) rollback(b *Block) error {
 	c.RollbackTrimmedBlock(b)
 	c.RollbackBlockHash(b)
 	c.RollbackTransactions(b)
+	c.RollbackAssetSupplies(b)
+	c.RollbackProducers(b)
+	c.RollbackArbitrators(b)
+	c.RollbackSideChains(b)
 	c.RollbackUnspendUTXOs(b)
 	c.RollbackUnspend(b)
 	c.RollbackCurrentBlock(b)
@@ -500,7 +559,10 @@ func (c *ChainStore) rollback(b *Block) error {
 	c.currentBlockHeight = b.Header.Height - 1
 	c.mu.Unlock()
 
-	DefaultLedger.Blockchain.BCEvents.Notify(events.EventRollbackTransaction, b)
+	// the subscribers are called in the order the blocks are rolled back and
+	// persisted, they must not wait for the lock of the blockchain held by
+	// the caller of RollbackBlock and SaveBlock
+	DefaultLedger.Blockchain.BCEvents.NotifySync(events.EventRollbackTransaction, b)
 
 	return nil
 }
@@ -516,6 +578,18 @@ func (c *ChainStore) persist(b *Block) error {
 	if err := c.PersistTransactions(b); err != nil {
 		return err
 	}
+	if err := c.PersistAssetSupplies(b); err != nil {
+		return err
+	}
+	if err := c.PersistProducers(b); err != nil {
+		return err
+	}
+	if err := c.PersistArbitrators(b); err != nil {
+		return err
+	}
+	if err := c.PersistSideChains(b); err != nil {
+		return err
+	}
 	if err := c.PersistUnspendUTXOs(b); err != nil {
 		return err
 	}
@@ -584,7 +658,7 @@ func (c *ChainStore) persistBlock(block *Block) {
 	c.currentBlockHeight = block.Header.Height
 	c.mu.Unlock()
 
-	DefaultLedger.Blockchain.BCEvents.Notify(events.EventBlockPersistCompleted, block)
+	DefaultLedger.Blockchain.BCEvents.NotifySync(events.EventBlockPersistCompleted, block)
 }
 
 func (c *ChainStore) GetUnspent(txid Uint256, index uint16) (*Output, error) {
@@ -801,6 +875,10 @@ func (c *ChainStore) GetAssets() map[Uint256]*Asset {
 
 	iter := c.NewIterator([]byte{byte(ST_Info)})
 	for iter.Next() {
+		// skip the supply states of the assets
+		if len(iter.Key()) != 1+UINT256SIZE {
+			continue
+		}
 		rk := bytes.NewReader(iter.Key())
 
 		// read prefix
@@ -1,12 +1,16 @@
 package blockchain
 
 import (
+	"bytes"
 	"container/list"
+	"math"
 	"testing"
 
+	"github.com/wuyazero/Elastos.ELA/config"
 	ela "github.com/wuyazero/Elastos.ELA/core"
 
 	"github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
 )
 
 var testChainStore *ChainStore
@@ -141,6 +145,774 @@ func TestChainStore_IsSidechainTxHashDuplicate(t *testing.T) {
 	}
 }
 
+func TestChainStore_AssetSupplies(t *testing.T) {
+	if testChainStore == nil {
+		t.Error("Chainstore init failed")
+	}
+	activationHeight := config.Parameters.ChainParam.AssetActivationHeight
+	config.Parameters.ChainParam.AssetActivationHeight = 0
+	defer func() {
+		config.Parameters.ChainParam.AssetActivationHeight = activationHeight
+	}()
+
+	payload := &ela.PayloadRegisterAsset{
+		Asset: ela.Asset{
+			Name:      "TOKEN",
+			Precision: 0x08,
+			MaxSupply: 1000,
+		},
+		Amount:     100,
+		Controller: common.Uint168{0x12, 1, 2, 3},
+	}
+	assetID := payload.AssetID()
+	register := &ela.Transaction{TxType: ela.RegisterAsset, Payload: payload}
+	block1 := &ela.Block{
+		Header:       ela.Header{Height: 1},
+		Transactions: []*ela.Transaction{register},
+	}
+
+	// 1. Persist the registered asset
+	testChainStore.PersistAsset(assetID, payload.Asset)
+	if err := testChainStore.PersistAssetSupplies(block1); err != nil {
+		t.Error("Persist asset supplies failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	supply, err := testChainStore.GetAssetSupply(assetID)
+	if err != nil {
+		t.Fatal("Not found the asset supply")
+	}
+	if supply.Controller != payload.Controller || supply.MaxSupply != 1000 ||
+		supply.Issued != 100 || supply.Burned != 0 {
+		t.Error("Asset supply matched wrong value")
+	}
+
+	// 2. The supply state is not listed as an asset
+	if _, ok := testChainStore.GetAssets()[assetID]; !ok {
+		t.Error("Not found the registered asset")
+	}
+	for id, asset := range testChainStore.GetAssets() {
+		if asset.Name == payload.Asset.Name && id != assetID {
+			t.Error("Supply state listed as an asset")
+		}
+	}
+
+	// 3. Mint and burn in the same block are accumulated
+	block2 := &ela.Block{
+		Header: ela.Header{Height: 2},
+		Transactions: []*ela.Transaction{
+			{TxType: ela.MintAsset, Payload: &ela.PayloadMintAsset{AssetID: assetID, Amount: 50}},
+			{TxType: ela.MintAsset, Payload: &ela.PayloadMintAsset{AssetID: assetID, Amount: 20}},
+			{TxType: ela.BurnAsset, Payload: &ela.PayloadBurnAsset{AssetID: assetID, Amount: 30}},
+		},
+	}
+	if err := testChainStore.PersistAssetSupplies(block2); err != nil {
+		t.Error("Persist asset supplies failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	supply, err = testChainStore.GetAssetSupply(assetID)
+	if err != nil {
+		t.Fatal("Not found the asset supply")
+	}
+	if supply.Issued != 170 || supply.Burned != 30 || supply.Circulating() != 140 {
+		t.Error("Asset supply matched wrong value")
+	}
+
+	// 4. Rollback the mint and burn
+	if err := testChainStore.RollbackAssetSupplies(block2); err != nil {
+		t.Error("Rollback asset supplies failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	supply, err = testChainStore.GetAssetSupply(assetID)
+	if err != nil {
+		t.Fatal("Not found the asset supply")
+	}
+	if supply.Issued != 100 || supply.Burned != 0 {
+		t.Error("Asset supply matched wrong value")
+	}
+
+	// 5. Rollback the registration
+	if err := testChainStore.RollbackAssetSupplies(block1); err != nil {
+		t.Error("Rollback asset supplies failed", err)
+	}
+	testChainStore.RollbackAsset(assetID)
+	testChainStore.BatchCommit()
+
+	if _, err := testChainStore.GetAssetSupply(assetID); err == nil {
+		t.Error("Found the asset supply which should been deleted")
+	}
+}
+
+func TestChainStore_Records(t *testing.T) {
+	if testChainStore == nil {
+		t.Error("Chainstore init failed")
+	}
+
+	newRecord := func(recordType string, data string) *ela.Transaction {
+		return &ela.Transaction{
+			TxType:  ela.Record,
+			Payload: &ela.PayloadRecord{RecordType: recordType, RecordData: []byte(data)},
+		}
+	}
+	record1 := newRecord("notary", "document1")
+	record2 := newRecord("notary", "document2")
+	record3 := newRecord("notarys", "document1")
+	block1 := &ela.Block{
+		Header:       ela.Header{Height: 1},
+		Transactions: []*ela.Transaction{record1, record3},
+	}
+	block2 := &ela.Block{
+		Header:       ela.Header{Height: 2},
+		Transactions: []*ela.Transaction{record2},
+	}
+
+	// 1. Persist the records
+	if err := testChainStore.PersistTransactions(block1); err != nil {
+		t.Error("Persist transactions failed", err)
+	}
+	if err := testChainStore.PersistTransactions(block2); err != nil {
+		t.Error("Persist transactions failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	// 2. Records are found by type in height order
+	records, err := testChainStore.GetRecordsByType("notary", 0, 10)
+	if err != nil {
+		t.Fatal("Get records by type failed", err)
+	}
+	if len(records) != 2 || records[0].TxID != record1.Hash() || records[0].Height != 1 ||
+		records[1].TxID != record2.Hash() || records[1].Height != 2 {
+		t.Error("Records matched wrong value")
+	}
+	records, _ = testChainStore.GetRecordsByType("notary", 2, 2)
+	if len(records) != 1 || records[0].TxID != record2.Hash() {
+		t.Error("Records matched wrong value")
+	}
+	records, _ = testChainStore.GetRecordsByType("notarys", 0, 10)
+	if len(records) != 1 || records[0].TxID != record3.Hash() {
+		t.Error("Records matched wrong value")
+	}
+
+	// 3. Records are found by data hash
+	records, err = testChainStore.GetRecordsByHash(GetRecordDataHash([]byte("document1")))
+	if err != nil {
+		t.Fatal("Get records by hash failed", err)
+	}
+	if len(records) != 2 {
+		t.Error("Records matched wrong value")
+	}
+	for _, record := range records {
+		if record.Height != 1 || (record.TxID != record1.Hash() && record.TxID != record3.Hash()) {
+			t.Error("Records matched wrong value")
+		}
+	}
+
+	// 4. Rollback the records
+	if err := testChainStore.RollbackTransactions(block2); err != nil {
+		t.Error("Rollback transactions failed", err)
+	}
+	if err := testChainStore.RollbackTransactions(block1); err != nil {
+		t.Error("Rollback transactions failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	records, _ = testChainStore.GetRecordsByType("notary", 0, 10)
+	if len(records) != 0 {
+		t.Error("Found the records which should been deleted")
+	}
+	records, _ = testChainStore.GetRecordsByHash(GetRecordDataHash([]byte("document2")))
+	if len(records) != 0 {
+		t.Error("Found the records which should been deleted")
+	}
+}
+
+func TestChainStore_ReindexRecords(t *testing.T) {
+	if testChainStore == nil {
+		t.Error("Chainstore init failed")
+	}
+
+	// 1. Blocks persisted before the record index existed
+	record := &ela.Transaction{
+		TxType:  ela.Record,
+		Payload: &ela.PayloadRecord{RecordType: "reindex", RecordData: []byte("document")},
+	}
+	blocks := []*ela.Block{
+		{Header: ela.Header{Height: 0}, Transactions: []*ela.Transaction{}},
+		{Header: ela.Header{Height: 1}, Transactions: []*ela.Transaction{record}},
+	}
+	testChainStore.NewBatch()
+	for _, block := range blocks {
+		testChainStore.PersistTrimmedBlock(block)
+		testChainStore.PersistBlockHash(block)
+		for _, txn := range block.Transactions {
+			testChainStore.PersistTransaction(txn, block.Header.Height)
+		}
+	}
+	testChainStore.BatchDelete([]byte{byte(SYS_RecordIndex)})
+	testChainStore.BatchCommit()
+	records, _ := testChainStore.GetRecordsByType("reindex", 0, 10)
+	if len(records) != 0 {
+		t.Error("Found the records not indexed yet")
+	}
+
+	// 2. The records are indexed on startup
+	if err := testChainStore.reindex(SYS_RecordIndex, "records", 1, testChainStore.PersistRecord); err != nil {
+		t.Fatal("Reindex records failed", err)
+	}
+	records, _ = testChainStore.GetRecordsByType("reindex", 0, 10)
+	if len(records) != 1 || records[0].TxID != record.Hash() || records[0].Height != 1 {
+		t.Error("Records matched wrong value")
+	}
+	records, _ = testChainStore.GetRecordsByHash(GetRecordDataHash([]byte("document")))
+	if len(records) != 1 || records[0].TxID != record.Hash() {
+		t.Error("Records matched wrong value")
+	}
+
+	// 3. The index is built only once
+	testChainStore.NewBatch()
+	testChainStore.RollbackRecord(record, 1)
+	testChainStore.BatchCommit()
+	if err := testChainStore.reindex(SYS_RecordIndex, "records", 1, testChainStore.PersistRecord); err != nil {
+		t.Fatal("Reindex records failed", err)
+	}
+	records, _ = testChainStore.GetRecordsByType("reindex", 0, 10)
+	if len(records) != 0 {
+		t.Error("Records reindexed twice")
+	}
+}
+
+func TestChainStore_Producers(t *testing.T) {
+	if testChainStore == nil {
+		t.Error("Chainstore init failed")
+	}
+
+	_, public, _ := crypto.GenerateKeyPair()
+	publicKey, _ := public.EncodePoint(true)
+	register := &ela.Transaction{
+		TxType: ela.RegisterProducer,
+		Payload: &ela.PayloadRegisterProducer{
+			PublicKey: publicKey,
+			NickName:  "producer",
+			Url:       "https://producer.org",
+		},
+		Outputs: []*ela.Output{{Value: 5000}},
+	}
+	vote := &ela.Transaction{
+		TxType:  ela.VoteProducer,
+		Payload: &ela.PayloadVoteProducer{Candidates: [][]byte{publicKey}},
+		Outputs: []*ela.Output{{Value: 30}, {Value: 20}},
+	}
+	vote2 := &ela.Transaction{
+		TxType:  ela.VoteProducer,
+		Payload: &ela.PayloadVoteProducer{Candidates: [][]byte{publicKey}},
+		Outputs: []*ela.Output{{Value: 40}},
+	}
+	newSpend := func(outPoint *ela.OutPoint) *ela.Transaction {
+		return &ela.Transaction{
+			TxType:  ela.TransferAsset,
+			Payload: &ela.PayloadTransferAsset{},
+			Inputs:  []*ela.Input{{Previous: *outPoint}},
+		}
+	}
+	cancel := &ela.Transaction{
+		TxType:  ela.CancelProducer,
+		Payload: &ela.PayloadCancelProducer{PublicKey: publicKey},
+	}
+	reregister := &ela.Transaction{
+		TxType: ela.RegisterProducer,
+		Payload: &ela.PayloadRegisterProducer{
+			PublicKey: publicKey,
+			NickName:  "producer",
+			Url:       "https://producer.org",
+		},
+		Outputs: []*ela.Output{{Value: 6000}},
+	}
+	blocks := []*ela.Block{
+		{Header: ela.Header{Height: 1}, Transactions: []*ela.Transaction{register}},
+		{Header: ela.Header{Height: 2}, Transactions: []*ela.Transaction{
+			vote, vote2, newSpend(ela.NewOutPoint(vote2.Hash(), 0))}},
+		{Header: ela.Header{Height: 3}, Transactions: []*ela.Transaction{
+			newSpend(ela.NewOutPoint(vote.Hash(), 1)), cancel}},
+		{Header: ela.Header{Height: 4}, Transactions: []*ela.Transaction{
+			newSpend(ela.NewOutPoint(register.Hash(), 0)), reregister}},
+		{Header: ela.Header{Height: 5}, Transactions: []*ela.Transaction{
+			newSpend(ela.NewOutPoint(vote.Hash(), 0))}},
+	}
+	checkProducer := func(votes common.Fixed64, registerHeight, cancelHeight uint32) {
+		p, err := testChainStore.GetProducer(publicKey)
+		if err != nil {
+			t.Fatal("Not found the producer")
+		}
+		deposit, amount := register, common.Fixed64(5000)
+		if registerHeight == 4 {
+			deposit, amount = reregister, 6000
+		}
+		if p.NickName != "producer" || p.DepositAmount != amount ||
+			p.RegisterHeight != registerHeight ||
+			!p.Deposit.IsEqual(*ela.NewOutPoint(deposit.Hash(), 0)) ||
+			p.Votes != votes || p.CancelHeight != cancelHeight {
+			t.Error("Producer matched wrong value")
+		}
+	}
+
+	// 1. Register the producer, vote for it with a vote spent in the same
+	// block, spend the change of a vote and cancel it, register it again after
+	// the deposit returned, then spend the vote
+	for i, block := range blocks {
+		if err := testChainStore.PersistTransactions(block); err != nil {
+			t.Error("Persist transactions failed", err)
+		}
+		if err := testChainStore.PersistProducers(block); err != nil {
+			t.Error("Persist producers failed", err)
+		}
+		testChainStore.BatchCommit()
+
+		switch i {
+		case 0:
+			checkProducer(0, 1, 0)
+		case 1:
+			checkProducer(30, 1, 0)
+		case 2:
+			checkProducer(30, 1, 3)
+		case 3:
+			checkProducer(30, 4, 0)
+		case 4:
+			checkProducer(0, 4, 0)
+		}
+	}
+	if len(testChainStore.GetProducers()) != 1 {
+		t.Error("Producers matched wrong value")
+	}
+	if v, err := testChainStore.GetVoteOutput(*ela.NewOutPoint(vote.Hash(), 0)); err != nil || v.Value != 30 {
+		t.Error("Vote output matched wrong value")
+	}
+
+	// 2. Rollback the blocks in reverse order
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if err := testChainStore.RollbackProducers(blocks[i]); err != nil {
+			t.Error("Rollback producers failed", err)
+		}
+		testChainStore.BatchCommit()
+		if err := testChainStore.RollbackTransactions(blocks[i]); err != nil {
+			t.Error("Rollback transactions failed", err)
+		}
+		testChainStore.BatchCommit()
+
+		switch i {
+		case 4:
+			checkProducer(30, 4, 0)
+		case 3:
+			checkProducer(30, 1, 3)
+		case 2:
+			checkProducer(30, 1, 0)
+		case 1:
+			checkProducer(0, 1, 0)
+		}
+	}
+	if _, err := testChainStore.Get(getProducerHistoryKey(publicKey, 4)); err == nil {
+		t.Error("Found the producer history which should been deleted")
+	}
+	if _, err := testChainStore.GetProducer(publicKey); err == nil {
+		t.Error("Found the producer which should been deleted")
+	}
+	if len(testChainStore.GetProducers()) != 0 {
+		t.Error("Found the producers which should been deleted")
+	}
+	if _, err := testChainStore.GetVoteOutput(*ela.NewOutPoint(vote.Hash(), 0)); err == nil {
+		t.Error("Found the vote output which should been deleted")
+	}
+}
+
+func TestChainStore_Arbitrators(t *testing.T) {
+	if testChainStore == nil {
+		t.Error("Chainstore init failed")
+	}
+
+	newArbitrators := func(count int) [][]byte {
+		arbitrators := make([][]byte, 0, count)
+		for i := 0; i < count; i++ {
+			_, public, _ := crypto.GenerateKeyPair()
+			publicKey, _ := public.EncodePoint(true)
+			arbitrators = append(arbitrators, publicKey)
+		}
+		return arbitrators
+	}
+	newBlock := func(height uint32, arbitrators [][]byte) *ela.Block {
+		return &ela.Block{
+			Header: ela.Header{Height: height},
+			Transactions: []*ela.Transaction{{
+				TxType:  ela.UpdateArbitrators,
+				Payload: &ela.PayloadUpdateArbitrators{Arbitrators: arbitrators},
+			}},
+		}
+	}
+	arbitrators1 := newArbitrators(3)
+	arbitrators2 := newArbitrators(5)
+	block1 := newBlock(10, arbitrators1)
+	block2 := newBlock(20, arbitrators2)
+
+	// 1. Persist the updates of the arbitrators
+	if err := testChainStore.PersistArbitrators(block1); err != nil {
+		t.Error("Persist arbitrators failed", err)
+	}
+	if err := testChainStore.PersistArbitrators(block2); err != nil {
+		t.Error("Persist arbitrators failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	// 2. The arbitrators of past heights are kept
+	checkArbitrators := func(height uint32, expected [][]byte) {
+		arbitrators, err := testChainStore.GetArbitrators(height)
+		if err != nil {
+			t.Fatal("Get arbitrators failed", err)
+		}
+		if len(arbitrators) != len(expected) {
+			t.Fatal("Arbitrators matched wrong value at height", height)
+		}
+		for i := range expected {
+			if !bytes.Equal(arbitrators[i], expected[i]) {
+				t.Error("Arbitrators matched wrong value at height", height)
+			}
+		}
+	}
+	// the configured arbitrators are used before the first update
+	if arbitrators, _ := testChainStore.GetArbitrators(9); len(arbitrators) > 0 &&
+		bytes.Equal(arbitrators[0], arbitrators1[0]) {
+		t.Error("Arbitrators matched wrong value at height", 9)
+	}
+	checkArbitrators(10, arbitrators1)
+	checkArbitrators(19, arbitrators1)
+	checkArbitrators(20, arbitrators2)
+	checkArbitrators(100, arbitrators2)
+	checkArbitrators(math.MaxUint32, arbitrators2)
+
+	// 3. Rollback the last update
+	if err := testChainStore.RollbackArbitrators(block2); err != nil {
+		t.Error("Rollback arbitrators failed", err)
+	}
+	testChainStore.BatchCommit()
+	checkArbitrators(100, arbitrators1)
+
+	if err := testChainStore.RollbackArbitrators(block1); err != nil {
+		t.Error("Rollback arbitrators failed", err)
+	}
+	testChainStore.BatchCommit()
+}
+
+func TestChainStore_SideChains(t *testing.T) {
+	if testChainStore == nil {
+		t.Error("Chainstore init failed")
+	}
+
+	programHash := common.Uint168{common.PrefixCrossChain, 1, 2, 3}
+	address, _ := programHash.ToAddress()
+	assetID := DefaultLedger.Blockchain.AssetID
+	genesisHash := common.Uint256{1, 2, 3}
+	register := &ela.Transaction{
+		TxType: ela.RegisterSideChain,
+		Payload: &ela.PayloadRegisterSideChain{
+			GenesisHash:             genesisHash,
+			GenesisAddress:          address,
+			Name:                    "side",
+			MinArbitratorSignatures: 3,
+		},
+	}
+	early := &ela.Transaction{
+		TxType: ela.TransferCrossChainAsset,
+		Payload: &ela.PayloadTransferCrossChainAsset{
+			CrossChainAddresses: []string{"address0"},
+			OutputIndexes:       []uint64{0},
+			CrossChainAmounts:   []common.Fixed64{40},
+		},
+		Outputs: []*ela.Output{
+			{AssetID: assetID, ProgramHash: programHash, Value: 50},
+			{AssetID: common.Uint256{9}, ProgramHash: programHash, Value: 1000},
+		},
+	}
+	deposit := &ela.Transaction{
+		TxType: ela.TransferCrossChainAsset,
+		Payload: &ela.PayloadTransferCrossChainAsset{
+			CrossChainAddresses: []string{"address1", "address2"},
+			OutputIndexes:       []uint64{0, 2},
+			CrossChainAmounts:   []common.Fixed64{90, 190},
+		},
+		Outputs: []*ela.Output{
+			{AssetID: assetID, ProgramHash: programHash, Value: 100},
+			{AssetID: assetID, ProgramHash: common.Uint168{0x21}, Value: 50},
+			{AssetID: assetID, ProgramHash: programHash, Value: 200},
+		},
+	}
+	withdraw := &ela.Transaction{
+		TxType: ela.WithdrawFromSideChain,
+		Payload: &ela.PayloadWithdrawFromSideChain{
+			GenesisBlockAddress: address,
+		},
+		Inputs: []*ela.Input{{Previous: *ela.NewOutPoint(deposit.Hash(), 2)}},
+		Outputs: []*ela.Output{
+			{AssetID: assetID, ProgramHash: common.Uint168{0x21}, Value: 120},
+			{AssetID: assetID, ProgramHash: programHash, Value: 70},
+		},
+	}
+	blocks := []*ela.Block{
+		{Header: ela.Header{Height: 1}, Transactions: []*ela.Transaction{early, register, deposit}},
+		{Header: ela.Header{Height: 2}, Transactions: []*ela.Transaction{withdraw}},
+	}
+	checkSideChain := func(deposited, withdrawn common.Fixed64) {
+		s, err := testChainStore.GetSideChain(genesisHash)
+		if err != nil {
+			t.Fatal("Not found the side chain")
+		}
+		if s.Name != "side" || s.GenesisAddress != address || s.MinArbitratorSignatures != 3 ||
+			s.RegisterHeight != 1 || s.Deposited != deposited || s.Withdrawn != withdrawn {
+			t.Error("Side chain matched wrong value")
+		}
+		s, err = testChainStore.GetSideChainByProgramHash(programHash)
+		if err != nil || s.GenesisHash != genesisHash {
+			t.Error("Not found the side chain by program hash")
+		}
+	}
+
+	// 1. Register the side chain after a deposit, which is counted as
+	// deposited without the other asset, with another deposit, then withdraw
+	// with a change
+	for _, block := range blocks {
+		if err := testChainStore.PersistTransactions(block); err != nil {
+			t.Error("Persist transactions failed", err)
+		}
+		if err := testChainStore.PersistSideChains(block); err != nil {
+			t.Error("Persist side chains failed", err)
+		}
+		testChainStore.BatchCommit()
+	}
+	checkSideChain(350, 130)
+	sideChains := testChainStore.GetSideChains()
+	if len(sideChains) != 1 || sideChains[0].Locked() != 220 {
+		t.Error("Side chains matched wrong value")
+	}
+
+	// 2. Rollback the blocks in reverse order
+	if err := testChainStore.RollbackSideChains(blocks[1]); err != nil {
+		t.Error("Rollback side chains failed", err)
+	}
+	testChainStore.BatchCommit()
+	checkSideChain(350, 0)
+
+	if err := testChainStore.RollbackSideChains(blocks[0]); err != nil {
+		t.Error("Rollback side chains failed", err)
+	}
+	testChainStore.BatchCommit()
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if err := testChainStore.RollbackTransactions(blocks[i]); err != nil {
+			t.Error("Rollback transactions failed", err)
+		}
+		testChainStore.BatchCommit()
+	}
+	if _, err := testChainStore.GetSideChain(genesisHash); err == nil {
+		t.Error("Found the side chain which should been deleted")
+	}
+	if _, err := testChainStore.GetSideChainByProgramHash(programHash); err == nil {
+		t.Error("Found the side chain which should been deleted")
+	}
+}
+
+func TestChainStore_SideChainPows(t *testing.T) {
+	if testChainStore == nil {
+		t.Error("Chainstore init failed")
+	}
+
+	genesisHash := common.Uint256{4, 5, 6}
+	newSideChainPow := func(sideHash common.Uint256, sideHeight uint32) *ela.Transaction {
+		return &ela.Transaction{
+			TxType: ela.SideChainPow,
+			Payload: &ela.PayloadSideChainPow{
+				SideBlockHash:   sideHash,
+				SideGenesisHash: genesisHash,
+				BlockHeight:     sideHeight,
+			},
+		}
+	}
+	pow1 := newSideChainPow(common.Uint256{1}, 10)
+	pow2 := newSideChainPow(common.Uint256{2}, 11)
+	block1 := &ela.Block{
+		Header:       ela.Header{Height: 1},
+		Transactions: []*ela.Transaction{pow2},
+	}
+	block2 := &ela.Block{
+		Header:       ela.Header{Height: 2},
+		Transactions: []*ela.Transaction{pow1},
+	}
+
+	// 1. Persist the sidechainpow transactions
+	if err := testChainStore.PersistTransactions(block1); err != nil {
+		t.Error("Persist transactions failed", err)
+	}
+	if err := testChainStore.PersistTransactions(block2); err != nil {
+		t.Error("Persist transactions failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	// 2. The anchored blocks are found in side block height order
+	pows, err := testChainStore.GetSideChainPows(genesisHash, 0, 100)
+	if err != nil {
+		t.Fatal("Get side chain pows failed", err)
+	}
+	if len(pows) != 2 ||
+		pows[0].SideBlockHash != (common.Uint256{1}) || pows[0].SideBlockHeight != 10 ||
+		pows[0].TxID != pow1.Hash() || pows[0].Height != 2 ||
+		pows[1].SideBlockHash != (common.Uint256{2}) || pows[1].SideBlockHeight != 11 ||
+		pows[1].TxID != pow2.Hash() || pows[1].Height != 1 {
+		t.Error("Side chain pows matched wrong value")
+	}
+	pows, _ = testChainStore.GetSideChainPows(genesisHash, 11, 11)
+	if len(pows) != 1 || pows[0].TxID != pow2.Hash() {
+		t.Error("Side chain pows matched wrong value")
+	}
+	pows, _ = testChainStore.GetSideChainPows(genesisHash, 0, 10)
+	if len(pows) != 1 || pows[0].TxID != pow1.Hash() {
+		t.Error("Side chain pows matched wrong value")
+	}
+	pows, _ = testChainStore.GetSideChainPows(common.Uint256{7, 8, 9}, 0, 100)
+	if len(pows) != 0 {
+		t.Error("Side chain pows matched wrong value")
+	}
+
+	// 3. Rollback the sidechainpow transactions
+	if err := testChainStore.RollbackTransactions(block2); err != nil {
+		t.Error("Rollback transactions failed", err)
+	}
+	if err := testChainStore.RollbackTransactions(block1); err != nil {
+		t.Error("Rollback transactions failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	pows, _ = testChainStore.GetSideChainPows(genesisHash, 0, 100)
+	if len(pows) != 0 {
+		t.Error("Found the side chain pows which should been deleted")
+	}
+}
+
+func TestChainStore_Withdraws(t *testing.T) {
+	if testChainStore == nil {
+		t.Error("Chainstore init failed")
+	}
+
+	programHash := common.Uint168{common.PrefixCrossChain, 4, 5, 6}
+	address, _ := programHash.ToAddress()
+	sideTx1, sideTx2 := common.Uint256{1, 1}, common.Uint256{2, 2}
+	withdraw1 := &ela.Transaction{
+		TxType: ela.WithdrawFromSideChain,
+		Payload: &ela.PayloadWithdrawFromSideChain{
+			BlockHeight:                1,
+			GenesisBlockAddress:        address,
+			SideChainTransactionHashes: []common.Uint256{sideTx1},
+		},
+	}
+	withdraw2 := &ela.Transaction{
+		TxType: ela.WithdrawFromSideChain,
+		Payload: &ela.PayloadWithdrawFromSideChain{
+			BlockHeight:                2,
+			GenesisBlockAddress:        address,
+			SideChainTransactionHashes: []common.Uint256{sideTx2},
+		},
+	}
+	block1 := &ela.Block{
+		Header:       ela.Header{Height: 1},
+		Transactions: []*ela.Transaction{withdraw1},
+	}
+	block2 := &ela.Block{
+		Header:       ela.Header{Height: 2},
+		Transactions: []*ela.Transaction{withdraw2},
+	}
+
+	// 1. Persist the withdraw transactions
+	if err := testChainStore.PersistTransactions(block1); err != nil {
+		t.Error("Persist transactions failed", err)
+	}
+	testChainStore.BatchCommit()
+	if err := testChainStore.PersistTransactions(block2); err != nil {
+		t.Error("Persist transactions failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	index, err := testChainStore.GetWithdraw(sideTx1)
+	if err != nil {
+		t.Fatal("Get withdraw failed", err)
+	}
+	if index.Status != WithdrawConfirmed || index.TxID != withdraw1.Hash() ||
+		index.Height != 1 || index.GenesisProgramHash != programHash {
+		t.Error("Withdraw matched wrong value")
+	}
+	withdraws, err := testChainStore.GetWithdraws(programHash, nil, 0)
+	if err != nil {
+		t.Fatal("Get withdraws failed", err)
+	}
+	if len(withdraws) != 2 ||
+		withdraws[0].SideChainTxHash != sideTx1 || withdraws[1].SideChainTxHash != sideTx2 {
+		t.Error("Withdraws matched wrong value")
+	}
+	withdraws, _ = testChainStore.GetWithdraws(programHash, nil, 1)
+	if len(withdraws) != 1 || withdraws[0].SideChainTxHash != sideTx1 {
+		t.Error("Withdraws matched wrong value")
+	}
+	withdraws, _ = testChainStore.GetWithdraws(programHash, &sideTx1, 0)
+	if len(withdraws) != 1 || withdraws[0].SideChainTxHash != sideTx2 {
+		t.Error("Withdraws matched wrong value")
+	}
+	withdraws, _ = testChainStore.GetWithdraws(programHash, &sideTx2, 0)
+	if len(withdraws) != 0 {
+		t.Error("Withdraws matched wrong value")
+	}
+	if _, err := testChainStore.GetWithdraws(programHash, &common.Uint256{3, 3}, 0); err == nil {
+		t.Error("Withdraws should not start with an unknown withdrawal")
+	}
+
+	// 2. Rollback the block, the withdrawal is kept as rolled back
+	if err := testChainStore.RollbackTransactions(block2); err != nil {
+		t.Error("Rollback transactions failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	index, _ = testChainStore.GetWithdraw(sideTx2)
+	if index == nil || index.Status != WithdrawRolledBack ||
+		index.TxID != withdraw2.Hash() || index.Height != 2 {
+		t.Error("Withdraw should be rolled back")
+	}
+
+	// 3. Confirm the withdrawal again at another height, it is listed once
+	block3 := &ela.Block{
+		Header:       ela.Header{Height: 3},
+		Transactions: []*ela.Transaction{withdraw2},
+	}
+	if err := testChainStore.PersistTransactions(block3); err != nil {
+		t.Error("Persist transactions failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	withdraws, _ = testChainStore.GetWithdraws(programHash, nil, 0)
+	if len(withdraws) != 2 || withdraws[1].SideChainTxHash != sideTx2 ||
+		withdraws[1].Status != WithdrawConfirmed || withdraws[1].Height != 3 {
+		t.Error("Withdraws matched wrong value")
+	}
+
+	// 4. Rollback all
+	if err := testChainStore.RollbackTransactions(block3); err != nil {
+		t.Error("Rollback transactions failed", err)
+	}
+	if err := testChainStore.RollbackTransactions(block1); err != nil {
+		t.Error("Rollback transactions failed", err)
+	}
+	testChainStore.BatchCommit()
+
+	withdraws, _ = testChainStore.GetWithdraws(programHash, nil, 0)
+	for _, w := range withdraws {
+		if w.Status != WithdrawRolledBack {
+			t.Error("Withdraw should be rolled back")
+		}
+	}
+}
+
 func TestChainStoreDone(t *testing.T) {
 	if testChainStore == nil {
 		t.Error("Chainstore init failed")
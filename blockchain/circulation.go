@@ -8,10 +8,82 @@ import (
 	"github.com/wuyazero/Elastos.ELA.Utility/common"
 )
 
-var (
-	OriginIssuanceAmount   = 3300 * 10000 * 100000000
-	InflationPerYear = OriginIssuanceAmount * 4 / 100
-	BlockGenerateInterval  = int64(config.Parameters.ChainParam.TargetTimePerBlock / time.Second)
-	GeneratedBlocksPerYear = 365 * 24 * 60 * 60 / BlockGenerateInterval
-	RewardAmountPerBlock   = common.Fixed64(float64(InflationPerYear) / float64(GeneratedBlocksPerYear))
-)
+// CalcBlockSubsidy returns the amount newly issued by the block at the height,
+// the genesis block issues the genesis supply and has no subsidy.
+func CalcBlockSubsidy(height uint32, params *config.ChainParams) common.Fixed64 {
+	subsidy, _ := calcIssuance(height, params)
+	return subsidy
+}
+
+// CalcSupply returns the amount issued by the blocks until the height, which
+// is the genesis supply plus the subsidies.
+func CalcSupply(height uint32, params *config.ChainParams) common.Fixed64 {
+	_, supply := calcIssuance(height, params)
+	return supply
+}
+
+// GetSubsidyInterval returns the number of blocks of which the subsidy is the
+// same, a year for the inflation schedule or an era for the halving schedule.
+func GetSubsidyInterval(params *config.ChainParams) uint32 {
+	if params.SubsidySchedule == config.HalvingSchedule {
+		return params.SubsidyHalvingInterval
+	}
+	return uint32(365 * 24 * time.Hour / params.TargetTimePerBlock)
+}
+
+// calcIssuance returns the subsidy of the block at the height and the supply
+// until the block. The subsidy of an interval is decided by the supply at the
+// beginning of the interval, so the intervals are walked through one by one.
+func calcIssuance(height uint32, params *config.ChainParams) (common.Fixed64, common.Fixed64) {
+	supply := params.GenesisSupply
+	interval := uint64(GetSubsidyInterval(params))
+	if height == 0 || interval == 0 {
+		return 0, supply
+	}
+
+	for index := uint64(0); ; index++ {
+		subsidy := calcIntervalSubsidy(index, interval, supply, params)
+
+		// the genesis block is not subsidized
+		start, end := index*interval, (index+1)*interval
+		if start == 0 {
+			start = 1
+		}
+		if uint64(height) < end {
+			return subsidy, supply + subsidy*common.Fixed64(uint64(height)-start+1)
+		}
+		supply += subsidy * common.Fixed64(end-start)
+	}
+}
+
+// calcIntervalSubsidy returns the subsidy per block of the interval at the
+// index, supply is the amount issued before the interval.
+func calcIntervalSubsidy(index, interval uint64, supply common.Fixed64, params *config.ChainParams) common.Fixed64 {
+	if params.SubsidySchedule == config.HalvingSchedule {
+		if index >= 63 {
+			return 0
+		}
+		return params.InitialSubsidy >> index
+	}
+	return supply * common.Fixed64(params.InflationPercentPerYear) / 100 / common.Fixed64(interval)
+}
+
+// CoinbaseRewards is the split of the reward of a block in the coinbase.
+type CoinbaseRewards struct {
+	Foundation common.Fixed64
+	Miner      common.Fixed64
+	Delegate   common.Fixed64
+}
+
+// CalcCoinbaseRewards splits the total reward of a block, which is the block
+// subsidy plus the transaction fees. The shares of the foundation and the
+// miner are rounded down, so the delegates get the remainder.
+func CalcCoinbaseRewards(totalReward common.Fixed64, params *config.ChainParams) CoinbaseRewards {
+	foundation := totalReward * common.Fixed64(params.FoundationRewardPercent) / 100
+	miner := totalReward * common.Fixed64(params.MinerRewardPercent) / 100
+	return CoinbaseRewards{
+		Foundation: foundation,
+		Miner:      miner,
+		Delegate:   totalReward - foundation - miner,
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/wuyazero/Elastos.ELA/config"
+	"github.com/wuyazero/Elastos.ELA/core"
 
 	"github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,15 +21,61 @@ var (
 )
 
 func TestGetBlockRewardAmount(t *testing.T) {
-	var blocks = uint32(GeneratedBlocksPerYear)
-	for i := uint32(0); i < blocks; i++ {
-		if !assert.Equal(t, calcBlockSubsidy(i), RewardAmountPerBlock) {
+	var blocks = GetSubsidyInterval(config.Parameters.ChainParam)
+	for i := uint32(1); i < blocks; i++ {
+		if !assert.Equal(t, calcBlockSubsidy(i), CalcBlockSubsidy(i, config.Parameters.ChainParam)) {
 			break
 		}
 	}
 	t.Logf("Circulation test finished with %d blocks", blocks)
 }
 
+func TestCalcBlockSubsidy(t *testing.T) {
+	type vector struct {
+		height  uint32
+		subsidy common.Fixed64
+		supply  common.Fixed64
+	}
+	check := func(params *config.ChainParams, vectors []vector) {
+		for _, v := range vectors {
+			assert.Equal(t, v.subsidy, CalcBlockSubsidy(v.height, params), "height %d", v.height)
+			assert.Equal(t, v.supply, CalcSupply(v.height, params), "height %d", v.height)
+		}
+	}
+
+	// 1. Inflation of 4% a year with 10 blocks a year, compounded yearly
+	check(&config.ChainParams{
+		TargetTimePerBlock:      365 * 24 * time.Hour / 10,
+		GenesisSupply:           100000,
+		SubsidySchedule:         config.InflationSchedule,
+		InflationPercentPerYear: 4,
+	}, []vector{
+		{0, 0, 100000},
+		{1, 400, 100400},
+		{9, 400, 103600},
+		{10, 414, 104014},
+		{19, 414, 107740},
+		{20, 430, 108170},
+	})
+
+	// 2. Halving every 10 blocks
+	params := &config.ChainParams{
+		SubsidySchedule:        config.HalvingSchedule,
+		InitialSubsidy:         50,
+		SubsidyHalvingInterval: 10,
+	}
+	check(params, []vector{
+		{0, 0, 0},
+		{1, 50, 50},
+		{9, 50, 450},
+		{10, 25, 475},
+		{19, 25, 700},
+		{20, 12, 712},
+	})
+	assert.Equal(t, common.Fixed64(0), CalcBlockSubsidy(700, params))
+	assert.Equal(t, CalcSupply(699, params), CalcSupply(700, params))
+}
+
 func calcBlockSubsidy(currentHeight uint32) common.Fixed64 {
 	ToTalAmountOfEla := int64(OrginAmountOfEla)
 	for i := uint32(0); i < (currentHeight / uint32(SubsidyInterval)); i++ {
@@ -40,3 +88,132 @@ func calcBlockSubsidy(currentHeight uint32) common.Fixed64 {
 
 	return subsidyPerBlock
 }
+
+func TestCalcCoinbaseRewards(t *testing.T) {
+	params := &config.ChainParams{FoundationRewardPercent: 30, MinerRewardPercent: 35}
+	vectors := []struct {
+		total      common.Fixed64
+		foundation common.Fixed64
+		miner      common.Fixed64
+		delegate   common.Fixed64
+	}{
+		{0, 0, 0, 0},
+		{1, 0, 0, 1},
+		{3, 0, 1, 2},
+		{99, 29, 34, 36},
+		{100, 30, 35, 35},
+		{101, 30, 35, 36},
+		{9999999999, 2999999999, 3499999999, 3500000001},
+		{3300 * 10000 * 100000000, 990 * 10000 * 100000000, 1155 * 10000 * 100000000, 1155 * 10000 * 100000000},
+	}
+	for _, v := range vectors {
+		rewards := CalcCoinbaseRewards(v.total, params)
+		assert.Equal(t, v.foundation, rewards.Foundation, "total %d", v.total)
+		assert.Equal(t, v.miner, rewards.Miner, "total %d", v.total)
+		assert.Equal(t, v.delegate, rewards.Delegate, "total %d", v.total)
+		assert.Equal(t, v.total, rewards.Foundation+rewards.Miner+rewards.Delegate)
+	}
+}
+
+func TestCheckCoinbaseRewards(t *testing.T) {
+	totalReward := CalcBlockSubsidy(1, config.Parameters.ChainParam) + 12345
+	rewards := CalcCoinbaseRewards(totalReward, config.Parameters.ChainParam)
+	miner := common.Uint168{0x21, 1, 2, 3}
+	coinbase := &core.Transaction{
+		TxType: core.CoinBase,
+		Outputs: []*core.Output{
+			{ProgramHash: FoundationAddress, Value: rewards.Foundation},
+			{ProgramHash: miner, Value: rewards.Miner},
+			{ProgramHash: FoundationAddress, Value: rewards.Delegate},
+		},
+	}
+	// the delegate reward goes to the foundation without elected producers
+	assert.NoError(t, CheckCoinbaseRewards(coinbase, totalReward, nil))
+
+	// the miner keeps the delegate share
+	coinbase.Outputs[1].Value += rewards.Delegate
+	coinbase.Outputs[2].Value = 0
+	assert.EqualError(t, CheckCoinbaseRewards(coinbase, totalReward, nil),
+		"reward to miner in coinbase not correct")
+
+	// the delegate share is paid to the miner
+	coinbase.Outputs[1].Value = rewards.Miner
+	coinbase.Outputs[2] = &core.Output{ProgramHash: miner, Value: rewards.Delegate}
+	assert.EqualError(t, CheckCoinbaseRewards(coinbase, totalReward, nil),
+		"reward to delegate in coinbase not correct")
+
+	// the foundation share is rounded up
+	coinbase.Outputs[0].Value = rewards.Foundation + 1
+	assert.EqualError(t, CheckCoinbaseRewards(coinbase, totalReward, nil),
+		"reward to foundation in coinbase not correct")
+
+	// the delegate share is missing
+	coinbase.Outputs = coinbase.Outputs[:2]
+	assert.EqualError(t, CheckCoinbaseRewards(coinbase, totalReward, nil),
+		"coinbase outputs count should be 3")
+
+	// the delegate share is paid to the elected producers
+	elected := []*Producer{newTestProducer(t, 300), newTestProducer(t, 100)}
+	delegates, err := CalcDelegateRewards(rewards.Delegate, elected)
+	assert.NoError(t, err)
+	coinbase.Outputs[0].Value = rewards.Foundation
+	for _, delegate := range delegates {
+		coinbase.Outputs = append(coinbase.Outputs,
+			&core.Output{ProgramHash: delegate.ProgramHash, Value: delegate.Amount})
+	}
+	assert.NoError(t, CheckCoinbaseRewards(coinbase, totalReward, elected))
+	assert.EqualError(t, CheckCoinbaseRewards(coinbase, totalReward, nil),
+		"coinbase outputs count should be 3")
+
+	// the producers are paid in the wrong order
+	coinbase.Outputs[2], coinbase.Outputs[3] = coinbase.Outputs[3], coinbase.Outputs[2]
+	assert.EqualError(t, CheckCoinbaseRewards(coinbase, totalReward, elected),
+		"reward to delegate in coinbase not correct")
+}
+
+func newTestProducer(t *testing.T, votes common.Fixed64) *Producer {
+	_, public, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	publicKey, err := public.EncodePoint(true)
+	assert.NoError(t, err)
+	return &Producer{PublicKey: publicKey, NickName: "producer", Votes: votes}
+}
+
+func TestElectProducers(t *testing.T) {
+	p1 := newTestProducer(t, 100)
+	p2 := newTestProducer(t, 300)
+	p3 := newTestProducer(t, 200)
+	canceled := newTestProducer(t, 400)
+	canceled.CancelHeight = 10
+	noVotes := newTestProducer(t, 0)
+
+	producers := []*Producer{p1, p2, p3, canceled, noVotes}
+	assert.Equal(t, []*Producer{p2, p3, p1}, ElectProducers(producers, 36))
+	assert.Equal(t, []*Producer{p2, p3}, ElectProducers(producers, 2))
+	assert.Empty(t, ElectProducers([]*Producer{canceled, noVotes}, 36))
+}
+
+func TestCalcDelegateRewards(t *testing.T) {
+	// the reward goes to the foundation without elected producers
+	rewards, err := CalcDelegateRewards(1000, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []DelegateReward{{ProgramHash: FoundationAddress, Amount: 1000}}, rewards)
+
+	// the reward is shared by votes and the remainder goes to the first
+	elected := []*Producer{newTestProducer(t, 200), newTestProducer(t, 100), newTestProducer(t, 100)}
+	rewards, err = CalcDelegateRewards(1001, elected)
+	assert.NoError(t, err)
+	assert.Len(t, rewards, 3)
+	assert.Equal(t, common.Fixed64(501), rewards[0].Amount)
+	assert.Equal(t, common.Fixed64(250), rewards[1].Amount)
+	assert.Equal(t, common.Fixed64(250), rewards[2].Amount)
+	for i, p := range elected {
+		programHash, err := GetProducerProgramHash(p.PublicKey)
+		assert.NoError(t, err)
+		assert.Equal(t, *programHash, rewards[i].ProgramHash)
+	}
+
+	// an invalid public key can not be paid
+	_, err = CalcDelegateRewards(1001, []*Producer{{PublicKey: []byte{1, 2, 3}, Votes: 1}})
+	assert.Error(t, err)
+}
@@ -10,17 +10,46 @@ const (
 	DATA_Transaction DataEntryPrefix = 0x02
 
 	// INDEX
-	IX_HeaderHashList DataEntryPrefix = 0x80
-	IX_Unspent        DataEntryPrefix = 0x90
-	IX_Unspent_UTXO   DataEntryPrefix = 0x91
-	IX_SideChain_Tx   DataEntryPrefix = 0x92
+	IX_HeaderHashList    DataEntryPrefix = 0x80
+	IX_Unspent           DataEntryPrefix = 0x90
+	IX_Unspent_UTXO      DataEntryPrefix = 0x91
+	IX_SideChain_Tx      DataEntryPrefix = 0x92
+	IX_Record_Type       DataEntryPrefix = 0x93
+	IX_Record_Hash       DataEntryPrefix = 0x94
+	IX_SideChain_Address DataEntryPrefix = 0x95
+	IX_SideChain_Pow     DataEntryPrefix = 0x96
+	IX_Withdraw          DataEntryPrefix = 0x97
+	IX_Withdraw_Address  DataEntryPrefix = 0x98
 
 	// ASSET
 	ST_Info DataEntryPrefix = 0xc0
 
+	// PRODUCER
+	ST_Producer DataEntryPrefix = 0xc1
+
+	// ARBITRATORS
+	ST_Arbitrators DataEntryPrefix = 0xc2
+
+	// SIDECHAIN
+	ST_SideChain DataEntryPrefix = 0xc3
+
+	// PRODUCER HISTORY
+	ST_ProducerHistory DataEntryPrefix = 0xc4
+
+	// VOTE OUTPUT
+	ST_VoteOutput DataEntryPrefix = 0xc5
+
+	// assetSupplySuffix is appended to the ST_Info asset key for the supply
+	// state of the asset
+	assetSupplySuffix = 0x01
+
 	//SYSTEM
 	SYS_CurrentBlock      DataEntryPrefix = 0x40
 	SYS_CurrentBookKeeper DataEntryPrefix = 0x42
+	SYS_FeeEstimator      DataEntryPrefix = 0x43
+	SYS_RecordIndex       DataEntryPrefix = 0x44
+	SYS_SideChainPowIndex DataEntryPrefix = 0x45
+	SYS_WithdrawIndex     DataEntryPrefix = 0x46
 
 	//CONFIG
 	CFG_Version DataEntryPrefix = 0xf0
@@ -0,0 +1,337 @@
+package blockchain
+
+import (
+	"errors"
+	"io"
+	"math"
+	"sync"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+	. "github.com/wuyazero/Elastos.ELA/core"
+)
+
+const (
+	// FeeEstimatorVersion is the version of the serialized fee estimator state.
+	FeeEstimatorVersion = 0x01
+
+	// EstimateMaxTarget is the maximum number of blocks a fee estimation
+	// can target.
+	EstimateMaxTarget = 48
+
+	// estimateDecay is the factor applied to all historical data on each
+	// new block, so that old observations gradually lose their weight.
+	estimateDecay = 0.998
+
+	// estimateSuccessThreshold is the minimum ratio of transactions in a fee
+	// rate range which must have been confirmed within the target to accept
+	// the range as an estimation.
+	estimateSuccessThreshold = 0.85
+
+	// estimateSufficientTxs is the minimum (decayed) number of confirmed or
+	// failed transactions a fee rate range must contain to be evaluated.
+	estimateSufficientTxs = 1.0
+
+	// estimateMinBucketFee and estimateMaxBucketFee are the lower and upper
+	// fee rate bounds (in sela per KB) of the buckets.
+	estimateMinBucketFee = 10
+	estimateMaxBucketFee = 1e8
+
+	// estimateBucketSpacing is the ratio between two adjacent bucket bounds.
+	estimateBucketSpacing = 1.2
+)
+
+// FeeEstimate is the result of a fee estimation.
+type FeeEstimate struct {
+	// FeeRate is the estimated fee rate in sela per KB.
+	FeeRate Fixed64
+	// Blocks is the target which the estimation was found for, it may be
+	// larger than the requested target if there was not enough data.
+	Blocks uint32
+	// Confidence is the ratio of transactions paying FeeRate which were
+	// confirmed within Blocks.
+	Confidence float64
+}
+
+type observedTx struct {
+	height  uint32
+	feeRate Fixed64
+	bucket  int
+}
+
+// FeeEstimator tracks how many blocks transactions in the transaction pool
+// waited to be confirmed, bucketed by their fee rate, and estimates the fee
+// rate a new transaction should pay to be confirmed within a given number of
+// blocks.
+type FeeEstimator struct {
+	sync.Mutex
+	buckets    []Fixed64   // upper bound (inclusive) of each fee rate bucket
+	txCount    []float64   // decayed count of confirmed or failed transactions by bucket
+	feeSum     []float64   // decayed sum of confirmed or failed fee rates by bucket
+	confirmed  [][]float64 // confirmed[i][b] decayed count confirmed within i+1 blocks
+	observed   map[Uint256]*observedTx
+	bestHeight uint32
+}
+
+func NewFeeEstimator() *FeeEstimator {
+	var buckets []Fixed64
+	for fee := float64(estimateMinBucketFee); fee < estimateMaxBucketFee; fee *= estimateBucketSpacing {
+		buckets = append(buckets, Fixed64(fee))
+	}
+	buckets = append(buckets, Fixed64(estimateMaxBucketFee), Fixed64(math.MaxInt64))
+
+	estimator := &FeeEstimator{
+		buckets:   buckets,
+		txCount:   make([]float64, len(buckets)),
+		feeSum:    make([]float64, len(buckets)),
+		confirmed: make([][]float64, EstimateMaxTarget),
+		observed:  make(map[Uint256]*observedTx),
+	}
+	for i := range estimator.confirmed {
+		estimator.confirmed[i] = make([]float64, len(buckets))
+	}
+	return estimator
+}
+
+func (fe *FeeEstimator) bucketIndex(feeRate Fixed64) int {
+	for i, limit := range fe.buckets {
+		if feeRate <= limit {
+			return i
+		}
+	}
+	return len(fe.buckets) - 1
+}
+
+// ObserveTransaction starts tracking a transaction accepted into the
+// transaction pool at the given height. The fee rate is kept as the same
+// transaction in a block received from peers has no fee rate set.
+func (fe *FeeEstimator) ObserveTransaction(txn *Transaction, height uint32) {
+	fe.Lock()
+	defer fe.Unlock()
+	hash := txn.Hash()
+	if _, ok := fe.observed[hash]; ok {
+		return
+	}
+	fe.observed[hash] = &observedTx{
+		height:  height,
+		feeRate: txn.FeePerKB,
+		bucket:  fe.bucketIndex(txn.FeePerKB),
+	}
+}
+
+// RemoveTransaction stops tracking a transaction which left the transaction
+// pool without being confirmed. It is counted as failed to be confirmed within
+// any target only if failed is set, such as expired for its fee rate, the
+// transactions dropped by conflicts or double spends say nothing of the fee.
+func (fe *FeeEstimator) RemoveTransaction(hash Uint256, failed bool) {
+	fe.Lock()
+	defer fe.Unlock()
+	tx, ok := fe.observed[hash]
+	if !ok {
+		return
+	}
+	delete(fe.observed, hash)
+	if !failed {
+		return
+	}
+	fe.txCount[tx.bucket]++
+	fe.feeSum[tx.bucket] += float64(tx.feeRate)
+}
+
+// ProcessBlock records the number of blocks each tracked transaction included
+// in the block waited in the transaction pool.
+func (fe *FeeEstimator) ProcessBlock(block *Block) {
+	fe.Lock()
+	defer fe.Unlock()
+
+	// Blocks at or below the best height have been seen already, which
+	// happens on reorganize, just ignore them.
+	if block.Height <= fe.bestHeight {
+		return
+	}
+	fe.bestHeight = block.Height
+
+	for b := range fe.buckets {
+		fe.txCount[b] *= estimateDecay
+		fe.feeSum[b] *= estimateDecay
+		for i := range fe.confirmed {
+			fe.confirmed[i][b] *= estimateDecay
+		}
+	}
+
+	for _, txn := range block.Transactions {
+		if txn.IsCoinBaseTx() {
+			continue
+		}
+		hash := txn.Hash()
+		tx, ok := fe.observed[hash]
+		if !ok {
+			continue
+		}
+		delete(fe.observed, hash)
+
+		if tx.height >= block.Height {
+			continue
+		}
+		blocks := int(block.Height - tx.height)
+		fe.txCount[tx.bucket]++
+		fe.feeSum[tx.bucket] += float64(tx.feeRate)
+		for i := blocks - 1; i < len(fe.confirmed); i++ {
+			fe.confirmed[i][tx.bucket]++
+		}
+	}
+}
+
+// estimate returns the average fee rate of the lowest fee rate range in which
+// enough transactions have been confirmed within target blocks.
+func (fe *FeeEstimator) estimate(target int) (Fixed64, float64, bool) {
+	var count, confirmed, feeSum float64
+	var bestFeeRate Fixed64
+	var bestConfidence float64
+	var found bool
+
+	for b := len(fe.buckets) - 1; b >= 0; b-- {
+		count += fe.txCount[b]
+		confirmed += fe.confirmed[target-1][b]
+		feeSum += fe.feeSum[b]
+		if count < estimateSufficientTxs {
+			continue
+		}
+
+		ratio := confirmed / count
+		if ratio < estimateSuccessThreshold {
+			break
+		}
+		bestFeeRate = Fixed64(math.Floor(feeSum/count + 0.5))
+		bestConfidence = ratio
+		found = true
+		count, confirmed, feeSum = 0, 0, 0
+	}
+
+	return bestFeeRate, bestConfidence, found
+}
+
+// EstimateSmartFee returns the estimated fee rate to get a transaction
+// confirmed within target blocks. If there is not enough data for target, the
+// estimation of the nearest larger target which has enough data is returned.
+func (fe *FeeEstimator) EstimateSmartFee(target uint32) (*FeeEstimate, error) {
+	if target == 0 || target > EstimateMaxTarget {
+		return nil, errors.New("[EstimateSmartFee] target out of range")
+	}
+
+	fe.Lock()
+	defer fe.Unlock()
+	for blocks := int(target); blocks <= EstimateMaxTarget; blocks++ {
+		if feeRate, confidence, ok := fe.estimate(blocks); ok {
+			return &FeeEstimate{
+				FeeRate:    feeRate,
+				Blocks:     uint32(blocks),
+				Confidence: confidence,
+			}, nil
+		}
+	}
+
+	return nil, errors.New("[EstimateSmartFee] insufficient data")
+}
+
+func writeFloats(w io.Writer, values []float64) error {
+	for _, value := range values {
+		if err := WriteUint64(w, math.Float64bits(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readFloats(r io.Reader, values []float64) error {
+	for i := range values {
+		bits, err := ReadUint64(r)
+		if err != nil {
+			return err
+		}
+		values[i] = math.Float64frombits(bits)
+	}
+	return nil
+}
+
+// Serialize writes the historical data of the estimator, transactions being
+// tracked are not included.
+func (fe *FeeEstimator) Serialize(w io.Writer) error {
+	fe.Lock()
+	defer fe.Unlock()
+
+	if err := WriteUint8(w, FeeEstimatorVersion); err != nil {
+		return err
+	}
+	if err := WriteUint32(w, fe.bestHeight); err != nil {
+		return err
+	}
+	if err := WriteVarUint(w, uint64(len(fe.buckets))); err != nil {
+		return err
+	}
+	if err := WriteVarUint(w, uint64(len(fe.confirmed))); err != nil {
+		return err
+	}
+	if err := writeFloats(w, fe.txCount); err != nil {
+		return err
+	}
+	if err := writeFloats(w, fe.feeSum); err != nil {
+		return err
+	}
+	for _, confirmed := range fe.confirmed {
+		if err := writeFloats(w, confirmed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deserialize restores the historical data written by Serialize.
+func (fe *FeeEstimator) Deserialize(r io.Reader) error {
+	fe.Lock()
+	defer fe.Unlock()
+
+	version, err := ReadUint8(r)
+	if err != nil {
+		return err
+	}
+	if version != FeeEstimatorVersion {
+		return errors.New("[FeeEstimator] unknown version")
+	}
+	bestHeight, err := ReadUint32(r)
+	if err != nil {
+		return err
+	}
+	buckets, err := ReadVarUint(r, 0)
+	if err != nil {
+		return err
+	}
+	targets, err := ReadVarUint(r, 0)
+	if err != nil {
+		return err
+	}
+	if buckets != uint64(len(fe.buckets)) || targets != uint64(len(fe.confirmed)) {
+		return errors.New("[FeeEstimator] buckets mismatch")
+	}
+
+	txCount := make([]float64, buckets)
+	feeSum := make([]float64, buckets)
+	confirmed := make([][]float64, targets)
+	if err := readFloats(r, txCount); err != nil {
+		return err
+	}
+	if err := readFloats(r, feeSum); err != nil {
+		return err
+	}
+	for i := range confirmed {
+		confirmed[i] = make([]float64, buckets)
+		if err := readFloats(r, confirmed[i]); err != nil {
+			return err
+		}
+	}
+
+	fe.bestHeight = bestHeight
+	fe.txCount = txCount
+	fe.feeSum = feeSum
+	fe.confirmed = confirmed
+	return nil
+}
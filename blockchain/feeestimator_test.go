@@ -0,0 +1,138 @@
+package blockchain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wuyazero/Elastos.ELA/core"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+func buildFeeTx(feePerKB common.Fixed64) *core.Transaction {
+	tx := buildTx()
+	tx.FeePerKB = feePerKB
+	return tx
+}
+
+// toPeerBlock returns the block as received from peers, the transactions are
+// deserialized so they have no fee rate set.
+func toPeerBlock(block *core.Block) *core.Block {
+	peerBlock := &core.Block{Header: block.Header}
+	for _, tx := range block.Transactions {
+		buf := new(bytes.Buffer)
+		tx.Serialize(buf)
+		var peerTx core.Transaction
+		peerTx.Deserialize(buf)
+		peerBlock.Transactions = append(peerBlock.Transactions, &peerTx)
+	}
+	return peerBlock
+}
+
+func TestFeeEstimator_EstimateSmartFee(t *testing.T) {
+	fe := NewFeeEstimator()
+
+	// no data at all
+	_, err := fe.EstimateSmartFee(1)
+	assert.Error(t, err)
+	_, err = fe.EstimateSmartFee(0)
+	assert.Error(t, err)
+	_, err = fe.EstimateSmartFee(EstimateMaxTarget + 1)
+	assert.Error(t, err)
+
+	// high fee transactions are confirmed in the next block, low fee
+	// transactions are confirmed 5 blocks later
+	var pending []*core.Transaction
+	for height := uint32(1); height <= 100; height++ {
+		block := &core.Block{Header: core.Header{Height: height}}
+		var remains []*core.Transaction
+		for _, tx := range pending {
+			if tx.FeePerKB >= 10000 || tx.LockTime+5 <= height {
+				block.Transactions = append(block.Transactions, tx)
+			} else {
+				remains = append(remains, tx)
+			}
+		}
+		pending = remains
+		fe.ProcessBlock(toPeerBlock(block))
+
+		high := buildFeeTx(10000)
+		high.LockTime = height
+		low := buildFeeTx(500)
+		low.LockTime = height
+		fe.ObserveTransaction(high, height)
+		fe.ObserveTransaction(low, height)
+		pending = append(pending, high, low)
+	}
+
+	estimate, err := fe.EstimateSmartFee(1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint32(1), estimate.Blocks)
+	assert.Equal(t, common.Fixed64(10000), estimate.FeeRate)
+	assert.True(t, estimate.Confidence >= 0.85)
+
+	estimate, err = fe.EstimateSmartFee(5)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint32(5), estimate.Blocks)
+	assert.Equal(t, common.Fixed64(500), estimate.FeeRate)
+
+	// failed transactions are counted as failures
+	low := fe.bucketIndex(500)
+	count, confirmed := fe.txCount[low], fe.confirmed[EstimateMaxTarget-1][low]
+	tx := buildFeeTx(500)
+	fe.ObserveTransaction(tx, 100)
+	fe.RemoveTransaction(tx.Hash(), true)
+	_, ok := fe.observed[tx.Hash()]
+	assert.False(t, ok)
+	assert.Equal(t, count+1, fe.txCount[low])
+	assert.Equal(t, confirmed, fe.confirmed[EstimateMaxTarget-1][low])
+	fe.RemoveTransaction(tx.Hash(), true)
+	assert.Equal(t, count+1, fe.txCount[low])
+
+	// transactions removed for other reasons are only untracked
+	tx = buildFeeTx(500)
+	fe.ObserveTransaction(tx, 100)
+	fe.RemoveTransaction(tx.Hash(), false)
+	_, ok = fe.observed[tx.Hash()]
+	assert.False(t, ok)
+	assert.Equal(t, count+1, fe.txCount[low])
+
+	// failures lower the confidence until the range is rejected
+	for i := 0; i < 100; i++ {
+		tx := buildFeeTx(500)
+		fe.ObserveTransaction(tx, 100)
+		fe.RemoveTransaction(tx.Hash(), true)
+	}
+	estimate, err = fe.EstimateSmartFee(5)
+	if assert.NoError(t, err) {
+		assert.Equal(t, common.Fixed64(10000), estimate.FeeRate)
+	}
+
+	// blocks already processed are ignored
+	count = fe.txCount[fe.bucketIndex(10000)]
+	fe.ProcessBlock(&core.Block{Header: core.Header{Height: 100}})
+	assert.Equal(t, count, fe.txCount[fe.bucketIndex(10000)])
+
+	// serialize and deserialize
+	buf := new(bytes.Buffer)
+	if !assert.NoError(t, fe.Serialize(buf)) {
+		return
+	}
+	restored := NewFeeEstimator()
+	if !assert.NoError(t, restored.Deserialize(buf)) {
+		return
+	}
+	assert.Equal(t, fe.bestHeight, restored.bestHeight)
+	assert.Equal(t, fe.txCount, restored.txCount)
+	assert.Equal(t, fe.feeSum, restored.feeSum)
+	assert.Equal(t, fe.confirmed, restored.confirmed)
+
+	estimate2, err := restored.EstimateSmartFee(5)
+	assert.NoError(t, err)
+	assert.Equal(t, estimate, estimate2)
+}
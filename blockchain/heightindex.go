@@ -0,0 +1,98 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"math"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+// getHeightKey appends the height to the prefix of a height indexed key, the
+// height is big endian so the keys of the prefix are iterated in height order.
+func getHeightKey(prefix []byte, height uint32) []byte {
+	key := make([]byte, len(prefix)+4)
+	copy(key, prefix)
+	binary.BigEndian.PutUint32(key[len(prefix):], height)
+	return key
+}
+
+// getHeightIndexKey returns the key of a transaction indexed by the height
+// under the prefix.
+func getHeightIndexKey(prefix []byte, height uint32, hash Uint256) []byte {
+	return append(getHeightKey(prefix, height), hash.Bytes()...)
+}
+
+// heightIndexIterator iterates the transactions indexed under a prefix between
+// the heights from and to inclusive, in height order.
+type heightIndexIterator struct {
+	iter   IIterator
+	prefix []byte
+	seek   []byte
+	to     uint32
+	seeked bool
+	height uint32
+	hash   Uint256
+}
+
+func (c *ChainStore) newHeightIndexIterator(prefix []byte, from, to uint32) *heightIndexIterator {
+	return &heightIndexIterator{
+		iter:   c.NewIterator(prefix),
+		prefix: prefix,
+		seek:   getHeightKey(prefix, from),
+		to:     to,
+	}
+}
+
+// newHeightIndexIteratorAfter returns the iterator of the transactions indexed
+// under the prefix after the transaction of the hash at the height.
+func (c *ChainStore) newHeightIndexIteratorAfter(prefix []byte, height uint32, hash Uint256) *heightIndexIterator {
+	it := c.newHeightIndexIterator(prefix, height, math.MaxUint32)
+	// the smallest key greater than the key of the transaction
+	it.seek = append(getHeightIndexKey(prefix, height, hash), 0)
+	return it
+}
+
+// Next moves to the next transaction in the range, the first call seeks to
+// the start of the range.
+func (it *heightIndexIterator) Next() bool {
+	for {
+		var ok bool
+		if !it.seeked {
+			it.seeked = true
+			ok = it.iter.Seek(it.seek)
+		} else {
+			ok = it.iter.Next()
+		}
+		if !ok {
+			return false
+		}
+		key := it.iter.Key()[len(it.prefix):]
+		if len(key) != 4+UINT256SIZE {
+			continue
+		}
+		it.height = binary.BigEndian.Uint32(key[:4])
+		if it.height > it.to {
+			return false
+		}
+		copy(it.hash[:], key[4:])
+		return true
+	}
+}
+
+// Height returns the height the current transaction is indexed by.
+func (it *heightIndexIterator) Height() uint32 {
+	return it.height
+}
+
+// Hash returns the hash the current transaction is indexed by.
+func (it *heightIndexIterator) Hash() Uint256 {
+	return it.hash
+}
+
+func (it *heightIndexIterator) Value() []byte {
+	return it.iter.Value()
+}
+
+func (it *heightIndexIterator) Release() {
+	it.iter.Release()
+}
@@ -24,9 +24,28 @@ type IChainStore interface {
 
 	PersistAsset(assetid Uint256, asset Asset) error
 	GetAsset(hash Uint256) (*Asset, error)
+	GetAssetSupply(assetId Uint256) (*AssetSupply, error)
 
 	PersistSidechainTx(sidechainTxHash Uint256)
 	GetSidechainTx(sidechainTxHash Uint256) (byte, error)
+	GetWithdraw(sidechainTxHash Uint256) (*WithdrawIndex, error)
+	GetWithdraws(programHash Uint168, start *Uint256, count int) ([]*WithdrawIndex, error)
+
+	GetRecordsByType(recordType string, from, to uint32) ([]*RecordIndex, error)
+	GetRecordsByHash(dataHash Uint256) ([]*RecordIndex, error)
+
+	GetProducer(publicKey []byte) (*Producer, error)
+	GetProducers() []*Producer
+
+	GetArbitrators(height uint32) ([][]byte, error)
+
+	GetSideChain(genesisHash Uint256) (*SideChain, error)
+	GetSideChainByProgramHash(programHash Uint168) (*SideChain, error)
+	GetSideChains() []*SideChain
+	GetSideChainPows(genesisHash Uint256, from, to uint32) ([]*SideChainPowIndex, error)
+
+	PersistFeeEstimator(data []byte) error
+	GetFeeEstimator() ([]byte, error)
 
 	GetCurrentBlockHash() Uint256
 	GetHeight() uint32
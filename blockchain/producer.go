@@ -0,0 +1,672 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/wuyazero/Elastos.ELA/config"
+	. "github.com/wuyazero/Elastos.ELA/core"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
+)
+
+const (
+	// MaxProducerNickNameLength is the longest nick name of a producer.
+	MaxProducerNickNameLength = 100
+	// MaxProducerUrlLength is the longest url of a producer.
+	MaxProducerUrlLength = 100
+	// VoteOutputIndex is the index of the output of a vote transaction which
+	// votes for the candidates, the other outputs are change.
+	VoteOutputIndex = 0
+)
+
+// Producer is the state of a block producer registered by a RegisterProducer
+// transaction.
+type Producer struct {
+	PublicKey []byte
+	NickName  string
+	Url       string
+	// Deposit is the first output of the registering transaction, which is
+	// locked until the producer is canceled.
+	Deposit       OutPoint
+	DepositAmount Fixed64
+	// RegisterHeight is the height the producer is registered at.
+	RegisterHeight uint32
+	// CancelHeight is the height the producer is canceled at, zero if the
+	// producer is active.
+	CancelHeight uint32
+	// Votes is the value of the unspent vote outputs voting for the producer,
+	// the votes are kept when the public key is registered again.
+	Votes Fixed64
+}
+
+// IsActive checks if the producer is not canceled.
+func (p *Producer) IsActive() bool {
+	return p.CancelHeight == 0
+}
+
+func (p *Producer) Serialize(w io.Writer) error {
+	if err := WriteVarBytes(w, p.PublicKey); err != nil {
+		return errors.New("[Producer], PublicKey serialize failed.")
+	}
+	if err := WriteVarString(w, p.NickName); err != nil {
+		return errors.New("[Producer], NickName serialize failed.")
+	}
+	if err := WriteVarString(w, p.Url); err != nil {
+		return errors.New("[Producer], Url serialize failed.")
+	}
+	if err := p.Deposit.Serialize(w); err != nil {
+		return errors.New("[Producer], Deposit serialize failed.")
+	}
+	if err := p.DepositAmount.Serialize(w); err != nil {
+		return errors.New("[Producer], DepositAmount serialize failed.")
+	}
+	if err := WriteUint32(w, p.RegisterHeight); err != nil {
+		return errors.New("[Producer], RegisterHeight serialize failed.")
+	}
+	if err := WriteUint32(w, p.CancelHeight); err != nil {
+		return errors.New("[Producer], CancelHeight serialize failed.")
+	}
+	if err := p.Votes.Serialize(w); err != nil {
+		return errors.New("[Producer], Votes serialize failed.")
+	}
+	return nil
+}
+
+func (p *Producer) Deserialize(r io.Reader) error {
+	var err error
+	if p.PublicKey, err = ReadVarBytes(r); err != nil {
+		return errors.New("[Producer], PublicKey deserialize failed.")
+	}
+	if p.NickName, err = ReadVarString(r); err != nil {
+		return errors.New("[Producer], NickName deserialize failed.")
+	}
+	if p.Url, err = ReadVarString(r); err != nil {
+		return errors.New("[Producer], Url deserialize failed.")
+	}
+	if err := p.Deposit.Deserialize(r); err != nil {
+		return errors.New("[Producer], Deposit deserialize failed.")
+	}
+	if err := p.DepositAmount.Deserialize(r); err != nil {
+		return errors.New("[Producer], DepositAmount deserialize failed.")
+	}
+	if p.RegisterHeight, err = ReadUint32(r); err != nil {
+		return errors.New("[Producer], RegisterHeight deserialize failed.")
+	}
+	if p.CancelHeight, err = ReadUint32(r); err != nil {
+		return errors.New("[Producer], CancelHeight deserialize failed.")
+	}
+	if err := p.Votes.Deserialize(r); err != nil {
+		return errors.New("[Producer], Votes deserialize failed.")
+	}
+	return nil
+}
+
+// GetProducerProgramHash returns the standard program hash of the public key
+// of a producer, which signs the producer transactions and receives the
+// delegate reward.
+func GetProducerProgramHash(publicKey []byte) (*Uint168, error) {
+	point, err := crypto.DecodePoint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	code, err := crypto.CreateStandardRedeemScript(point)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.ToProgramHash(code)
+}
+
+func getProducerKey(publicKey []byte) []byte {
+	return append([]byte{byte(ST_Producer)}, publicKey...)
+}
+
+// getProducerHistoryKey returns the key of the state a producer had before its
+// public key was registered again at the height.
+func getProducerHistoryKey(publicKey []byte, height uint32) []byte {
+	return getHeightKey(append([]byte{byte(ST_ProducerHistory)}, publicKey...), height)
+}
+
+func producerConflictKey(publicKey []byte) string {
+	return "producer:" + BytesToHexString(publicKey)
+}
+
+func (c *ChainStore) PersistProducer(p *Producer) error {
+	value := new(bytes.Buffer)
+	if err := p.Serialize(value); err != nil {
+		return err
+	}
+	c.BatchPut(getProducerKey(p.PublicKey), value.Bytes())
+	return nil
+}
+
+func (c *ChainStore) RollbackProducer(publicKey []byte) error {
+	c.BatchDelete(getProducerKey(publicKey))
+	return nil
+}
+
+func (c *ChainStore) GetProducer(publicKey []byte) (*Producer, error) {
+	data, err := c.Get(getProducerKey(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	p := new(Producer)
+	if err := p.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetProducers returns the registered producers including the canceled ones.
+func (c *ChainStore) GetProducers() []*Producer {
+	producers := make([]*Producer, 0)
+	iter := c.NewIterator([]byte{byte(ST_Producer)})
+	defer iter.Release()
+	for iter.Next() {
+		p := new(Producer)
+		if err := p.Deserialize(bytes.NewReader(iter.Value())); err != nil {
+			continue
+		}
+		producers = append(producers, p)
+	}
+	return producers
+}
+
+// VoteOutput is the vote output of a vote transaction, indexed by its outpoint
+// to take the votes back when it is spent.
+type VoteOutput struct {
+	Value      Fixed64
+	Candidates [][]byte
+}
+
+func (v *VoteOutput) Serialize(w io.Writer) error {
+	if err := v.Value.Serialize(w); err != nil {
+		return errors.New("[VoteOutput], Value serialize failed.")
+	}
+	if err := WriteVarUint(w, uint64(len(v.Candidates))); err != nil {
+		return errors.New("[VoteOutput], Candidates count serialize failed.")
+	}
+	for _, candidate := range v.Candidates {
+		if err := WriteVarBytes(w, candidate); err != nil {
+			return errors.New("[VoteOutput], Candidate serialize failed.")
+		}
+	}
+	return nil
+}
+
+func (v *VoteOutput) Deserialize(r io.Reader) error {
+	if err := v.Value.Deserialize(r); err != nil {
+		return errors.New("[VoteOutput], Value deserialize failed.")
+	}
+	count, err := ReadVarUint(r, 0)
+	if err != nil {
+		return errors.New("[VoteOutput], Candidates count deserialize failed.")
+	}
+	v.Candidates = make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		candidate, err := ReadVarBytes(r)
+		if err != nil {
+			return errors.New("[VoteOutput], Candidate deserialize failed.")
+		}
+		v.Candidates = append(v.Candidates, candidate)
+	}
+	return nil
+}
+
+func getVoteOutputKey(outPoint OutPoint) []byte {
+	return append([]byte{byte(ST_VoteOutput)}, outPoint.Bytes()...)
+}
+
+func (c *ChainStore) PersistVoteOutput(outPoint OutPoint, vote *VoteOutput) error {
+	value := new(bytes.Buffer)
+	if err := vote.Serialize(value); err != nil {
+		return err
+	}
+	c.BatchPut(getVoteOutputKey(outPoint), value.Bytes())
+	return nil
+}
+
+func (c *ChainStore) RollbackVoteOutput(outPoint OutPoint) error {
+	c.BatchDelete(getVoteOutputKey(outPoint))
+	return nil
+}
+
+func (c *ChainStore) GetVoteOutput(outPoint OutPoint) (*VoteOutput, error) {
+	data, err := c.Get(getVoteOutputKey(outPoint))
+	if err != nil {
+		return nil, err
+	}
+	vote := new(VoteOutput)
+	if err := vote.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return vote, nil
+}
+
+// producerChanges accumulates the changes of the producers made by a block.
+type producerChanges struct {
+	*stateChanges
+	store *ChainStore
+	// votes are the vote outputs of the block, which may be spent in the
+	// same block
+	votes map[OutPoint]*VoteOutput
+}
+
+func newProducerChanges(c *ChainStore) *producerChanges {
+	return &producerChanges{
+		stateChanges: newStateChanges(func(key string) (interface{}, error) {
+			return c.GetProducer([]byte(key))
+		}),
+		store: c,
+		votes: make(map[OutPoint]*VoteOutput),
+	}
+}
+
+func (pc *producerChanges) get(publicKey []byte) (*Producer, error) {
+	p, err := pc.stateChanges.get(string(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	return p.(*Producer), nil
+}
+
+// addVotes adds the value of a vote output to the votes of the candidates,
+// sign is -1 if the output is spent.
+func (pc *producerChanges) addVotes(vote *VoteOutput, sign Fixed64) error {
+	for _, candidate := range vote.Candidates {
+		p, err := pc.get(candidate)
+		if err != nil {
+			return err
+		}
+		p.Votes += sign * vote.Value
+	}
+	return nil
+}
+
+// getVoteOutput returns the vote output of the outpoint, false if the outpoint
+// is not a vote output.
+func (pc *producerChanges) getVoteOutput(outPoint OutPoint) (*VoteOutput, bool) {
+	if vote, ok := pc.votes[outPoint]; ok {
+		return vote, true
+	}
+	vote, err := pc.store.GetVoteOutput(outPoint)
+	if err != nil {
+		return nil, false
+	}
+	return vote, true
+}
+
+// addSpentVotes adds the votes of the vote outputs spent by the transaction,
+// sign is -1 when the block is persisted and 1 when it is rolled back.
+func (pc *producerChanges) addSpentVotes(txn *Transaction, sign Fixed64) error {
+	if txn.IsCoinBaseTx() {
+		return nil
+	}
+	for _, input := range txn.Inputs {
+		if input.Previous.Index != VoteOutputIndex {
+			continue
+		}
+		vote, ok := pc.getVoteOutput(input.Previous)
+		if !ok {
+			continue
+		}
+		if err := pc.addVotes(vote, sign); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pc *producerChanges) commit() error {
+	return pc.stateChanges.commit(
+		func(key string, state interface{}) error {
+			return pc.store.PersistProducer(state.(*Producer))
+		},
+		func(key string) error {
+			return pc.store.RollbackProducer([]byte(key))
+		})
+}
+
+// newVoteOutput returns the vote output of a vote transaction.
+func newVoteOutput(txn *Transaction) (*VoteOutput, bool) {
+	payload, ok := txn.Payload.(*PayloadVoteProducer)
+	if !ok || len(txn.Outputs) <= VoteOutputIndex {
+		return nil, false
+	}
+	return &VoteOutput{
+		Value:      txn.Outputs[VoteOutputIndex].Value,
+		Candidates: payload.Candidates,
+	}, true
+}
+
+// PersistProducers updates the producers registered, canceled and voted by
+// the block.
+func (c *ChainStore) PersistProducers(b *Block) error {
+	pc := newProducerChanges(c)
+	for _, txn := range b.Transactions {
+		if err := pc.addSpentVotes(txn, -1); err != nil {
+			return err
+		}
+
+		switch payload := txn.Payload.(type) {
+		case *PayloadRegisterProducer:
+			producer := &Producer{
+				PublicKey:      payload.PublicKey,
+				NickName:       payload.NickName,
+				Url:            payload.Url,
+				Deposit:        *NewOutPoint(txn.Hash(), 0),
+				DepositAmount:  txn.Outputs[0].Value,
+				RegisterHeight: b.Header.Height,
+			}
+			// the public key of a canceled producer is registered again, the
+			// former state is kept for the rollback
+			if p, err := pc.get(payload.PublicKey); err == nil {
+				value := new(bytes.Buffer)
+				if err := p.Serialize(value); err != nil {
+					return err
+				}
+				c.BatchPut(getProducerHistoryKey(payload.PublicKey, b.Header.Height), value.Bytes())
+				producer.Votes = p.Votes
+			}
+			pc.put(string(payload.PublicKey), producer)
+		case *PayloadCancelProducer:
+			p, err := pc.get(payload.PublicKey)
+			if err != nil {
+				return err
+			}
+			p.CancelHeight = b.Header.Height
+		case *PayloadVoteProducer:
+			vote, ok := newVoteOutput(txn)
+			if !ok {
+				continue
+			}
+			outPoint := *NewOutPoint(txn.Hash(), VoteOutputIndex)
+			if err := c.PersistVoteOutput(outPoint, vote); err != nil {
+				return err
+			}
+			pc.votes[outPoint] = vote
+			if err := pc.addVotes(vote, 1); err != nil {
+				return err
+			}
+		}
+	}
+	return pc.commit()
+}
+
+// RollbackProducers reverts the changes of the producers made by the block.
+func (c *ChainStore) RollbackProducers(b *Block) error {
+	pc := newProducerChanges(c)
+	for _, txn := range b.Transactions {
+		if err := pc.addSpentVotes(txn, 1); err != nil {
+			return err
+		}
+
+		switch payload := txn.Payload.(type) {
+		case *PayloadRegisterProducer:
+			historyKey := getProducerHistoryKey(payload.PublicKey, b.Header.Height)
+			data, err := c.Get(historyKey)
+			if err != nil {
+				pc.remove(string(payload.PublicKey))
+				continue
+			}
+			// restore the state before the public key was registered again
+			p, err := pc.get(payload.PublicKey)
+			if err != nil {
+				return err
+			}
+			former := new(Producer)
+			if err := former.Deserialize(bytes.NewReader(data)); err != nil {
+				return err
+			}
+			former.Votes = p.Votes
+			*p = *former
+			c.BatchDelete(historyKey)
+		case *PayloadCancelProducer:
+			p, err := pc.get(payload.PublicKey)
+			if err != nil {
+				return err
+			}
+			p.CancelHeight = 0
+		case *PayloadVoteProducer:
+			vote, ok := newVoteOutput(txn)
+			if !ok {
+				continue
+			}
+			if err := c.RollbackVoteOutput(*NewOutPoint(txn.Hash(), VoteOutputIndex)); err != nil {
+				return err
+			}
+			if err := pc.addVotes(vote, -1); err != nil {
+				return err
+			}
+		}
+	}
+	return pc.commit()
+}
+
+// ElectProducers returns the active producers with the most votes, at most
+// count producers are elected and the producers without votes are not.
+func ElectProducers(producers []*Producer, count int) []*Producer {
+	elected := make([]*Producer, 0, len(producers))
+	for _, p := range producers {
+		if p.IsActive() && p.Votes > 0 {
+			elected = append(elected, p)
+		}
+	}
+	sort.Slice(elected, func(i, j int) bool {
+		if elected[i].Votes != elected[j].Votes {
+			return elected[i].Votes > elected[j].Votes
+		}
+		return bytes.Compare(elected[i].PublicKey, elected[j].PublicKey) < 0
+	})
+	if len(elected) > count {
+		elected = elected[:count]
+	}
+	return elected
+}
+
+// GetElectedProducers returns the producers elected by the votes of the
+// current best chain.
+func GetElectedProducers() []*Producer {
+	return ElectProducers(DefaultLedger.Store.GetProducers(),
+		config.Parameters.ChainParam.MaxElectedProducers)
+}
+
+// DelegateReward is the share of the delegate reward paid to a producer.
+type DelegateReward struct {
+	ProgramHash Uint168
+	Amount      Fixed64
+}
+
+// CalcDelegateRewards distributes the delegate reward to the elected producers
+// proportionally to their votes, rounded down with the remainder paid to the
+// producer with the most votes. The reward goes to the foundation if no
+// producer is elected.
+func CalcDelegateRewards(delegate Fixed64, elected []*Producer) ([]DelegateReward, error) {
+	if len(elected) == 0 {
+		return []DelegateReward{{ProgramHash: FoundationAddress, Amount: delegate}}, nil
+	}
+
+	totalVotes := new(big.Int)
+	for _, p := range elected {
+		totalVotes.Add(totalVotes, big.NewInt(int64(p.Votes)))
+	}
+	rewards := make([]DelegateReward, 0, len(elected))
+	remainder := delegate
+	for _, p := range elected {
+		programHash, err := GetProducerProgramHash(p.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		amount := new(big.Int).Mul(big.NewInt(int64(delegate)), big.NewInt(int64(p.Votes)))
+		amount.Quo(amount, totalVotes)
+		rewards = append(rewards, DelegateReward{ProgramHash: *programHash, Amount: Fixed64(amount.Int64())})
+		remainder -= Fixed64(amount.Int64())
+	}
+	rewards[0].Amount += remainder
+	return rewards, nil
+}
+
+func checkRegisterProducerPayload(txn *Transaction) error {
+	payload, ok := txn.Payload.(*PayloadRegisterProducer)
+	if !ok {
+		return errors.New("invalid register producer payload")
+	}
+	if _, err := GetProducerProgramHash(payload.PublicKey); err != nil {
+		return errors.New("invalid producer public key")
+	}
+	if len(payload.NickName) == 0 || len(payload.NickName) > MaxProducerNickNameLength {
+		return errors.New("invalid producer nick name")
+	}
+	if len(payload.Url) > MaxProducerUrlLength {
+		return errors.New("invalid producer url")
+	}
+	return nil
+}
+
+// checkProducerActivation checks the producer transactions are activated at
+// the height of the next block.
+func checkProducerActivation() error {
+	height := DefaultLedger.Store.GetHeight() + 1
+	if height < config.Parameters.ChainParam.ProducerActivationHeight {
+		return errors.New("producer transactions are not activated")
+	}
+	return nil
+}
+
+// CheckRegisterProducerTransaction checks the producer is not registered, or
+// canceled with the deposit returned, and the first output is the deposit paid
+// to the producer.
+func CheckRegisterProducerTransaction(txn *Transaction) error {
+	if err := checkProducerActivation(); err != nil {
+		return err
+	}
+	payload := txn.Payload.(*PayloadRegisterProducer)
+	if p, err := DefaultLedger.Store.GetProducer(payload.PublicKey); err == nil {
+		if p.IsActive() {
+			return errors.New("producer already registered")
+		}
+		unspent, _ := DefaultLedger.Store.ContainsUnspent(p.Deposit.TxID, p.Deposit.Index)
+		if unspent {
+			return errors.New("deposit of the canceled producer not returned")
+		}
+	}
+	programHash, err := GetProducerProgramHash(payload.PublicKey)
+	if err != nil {
+		return err
+	}
+	if len(txn.Outputs) == 0 {
+		return errors.New("producer deposit not found")
+	}
+	deposit := txn.Outputs[0]
+	if deposit.AssetID != DefaultLedger.Blockchain.AssetID || deposit.ProgramHash != *programHash {
+		return errors.New("producer deposit must be paid to the producer")
+	}
+	if deposit.Value < config.Parameters.ChainParam.ProducerDeposit {
+		return errors.New("producer deposit not enough")
+	}
+	return nil
+}
+
+func checkCancelProducerPayload(txn *Transaction) error {
+	payload, ok := txn.Payload.(*PayloadCancelProducer)
+	if !ok {
+		return errors.New("invalid cancel producer payload")
+	}
+	if _, err := GetProducerProgramHash(payload.PublicKey); err != nil {
+		return errors.New("invalid producer public key")
+	}
+	return nil
+}
+
+// CheckCancelProducerTransaction checks the producer is active.
+func CheckCancelProducerTransaction(txn *Transaction) error {
+	if err := checkProducerActivation(); err != nil {
+		return err
+	}
+	payload := txn.Payload.(*PayloadCancelProducer)
+	p, err := DefaultLedger.Store.GetProducer(payload.PublicKey)
+	if err != nil {
+		return errors.New("producer not registered")
+	}
+	if !p.IsActive() {
+		return errors.New("producer already canceled")
+	}
+	return nil
+}
+
+func checkVoteProducerPayload(txn *Transaction) error {
+	payload, ok := txn.Payload.(*PayloadVoteProducer)
+	if !ok {
+		return errors.New("invalid vote producer payload")
+	}
+	if len(payload.Candidates) == 0 || len(payload.Candidates) > config.Parameters.ChainParam.MaxVoteCandidates {
+		return errors.New("invalid vote candidates count")
+	}
+	candidates := make(map[string]struct{}, len(payload.Candidates))
+	for _, candidate := range payload.Candidates {
+		if _, ok := candidates[string(candidate)]; ok {
+			return errors.New("duplicated vote candidate")
+		}
+		candidates[string(candidate)] = struct{}{}
+	}
+	return nil
+}
+
+// CheckVoteProducerTransaction checks the candidates are active producers, and
+// the output voting for them is ELA.
+func CheckVoteProducerTransaction(txn *Transaction) error {
+	if err := checkProducerActivation(); err != nil {
+		return err
+	}
+	payload := txn.Payload.(*PayloadVoteProducer)
+	if len(txn.Outputs) <= VoteOutputIndex {
+		return errors.New("vote output not found")
+	}
+	if txn.Outputs[VoteOutputIndex].AssetID != DefaultLedger.Blockchain.AssetID {
+		return errors.New("vote output must be ELA")
+	}
+	for _, candidate := range payload.Candidates {
+		p, err := DefaultLedger.Store.GetProducer(candidate)
+		if err != nil || !p.IsActive() {
+			return errors.New("vote candidate is not an active producer")
+		}
+	}
+	return nil
+}
+
+// CheckProducerDeposit checks the transaction does not spend the deposit of an
+// active producer.
+func CheckProducerDeposit(txn *Transaction) error {
+	for _, input := range txn.Inputs {
+		if input.Previous.Index != 0 {
+			continue
+		}
+		referTxn, _, err := DefaultLedger.Store.GetTransaction(input.Previous.TxID)
+		if err != nil {
+			return errors.New("referenced transaction not found")
+		}
+		payload, ok := referTxn.Payload.(*PayloadRegisterProducer)
+		if !ok {
+			continue
+		}
+		p, err := DefaultLedger.Store.GetProducer(payload.PublicKey)
+		if err == nil && p.IsActive() && p.Deposit.IsEqual(input.Previous) {
+			return errors.New("cannot spend the deposit of an active producer")
+		}
+	}
+	return nil
+}
+
+func producerConflictKeys(txn *Transaction) []string {
+	switch payload := txn.Payload.(type) {
+	case *PayloadRegisterProducer:
+		return []string{producerConflictKey(payload.PublicKey)}
+	case *PayloadCancelProducer:
+		return []string{producerConflictKey(payload.PublicKey)}
+	}
+	return nil
+}
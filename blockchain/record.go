@@ -0,0 +1,128 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	. "github.com/wuyazero/Elastos.ELA/core"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+// RecordIndex locates a record anchored on chain by a Record transaction.
+type RecordIndex struct {
+	RecordType string
+	// DataHash is the SHA-256 hash of the record data.
+	DataHash Uint256
+	TxID     Uint256
+	Height   uint32
+}
+
+// GetRecordDataHash returns the hash of the record data the records are
+// indexed by.
+func GetRecordDataHash(data []byte) Uint256 {
+	return Uint256(sha256.Sum256(data))
+}
+
+// getRecordTypeKey returns the key of the record in the type index.
+func getRecordTypeKey(recordType string, height uint32, txId Uint256) []byte {
+	return getHeightIndexKey(getRecordTypePrefix(recordType), height, txId)
+}
+
+func getRecordTypePrefix(recordType string) []byte {
+	key := new(bytes.Buffer)
+	key.WriteByte(byte(IX_Record_Type))
+	WriteVarString(key, recordType)
+	return key.Bytes()
+}
+
+func getRecordHashKey(dataHash Uint256, txId Uint256) []byte {
+	key := []byte{byte(IX_Record_Hash)}
+	key = append(key, dataHash.Bytes()...)
+	return append(key, txId.Bytes()...)
+}
+
+// PersistRecord indexes the record of the transaction by its type and by the
+// hash of its data.
+func (c *ChainStore) PersistRecord(txn *Transaction, height uint32) error {
+	payload, ok := txn.Payload.(*PayloadRecord)
+	if !ok {
+		return nil
+	}
+	txId := txn.Hash()
+	dataHash := GetRecordDataHash(payload.RecordData)
+	c.BatchPut(getRecordTypeKey(payload.RecordType, height, txId), dataHash.Bytes())
+
+	value := new(bytes.Buffer)
+	if err := WriteUint32(value, height); err != nil {
+		return err
+	}
+	c.BatchPut(getRecordHashKey(dataHash, txId), value.Bytes())
+	return nil
+}
+
+// RollbackRecord removes the record of the transaction from the indexes.
+func (c *ChainStore) RollbackRecord(txn *Transaction, height uint32) error {
+	payload, ok := txn.Payload.(*PayloadRecord)
+	if !ok {
+		return nil
+	}
+	txId := txn.Hash()
+	c.BatchDelete(getRecordTypeKey(payload.RecordType, height, txId))
+	c.BatchDelete(getRecordHashKey(GetRecordDataHash(payload.RecordData), txId))
+	return nil
+}
+
+// GetRecordsByType returns the records of the type anchored between the
+// heights from and to inclusive, in height order.
+func (c *ChainStore) GetRecordsByType(recordType string, from, to uint32) ([]*RecordIndex, error) {
+	records := make([]*RecordIndex, 0)
+	iter := c.newHeightIndexIterator(getRecordTypePrefix(recordType), from, to)
+	defer iter.Release()
+	for iter.Next() {
+		dataHash, err := Uint256FromBytes(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &RecordIndex{
+			RecordType: recordType,
+			DataHash:   *dataHash,
+			TxID:       iter.Hash(),
+			Height:     iter.Height(),
+		})
+	}
+	return records, nil
+}
+
+// GetRecordsByHash returns the records of which the data hash is dataHash.
+func (c *ChainStore) GetRecordsByHash(dataHash Uint256) ([]*RecordIndex, error) {
+	prefix := append([]byte{byte(IX_Record_Hash)}, dataHash.Bytes()...)
+	records := make([]*RecordIndex, 0)
+	iter := c.NewIterator(prefix)
+	defer iter.Release()
+	for iter.Next() {
+		txId, err := Uint256FromBytes(iter.Key()[len(prefix):])
+		if err != nil {
+			return nil, err
+		}
+		height, err := ReadUint32(bytes.NewReader(iter.Value()))
+		if err != nil {
+			return nil, err
+		}
+		txn, _, err := c.GetTransaction(*txId)
+		if err != nil {
+			return nil, err
+		}
+		payload, ok := txn.Payload.(*PayloadRecord)
+		if !ok {
+			continue
+		}
+		records = append(records, &RecordIndex{
+			RecordType: payload.RecordType,
+			DataHash:   dataHash,
+			TxID:       *txId,
+			Height:     height,
+		})
+	}
+	return records, nil
+}
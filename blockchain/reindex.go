@@ -0,0 +1,46 @@
+package blockchain
+
+import (
+	. "github.com/wuyazero/Elastos.ELA/core"
+	"github.com/wuyazero/Elastos.ELA/log"
+)
+
+// reindexBatchBlocks is the count of blocks of which the indexes are committed
+// in a batch when reindexing.
+const reindexBatchBlocks = 1000
+
+// reindex indexes the transactions of the blocks up to the height by persist,
+// for an index added after the blocks were persisted. The store is marked by
+// the key once all the blocks are indexed, so it is done only once.
+func (c *ChainStore) reindex(key DataEntryPrefix, name string, height uint32,
+	persist func(txn *Transaction, height uint32) error) error {
+	if _, err := c.Get([]byte{byte(key)}); err == nil {
+		return nil
+	}
+
+	log.Infof("indexing %s of the blocks up to height %d", name, height)
+	c.NewBatch()
+	for h := uint32(0); h <= height; h++ {
+		hash, err := c.GetBlockHash(h)
+		if err != nil {
+			return err
+		}
+		block, err := c.GetBlock(hash)
+		if err != nil {
+			return err
+		}
+		for _, txn := range block.Transactions {
+			if err := persist(txn, h); err != nil {
+				return err
+			}
+		}
+		if h%reindexBatchBlocks == reindexBatchBlocks-1 {
+			if err := c.BatchCommit(); err != nil {
+				return err
+			}
+			c.NewBatch()
+		}
+	}
+	c.BatchPut([]byte{byte(key)}, []byte{byte(ValueExist)})
+	return c.BatchCommit()
+}
@@ -0,0 +1,463 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/wuyazero/Elastos.ELA/config"
+	. "github.com/wuyazero/Elastos.ELA/core"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
+)
+
+// MaxSideChainNameLength is the longest name of a side chain.
+const MaxSideChainNameLength = 100
+
+// SideChain is the state of a side chain registered by a RegisterSideChain
+// transaction.
+type SideChain struct {
+	Name                    string
+	GenesisHash             Uint256
+	GenesisAddress          string
+	MinArbitratorSignatures uint32
+	RegisterHeight          uint32
+	// Deposited is the value transferred to the genesis address by the cross
+	// chain transfers, starting with the unspent value of the genesis address
+	// when the side chain is registered.
+	Deposited Fixed64
+	// Withdrawn is the value spent from the genesis address by the
+	// withdrawals, less the change paid back to it.
+	Withdrawn Fixed64
+}
+
+// Locked returns the value locked in the genesis address for the side chain.
+func (s *SideChain) Locked() Fixed64 {
+	return s.Deposited - s.Withdrawn
+}
+
+func (s *SideChain) Serialize(w io.Writer) error {
+	if err := WriteVarString(w, s.Name); err != nil {
+		return errors.New("[SideChain], Name serialize failed.")
+	}
+	if err := s.GenesisHash.Serialize(w); err != nil {
+		return errors.New("[SideChain], GenesisHash serialize failed.")
+	}
+	if err := WriteVarString(w, s.GenesisAddress); err != nil {
+		return errors.New("[SideChain], GenesisAddress serialize failed.")
+	}
+	if err := WriteUint32(w, s.MinArbitratorSignatures); err != nil {
+		return errors.New("[SideChain], MinArbitratorSignatures serialize failed.")
+	}
+	if err := WriteUint32(w, s.RegisterHeight); err != nil {
+		return errors.New("[SideChain], RegisterHeight serialize failed.")
+	}
+	if err := s.Deposited.Serialize(w); err != nil {
+		return errors.New("[SideChain], Deposited serialize failed.")
+	}
+	if err := s.Withdrawn.Serialize(w); err != nil {
+		return errors.New("[SideChain], Withdrawn serialize failed.")
+	}
+	return nil
+}
+
+func (s *SideChain) Deserialize(r io.Reader) error {
+	var err error
+	if s.Name, err = ReadVarString(r); err != nil {
+		return errors.New("[SideChain], Name deserialize failed.")
+	}
+	if err := s.GenesisHash.Deserialize(r); err != nil {
+		return errors.New("[SideChain], GenesisHash deserialize failed.")
+	}
+	if s.GenesisAddress, err = ReadVarString(r); err != nil {
+		return errors.New("[SideChain], GenesisAddress deserialize failed.")
+	}
+	if s.MinArbitratorSignatures, err = ReadUint32(r); err != nil {
+		return errors.New("[SideChain], MinArbitratorSignatures deserialize failed.")
+	}
+	if s.RegisterHeight, err = ReadUint32(r); err != nil {
+		return errors.New("[SideChain], RegisterHeight deserialize failed.")
+	}
+	if err := s.Deposited.Deserialize(r); err != nil {
+		return errors.New("[SideChain], Deposited deserialize failed.")
+	}
+	if err := s.Withdrawn.Deserialize(r); err != nil {
+		return errors.New("[SideChain], Withdrawn deserialize failed.")
+	}
+	return nil
+}
+
+func getSideChainKey(genesisHash Uint256) []byte {
+	return append([]byte{byte(ST_SideChain)}, genesisHash.Bytes()...)
+}
+
+func getSideChainAddressKey(programHash Uint168) []byte {
+	return append([]byte{byte(IX_SideChain_Address)}, programHash.Bytes()...)
+}
+
+func (c *ChainStore) PersistSideChain(s *SideChain) error {
+	programHash, err := Uint168FromAddress(s.GenesisAddress)
+	if err != nil {
+		return err
+	}
+	value := new(bytes.Buffer)
+	if err := s.Serialize(value); err != nil {
+		return err
+	}
+	c.BatchPut(getSideChainKey(s.GenesisHash), value.Bytes())
+	c.BatchPut(getSideChainAddressKey(*programHash), s.GenesisHash.Bytes())
+	return nil
+}
+
+func (c *ChainStore) RollbackSideChain(s *SideChain) error {
+	programHash, err := Uint168FromAddress(s.GenesisAddress)
+	if err != nil {
+		return err
+	}
+	c.BatchDelete(getSideChainKey(s.GenesisHash))
+	c.BatchDelete(getSideChainAddressKey(*programHash))
+	return nil
+}
+
+func (c *ChainStore) GetSideChain(genesisHash Uint256) (*SideChain, error) {
+	data, err := c.Get(getSideChainKey(genesisHash))
+	if err != nil {
+		return nil, err
+	}
+	s := new(SideChain)
+	if err := s.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetSideChainByProgramHash returns the side chain of which the program hash
+// of the genesis address is programHash.
+func (c *ChainStore) GetSideChainByProgramHash(programHash Uint168) (*SideChain, error) {
+	data, err := c.Get(getSideChainAddressKey(programHash))
+	if err != nil {
+		return nil, err
+	}
+	genesisHash, err := Uint256FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetSideChain(*genesisHash)
+}
+
+// GetSideChains returns the registered side chains.
+func (c *ChainStore) GetSideChains() []*SideChain {
+	sideChains := make([]*SideChain, 0)
+	iter := c.NewIterator([]byte{byte(ST_SideChain)})
+	defer iter.Release()
+	for iter.Next() {
+		s := new(SideChain)
+		if err := s.Deserialize(bytes.NewReader(iter.Value())); err != nil {
+			continue
+		}
+		sideChains = append(sideChains, s)
+	}
+	return sideChains
+}
+
+// sideChainChanges accumulates the changes of the side chains made by a block.
+type sideChainChanges struct {
+	*stateChanges
+	store *ChainStore
+	// assetID is the ELA asset, the values of the other assets paid to the
+	// genesis addresses are not locked for the side chains
+	assetID Uint256
+	// txns are the transactions of the block, the outputs to the genesis
+	// addresses may be spent in the same block
+	txns map[Uint256]*Transaction
+}
+
+func newSideChainChanges(c *ChainStore, b *Block) *sideChainChanges {
+	sc := &sideChainChanges{
+		stateChanges: newStateChanges(func(key string) (interface{}, error) {
+			programHash, err := Uint168FromBytes([]byte(key))
+			if err != nil {
+				return nil, err
+			}
+			return c.GetSideChainByProgramHash(*programHash)
+		}),
+		store:   c,
+		assetID: DefaultLedger.Blockchain.AssetID,
+		txns:    make(map[Uint256]*Transaction, len(b.Transactions)),
+	}
+	for _, txn := range b.Transactions {
+		sc.txns[txn.Hash()] = txn
+	}
+	return sc
+}
+
+func (sc *sideChainChanges) get(programHash Uint168) *SideChain {
+	s, err := sc.stateChanges.get(string(programHash[:]))
+	if err != nil {
+		return nil
+	}
+	return s.(*SideChain)
+}
+
+// getWithdrawn returns the ELA value the withdrawal spends from the genesis
+// address, less the change paid back to it.
+func (sc *sideChainChanges) getWithdrawn(txn *Transaction, programHash Uint168) (Fixed64, error) {
+	var withdrawn Fixed64
+	for _, input := range txn.Inputs {
+		referTxn, ok := sc.txns[input.Previous.TxID]
+		if !ok {
+			var err error
+			referTxn, _, err = sc.store.GetTransaction(input.Previous.TxID)
+			if err != nil {
+				return 0, err
+			}
+		}
+		output := referTxn.Outputs[input.Previous.Index]
+		if output.AssetID == sc.assetID && output.ProgramHash == programHash {
+			withdrawn += output.Value
+		}
+	}
+	for _, output := range txn.Outputs {
+		if output.AssetID == sc.assetID && output.ProgramHash == programHash {
+			withdrawn -= output.Value
+		}
+	}
+	return withdrawn, nil
+}
+
+// getUnspent returns the unspent ELA value of the genesis address before the
+// transaction at the index of the block, so the value locked before the side
+// chain is registered is counted as deposited.
+func (sc *sideChainChanges) getUnspent(b *Block, index int, programHash Uint168) (Fixed64, error) {
+	var unspent Fixed64
+	utxos, err := sc.store.GetUnspentsFromProgramHash(programHash)
+	if err != nil {
+		return 0, err
+	}
+	for _, utxo := range utxos[sc.assetID] {
+		unspent += utxo.Value
+	}
+	// the unspents of the block are not persisted yet
+	for _, txn := range b.Transactions[:index] {
+		withdrawn, err := sc.getWithdrawn(txn, programHash)
+		if err != nil {
+			return 0, err
+		}
+		unspent -= withdrawn
+	}
+	return unspent, nil
+}
+
+// update applies the changes of the side chains made by the block, the values
+// are negative if the block is rolled back.
+func (sc *sideChainChanges) update(b *Block, rollback bool) error {
+	sign := Fixed64(1)
+	if rollback {
+		sign = -1
+	}
+	for i, txn := range b.Transactions {
+		switch payload := txn.Payload.(type) {
+		case *PayloadRegisterSideChain:
+			programHash, err := Uint168FromAddress(payload.GenesisAddress)
+			if err != nil {
+				return err
+			}
+			s := &SideChain{
+				Name:                    payload.Name,
+				GenesisHash:             payload.GenesisHash,
+				GenesisAddress:          payload.GenesisAddress,
+				MinArbitratorSignatures: payload.MinArbitratorSignatures,
+				RegisterHeight:          b.Header.Height,
+			}
+			if rollback {
+				sc.remove(string(programHash[:]))
+				continue
+			}
+			if s.Deposited, err = sc.getUnspent(b, i, *programHash); err != nil {
+				return err
+			}
+			sc.put(string(programHash[:]), s)
+		case *PayloadTransferCrossChainAsset:
+			for _, index := range payload.OutputIndexes {
+				output := txn.Outputs[index]
+				if output.AssetID != sc.assetID {
+					continue
+				}
+				if s := sc.get(output.ProgramHash); s != nil {
+					s.Deposited += sign * output.Value
+				}
+			}
+		case *PayloadWithdrawFromSideChain:
+			programHash, err := Uint168FromAddress(payload.GenesisBlockAddress)
+			if err != nil {
+				continue
+			}
+			s := sc.get(*programHash)
+			if s == nil {
+				continue
+			}
+			withdrawn, err := sc.getWithdrawn(txn, *programHash)
+			if err != nil {
+				return err
+			}
+			s.Withdrawn += sign * withdrawn
+		}
+	}
+	return sc.commit()
+}
+
+func (sc *sideChainChanges) commit() error {
+	return sc.stateChanges.commit(
+		func(key string, state interface{}) error {
+			return sc.store.PersistSideChain(state.(*SideChain))
+		},
+		func(key string) error {
+			// the side chain registered by the rolled back block is stored
+			programHash, err := Uint168FromBytes([]byte(key))
+			if err != nil {
+				return err
+			}
+			s, err := sc.store.GetSideChainByProgramHash(*programHash)
+			if err != nil {
+				return err
+			}
+			return sc.store.RollbackSideChain(s)
+		})
+}
+
+// PersistSideChains updates the side chains registered by the block, and the
+// values deposited to and withdrawn from them.
+func (c *ChainStore) PersistSideChains(b *Block) error {
+	return newSideChainChanges(c, b).update(b, false)
+}
+
+// RollbackSideChains reverts the changes of the side chains made by the block.
+func (c *ChainStore) RollbackSideChains(b *Block) error {
+	return newSideChainChanges(c, b).update(b, true)
+}
+
+// isSideChainRequired checks if the side chains of the transactions in the
+// next block must be registered.
+func isSideChainRequired() bool {
+	return DefaultLedger.Store.GetHeight()+1 >= config.Parameters.ChainParam.SideChainActivationHeight
+}
+
+func checkRegisterSideChainPayload(txn *Transaction) error {
+	payload, ok := txn.Payload.(*PayloadRegisterSideChain)
+	if !ok {
+		return errors.New("invalid register side chain payload")
+	}
+	if payload.GenesisHash == EmptyHash {
+		return errors.New("invalid side chain genesis hash")
+	}
+	programHash, err := Uint168FromAddress(payload.GenesisAddress)
+	if err != nil || programHash[0] != PrefixCrossChain {
+		return errors.New("invalid side chain genesis address")
+	}
+	if len(payload.Name) == 0 || len(payload.Name) > MaxSideChainNameLength {
+		return errors.New("invalid side chain name")
+	}
+	if payload.MinArbitratorSignatures == 0 {
+		return errors.New("invalid side chain arbitrator signatures count")
+	}
+	return nil
+}
+
+// CheckRegisterSideChainTransaction checks the side chain is not registered,
+// and the arbitrators can sign its withdrawals. The signature of the current
+// arbitrators is checked with the program hashes of the transaction.
+func CheckRegisterSideChainTransaction(txn *Transaction) error {
+	payload, ok := txn.Payload.(*PayloadRegisterSideChain)
+	if !ok {
+		return errors.New("invalid register side chain payload")
+	}
+	if _, err := DefaultLedger.Store.GetSideChain(payload.GenesisHash); err == nil {
+		return errors.New("side chain already registered")
+	}
+	programHash, err := Uint168FromAddress(payload.GenesisAddress)
+	if err != nil {
+		return err
+	}
+	if _, err := DefaultLedger.Store.GetSideChainByProgramHash(*programHash); err == nil {
+		return errors.New("side chain genesis address already registered")
+	}
+	arbitrators, err := DefaultLedger.Store.GetArbitrators(DefaultLedger.Store.GetHeight())
+	if err != nil {
+		return err
+	}
+	if int(payload.MinArbitratorSignatures) > len(arbitrators) {
+		return errors.New("side chain arbitrator signatures count greater than the arbitrators")
+	}
+	return nil
+}
+
+// checkCrossChainOutput checks the output of a cross chain transfer is paid
+// to the genesis address of a registered side chain.
+func checkCrossChainOutput(output *Output) error {
+	if !isSideChainRequired() {
+		return nil
+	}
+	if _, err := DefaultLedger.Store.GetSideChainByProgramHash(output.ProgramHash); err != nil {
+		return errors.New("Invalid transaction output address, side chain not registered")
+	}
+	return nil
+}
+
+// checkSideChainWithdrawal checks the withdrawal is from a registered side
+// chain, only spends the genesis address of the side chain, and is signed by
+// enough arbitrators.
+func checkSideChainWithdrawal(txn *Transaction, references map[*Input]*Output) error {
+	if !isSideChainRequired() {
+		return nil
+	}
+	payload := txn.Payload.(*PayloadWithdrawFromSideChain)
+	programHash, err := Uint168FromAddress(payload.GenesisBlockAddress)
+	if err != nil {
+		return errors.New("Invalid side chain genesis address")
+	}
+	sideChain, err := DefaultLedger.Store.GetSideChainByProgramHash(*programHash)
+	if err != nil {
+		return errors.New("Side chain not registered")
+	}
+	for _, output := range references {
+		if output.ProgramHash != *programHash {
+			return errors.New("Invalid transaction inputs address, not the genesis address of the side chain")
+		}
+	}
+	for _, program := range txn.Programs {
+		signType, err := crypto.GetScriptType(program.Code)
+		if err != nil || signType != CROSSCHAIN {
+			continue
+		}
+		m := int(program.Code[0]) - crypto.PUSH1 + 1
+		if m < int(sideChain.MinArbitratorSignatures) {
+			return errors.New("Arbitrator signatures of the withdrawal not enough")
+		}
+	}
+	return nil
+}
+
+// checkSideChainPowGenesis checks the side chain mined by the sidechainpow
+// transaction is registered.
+func checkSideChainPowGenesis(txn *Transaction) error {
+	if !isSideChainRequired() {
+		return nil
+	}
+	payload := txn.Payload.(*PayloadSideChainPow)
+	if _, err := DefaultLedger.Store.GetSideChain(payload.SideGenesisHash); err != nil {
+		return errors.New("Side chain not registered")
+	}
+	return nil
+}
+
+func registerSideChainConflictKeys(txn *Transaction) []string {
+	payload, ok := txn.Payload.(*PayloadRegisterSideChain)
+	if !ok {
+		return nil
+	}
+	return []string{
+		"sidechain:" + payload.GenesisHash.String(),
+		"sidechainaddress:" + payload.GenesisAddress,
+	}
+}
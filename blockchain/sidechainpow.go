@@ -0,0 +1,85 @@
+package blockchain
+
+import (
+	"bytes"
+
+	. "github.com/wuyazero/Elastos.ELA/core"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+// SideChainPowIndex locates a side chain block anchored on chain by a
+// SideChainPow transaction.
+type SideChainPowIndex struct {
+	SideGenesisHash Uint256
+	SideBlockHash   Uint256
+	SideBlockHeight uint32
+	TxID            Uint256
+	Height          uint32
+}
+
+func getSideChainPowPrefix(genesisHash Uint256) []byte {
+	return append([]byte{byte(IX_SideChain_Pow)}, genesisHash.Bytes()...)
+}
+
+// getSideChainPowKey returns the key of the anchored side chain block, indexed
+// by the side block height.
+func getSideChainPowKey(genesisHash Uint256, sideHeight uint32, txId Uint256) []byte {
+	return getHeightIndexKey(getSideChainPowPrefix(genesisHash), sideHeight, txId)
+}
+
+// PersistSideChainPow indexes the side chain block anchored by the transaction
+// by the side chain genesis hash and the side block height.
+func (c *ChainStore) PersistSideChainPow(txn *Transaction, height uint32) error {
+	payload, ok := txn.Payload.(*PayloadSideChainPow)
+	if !ok {
+		return nil
+	}
+	value := new(bytes.Buffer)
+	if err := payload.SideBlockHash.Serialize(value); err != nil {
+		return err
+	}
+	if err := WriteUint32(value, height); err != nil {
+		return err
+	}
+	c.BatchPut(getSideChainPowKey(payload.SideGenesisHash, payload.BlockHeight, txn.Hash()), value.Bytes())
+	return nil
+}
+
+// RollbackSideChainPow removes the side chain block anchored by the
+// transaction from the index.
+func (c *ChainStore) RollbackSideChainPow(txn *Transaction) error {
+	payload, ok := txn.Payload.(*PayloadSideChainPow)
+	if !ok {
+		return nil
+	}
+	c.BatchDelete(getSideChainPowKey(payload.SideGenesisHash, payload.BlockHeight, txn.Hash()))
+	return nil
+}
+
+// GetSideChainPows returns the anchored blocks of the side chain between the
+// side block heights from and to inclusive, in side block height order.
+func (c *ChainStore) GetSideChainPows(genesisHash Uint256, from, to uint32) ([]*SideChainPowIndex, error) {
+	pows := make([]*SideChainPowIndex, 0)
+	iter := c.newHeightIndexIterator(getSideChainPowPrefix(genesisHash), from, to)
+	defer iter.Release()
+	for iter.Next() {
+		value := bytes.NewReader(iter.Value())
+		var sideBlockHash Uint256
+		if err := sideBlockHash.Deserialize(value); err != nil {
+			return nil, err
+		}
+		height, err := ReadUint32(value)
+		if err != nil {
+			return nil, err
+		}
+		pows = append(pows, &SideChainPowIndex{
+			SideGenesisHash: genesisHash,
+			SideBlockHash:   sideBlockHash,
+			SideBlockHeight: iter.Height(),
+			TxID:            iter.Hash(),
+			Height:          height,
+		})
+	}
+	return pows, nil
+}
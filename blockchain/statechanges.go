@@ -0,0 +1,64 @@
+package blockchain
+
+// stateChanges accumulates the changes of the states made by a block, because
+// the batch is not readable before committed. A state is loaded from the store
+// when it is first changed, and the changed states are written to the batch
+// together when the block is done.
+type stateChanges struct {
+	states  map[string]interface{}
+	removed map[string]struct{}
+	// load reads the state of the key from the store.
+	load func(key string) (interface{}, error)
+}
+
+func newStateChanges(load func(key string) (interface{}, error)) *stateChanges {
+	return &stateChanges{
+		states:  make(map[string]interface{}),
+		removed: make(map[string]struct{}),
+		load:    load,
+	}
+}
+
+// get returns the changed state of the key, or loads it from the store.
+func (sc *stateChanges) get(key string) (interface{}, error) {
+	if state, ok := sc.states[key]; ok {
+		return state, nil
+	}
+	state, err := sc.load(key)
+	if err != nil {
+		return nil, err
+	}
+	sc.states[key] = state
+	return state, nil
+}
+
+// put replaces the state of the key.
+func (sc *stateChanges) put(key string, state interface{}) {
+	sc.states[key] = state
+	delete(sc.removed, key)
+}
+
+// remove deletes the state of the key when committed.
+func (sc *stateChanges) remove(key string) {
+	sc.removed[key] = struct{}{}
+}
+
+// commit writes the changed states to the batch with persist and the removed
+// ones with rollback.
+func (sc *stateChanges) commit(persist func(key string, state interface{}) error,
+	rollback func(key string) error) error {
+	for key, state := range sc.states {
+		if _, ok := sc.removed[key]; ok {
+			continue
+		}
+		if err := persist(key, state); err != nil {
+			return err
+		}
+	}
+	for key := range sc.removed {
+		if err := rollback(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
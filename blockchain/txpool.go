@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	. "github.com/wuyazero/Elastos.ELA.Utility/common"
 	"github.com/wuyazero/Elastos.ELA/config"
@@ -14,13 +16,54 @@ import (
 	"github.com/wuyazero/Elastos.ELA/log"
 )
 
+const (
+	// DefaultTxPoolExpiry is the max age of a transaction in txnpool when
+	// TxPoolExpiry is not configured.
+	DefaultTxPoolExpiry = 72 * time.Hour
+
+	// SideChainPowExpiryBlocks is the count of blocks after which a
+	// sidechainpow transaction in txnpool expires, it is useless once newer
+	// side chain blocks have been mined.
+	SideChainPowExpiryBlocks = 6
+
+	// WithdrawExpiryBlocks is the count of blocks after which a withdraw
+	// transaction in txnpool expires, the side chain transactions it holds
+	// can not be withdrawn by another transaction until it is removed, so the
+	// arbiters get the chance to submit the withdrawal again long before the
+	// age expiry.
+	WithdrawExpiryBlocks = 36
+)
+
+// TxPoolEntry is the metadata of a transaction in txnpool.
+type TxPoolEntry struct {
+	Tx     *Transaction
+	Time   time.Time // the time when the transaction is added
+	Height uint32    // the best height when the transaction is added
+	Size   int
+}
+
+// TxPoolInfo is the summary of txnpool.
+type TxPoolInfo struct {
+	Count  int
+	Bytes  int
+	Usage  int
+	MinFee Fixed64
+}
+
 type TxPool struct {
 	sync.RWMutex
-	txnCnt  uint64                   // count
-	txnList map[Uint256]*Transaction // transaction which have been verifyed will put into this map
+	txnCnt     uint64                   // count
+	txnList    map[Uint256]*Transaction // transaction which have been verifyed will put into this map
+	txnEntries map[Uint256]*TxPoolEntry // metadata of transactions in txnList
 	//issueSummary  map[Uint256]Fixed64           // transaction which pass the verify will summary the amout to this map
-	inputUTXOList   map[string]*Transaction  // transaction which pass the verify will add the UTXO to this map
-	sidechainTxList map[Uint256]*Transaction // sidechain tx pool
+	inputUTXOList map[string]*Transaction // transaction which pass the verify will add the UTXO to this map
+	conflictList  map[string]*Transaction // transaction which holds the conflict keys, such as sidechain txs
+	feeEstimator  *FeeEstimator           // estimate fee rate by confirmed pool transactions
+
+	chainMutex                      sync.Mutex // serialize the handling of chain events
+	chainEvents                     *events.Event
+	blockPersistCompletedSubscriber events.Subscriber
+	rollbackTransactionSubscriber   events.Subscriber
 }
 
 func (pool *TxPool) Init() {
@@ -30,46 +73,123 @@ func (pool *TxPool) Init() {
 	pool.inputUTXOList = make(map[string]*Transaction)
 	//pool.issueSummary = make(map[Uint256]Fixed64)
 	pool.txnList = make(map[Uint256]*Transaction)
-	pool.sidechainTxList = make(map[Uint256]*Transaction)
+	pool.txnEntries = make(map[Uint256]*TxPoolEntry)
+	pool.conflictList = make(map[string]*Transaction)
+	pool.feeEstimator = NewFeeEstimator()
+	if data, err := DefaultLedger.Store.GetFeeEstimator(); err == nil {
+		if err := pool.feeEstimator.Deserialize(bytes.NewReader(data)); err != nil {
+			log.Warn("restore fee estimator failed,", err)
+			pool.feeEstimator = NewFeeEstimator()
+		}
+	}
+
+	// keep txnpool consistent with the best chain when blocks are
+	// connected or disconnected
+	if pool.chainEvents != nil {
+		pool.chainEvents.UnSubscribe(events.EventBlockPersistCompleted, pool.blockPersistCompletedSubscriber)
+		pool.chainEvents.UnSubscribe(events.EventRollbackTransaction, pool.rollbackTransactionSubscriber)
+	}
+	pool.chainEvents = DefaultLedger.Blockchain.BCEvents
+	pool.blockPersistCompletedSubscriber = pool.chainEvents.Subscribe(events.EventBlockPersistCompleted, pool.BlockPersistCompleted)
+	pool.rollbackTransactionSubscriber = pool.chainEvents.Subscribe(events.EventRollbackTransaction, pool.RollbackTransaction)
 }
 
-//append transaction to txnpool when check ok.
-//1.check  2.check with ledger(db) 3.check with pool
-func (pool *TxPool) AppendToTxnPool(txn *Transaction) ErrCode {
+//clean txnpool with the block connected to the best chain, the withdrawals
+//of the block are published confirmed before the pool transactions dropped
+func (pool *TxPool) BlockPersistCompleted(v interface{}) {
+	block, ok := v.(*Block)
+	if !ok {
+		return
+	}
+	pool.chainMutex.Lock()
+	defer pool.chainMutex.Unlock()
 
-	if txn.IsCoinBaseTx() {
-		log.Warn("coinbase cannot be added into transaction pool", txn.Hash().String())
-		return ErrIneffectiveCoinbase
+	notifyWithdrawTransitions(block.Transactions, WithdrawConfirmed, block.Header.Height)
+
+	if err := pool.CleanSubmittedTransactions(block); err != nil {
+		log.Warn(err)
 	}
+}
 
-	//verify transaction with Concurrency
-	if errCode := CheckTransactionSanity(CheckTxOut, txn); errCode != Success {
-		log.Warn("[TxPool CheckTransactionSanity] failed", txn.Hash().String())
-		return errCode
+//re-admit the transactions of the block disconnected from the best chain,
+//transactions spending outputs of the same block can not be re-admitted
+//because their references are not in chain.
+func (pool *TxPool) RollbackTransaction(v interface{}) {
+	block, ok := v.(*Block)
+	if !ok {
+		return
 	}
-	if errCode := CheckTransactionContext(txn); errCode != Success {
-		log.Warn("[TxPool CheckTransactionContext] failed", txn.Hash().String())
+	pool.chainMutex.Lock()
+	defer pool.chainMutex.Unlock()
+
+	notifyWithdrawTransitions(block.Transactions, WithdrawRolledBack, block.Header.Height)
+	for _, txn := range block.Transactions {
+		if txn.IsCoinBaseTx() {
+			continue
+		}
+		if err := pool.MaybeAcceptTransaction(txn); err != nil {
+			log.Debug("re-admit transaction failed, txid=", txn.Hash().String(), err)
+		}
+	}
+	pool.evictInvalidTransactions()
+}
+
+//append transaction to txnpool when check ok.
+//1.check  2.check with ledger(db) 3.check with pool
+func (pool *TxPool) AppendToTxnPool(txn *Transaction) ErrCode {
+
+	//check transaction the same way as the dry run
+	if errCode := pool.CheckTransaction(txn); errCode != Success {
+		log.Warn("[TxPool CheckTransaction] failed", txn.Hash().String(), errCode.Message())
 		return errCode
 	}
-	//verify transaction by pool with lock
+	//reserve the inputs and conflict keys in pool with lock
 	if errCode := pool.verifyTransactionWithTxnPool(txn); errCode != Success {
 		log.Warn("[TxPool verifyTransactionWithTxnPool] failed", txn.Hash())
 		return errCode
 	}
 
-	txn.Fee = GetTxFee(txn, DefaultLedger.Blockchain.AssetID)
-	buf := new(bytes.Buffer)
-	txn.Serialize(buf)
-	txn.FeePerKB = txn.Fee * 1000 / Fixed64(len(buf.Bytes()))
+	setTxFee(txn)
 	//add the transaction to process scope
 	if ok := pool.addToTxList(txn); !ok {
 		// reject duplicated transaction
 		log.Debugf("Transaction duplicate %s", txn.Hash().String())
 		return ErrTransactionDuplicate
 	}
+	pool.feeEstimator.ObserveTransaction(txn, DefaultLedger.Store.GetHeight())
 	return Success
 }
 
+//check if the transaction would be accepted by txnpool, neither the pool nor
+//the transaction will be modified. AppendToTxnPool runs the same checks before
+//it modifies the pool, so the dry run can not drift from the admission.
+func (pool *TxPool) CheckTransaction(txn *Transaction) ErrCode {
+	if txn.IsCoinBaseTx() {
+		return ErrIneffectiveCoinbase
+	}
+
+	if errCode := CheckTransactionSanity(CheckTxOut, txn); errCode != Success {
+		return errCode
+	}
+	if errCode := CheckTransactionContext(txn); errCode != Success {
+		return errCode
+	}
+
+	return pool.checkTransactionWithTxnPool(txn)
+}
+
+func setTxFee(txn *Transaction) {
+	txn.Fee, txn.FeePerKB = GetTxFeeRate(txn)
+}
+
+//get the ELA fee and the fee per KB of the transaction
+func GetTxFeeRate(txn *Transaction) (Fixed64, Fixed64) {
+	fee := GetTxFee(txn, DefaultLedger.Blockchain.AssetID)
+	buf := new(bytes.Buffer)
+	txn.Serialize(buf)
+	return fee, fee * 1000 / Fixed64(len(buf.Bytes()))
+}
+
 //get the transaction in txnpool
 func (pool *TxPool) GetTransactionPool(hasMaxCount bool) map[Uint256]*Transaction {
 	pool.RLock()
@@ -95,13 +215,115 @@ func (pool *TxPool) GetTransactionPool(hasMaxCount bool) map[Uint256]*Transactio
 
 //clean the trasaction Pool with committed block.
 func (pool *TxPool) CleanSubmittedTransactions(block *Block) error {
+	pool.feeEstimator.ProcessBlock(block)
 	pool.cleanTransactions(block.Transactions)
-	pool.cleanSidechainTx(block.Transactions)
+	pool.cleanConflictTransactions(block.Transactions)
 	pool.cleanSideChainPowTx()
+	pool.evictInvalidTransactions()
+	pool.expireTransactions(time.Now(), block.Height)
+	pool.saveFeeEstimator()
 
 	return nil
 }
 
+//remove the transactions in txnpool which become invalid with the current best chain
+func (pool *TxPool) evictInvalidTransactions() {
+	for _, txn := range pool.copyTxList() {
+		if err := checkTransactionWithChain(txn); err != nil {
+			log.Info("transaction evicted from txpool, txid=", txn.Hash().String(), err)
+			pool.removeFromPool(txn)
+		}
+	}
+}
+
+//check the parts of a pool transaction which may change with the best chain
+func checkTransactionWithChain(txn *Transaction) error {
+	if DefaultLedger.Store.IsTxHashDuplicate(txn.Hash()) {
+		return errors.New("transaction already in chain")
+	}
+	if DefaultLedger.IsDoubleSpend(txn) {
+		return errors.New("transaction inputs already spent")
+	}
+	if _, err := DefaultLedger.Store.GetTxReference(txn); err != nil {
+		return errors.New("transaction references not found")
+	}
+	if err := CheckTransactionCoinbaseOutputLock(txn); err != nil {
+		return err
+	}
+	if _, err := checkTxTypeContext(txn); err != nil {
+		return err
+	}
+	return nil
+}
+
+//remove the transactions which stay in txnpool for too long
+func (pool *TxPool) expireTransactions(now time.Time, bestHeight uint32) {
+	expiry := time.Duration(config.Parameters.TxPoolExpiry) * time.Second
+	if expiry <= 0 {
+		expiry = DefaultTxPoolExpiry
+	}
+
+	var expired []*Transaction
+	// only the transactions not confirmed in time tell the fee is too low, the
+	// sidechainpow and withdraw transactions expire for their own rules
+	timedOut := make(map[*Transaction]bool)
+	pool.RLock()
+	for _, entry := range pool.txnEntries {
+		if now.Sub(entry.Time) >= expiry {
+			expired = append(expired, entry.Tx)
+			timedOut[entry.Tx] = true
+		} else if entry.Tx.IsSideChainPowTx() &&
+			bestHeight >= entry.Height+SideChainPowExpiryBlocks {
+			expired = append(expired, entry.Tx)
+		} else if entry.Tx.IsWithdrawFromSideChainTx() &&
+			bestHeight >= entry.Height+WithdrawExpiryBlocks {
+			expired = append(expired, entry.Tx)
+		}
+	}
+	pool.RUnlock()
+
+	for _, txn := range expired {
+		log.Info("transaction expired in txpool, txid=", txn.Hash().String())
+		pool.feeEstimator.RemoveTransaction(txn.Hash(), timedOut[txn])
+		pool.removeFromPool(txn)
+	}
+}
+
+//remove the transaction and the inputs and conflict keys it holds from txnpool,
+//and publish the removal so wallets and websocket clients learn it was dropped,
+//the transactions included in the block are removed by cleanTransactions
+func (pool *TxPool) removeFromPool(txn *Transaction) {
+	//1.remove from txnList
+	removed := pool.delFromTxList(txn.Hash())
+	//2.remove from UTXO list map
+	for _, input := range txn.Inputs {
+		pool.delInputUTXOList(input)
+	}
+	//3.release the conflict keys so the conflicting transactions can be submitted again
+	pool.delConflictKeys(txn)
+	//4.publish the removal
+	if removed {
+		DefaultLedger.Blockchain.BCEvents.Notify(events.EventRemoveTransactionFromPool, txn)
+		notifyWithdrawTransitions([]*Transaction{txn}, WithdrawDropped, 0)
+	}
+}
+
+//estimate the fee rate to get a transaction confirmed within target blocks
+func (pool *TxPool) EstimateSmartFee(target uint32) (*FeeEstimate, error) {
+	return pool.feeEstimator.EstimateSmartFee(target)
+}
+
+func (pool *TxPool) saveFeeEstimator() {
+	buf := new(bytes.Buffer)
+	if err := pool.feeEstimator.Serialize(buf); err != nil {
+		log.Warn("serialize fee estimator failed,", err)
+		return
+	}
+	if err := DefaultLedger.Store.PersistFeeEstimator(buf.Bytes()); err != nil {
+		log.Warn("persist fee estimator failed,", err)
+	}
+}
+
 func (pool *TxPool) cleanTransactions(blockTxs []*Transaction) error {
 	txCountInPool := pool.GetTransactionCount()
 	deleteCount := 0
@@ -125,28 +347,21 @@ func (pool *TxPool) cleanTransactions(blockTxs []*Transaction) error {
 					// other. This is a special case of what we've said above.
 					log.Debugf("duplicated transactions detected when adding a new block. "+
 						" Delete transaction in the transaction pool. Transaction id: %x", tx.Hash())
+					//1.remove from txnList
+					pool.delFromTxList(tx.Hash())
+					//2.remove from UTXO list map
+					for _, input := range tx.Inputs {
+						pool.delInputUTXOList(input)
+					}
+					//3.remove from conflict list map
+					pool.delConflictKeys(tx)
 				} else {
 					log.Debugf("double spent UTXO inputs detected in transaction pool when adding a new block. "+
 						"Delete transaction in the transaction pool. "+
 						"block transaction hash: %x, transaction hash: %x, the same input: %s, index: %d",
 						blockTx.Hash(), tx.Hash(), input.Previous.TxID, input.Previous.Index)
-				}
-				//1.remove from txnList
-				pool.delFromTxList(tx.Hash())
-				//2.remove from UTXO list map
-				for _, input := range tx.Inputs {
-					pool.delInputUTXOList(input)
-				}
-
-				//delete sidechain tx list
-				if tx.TxType == WithdrawFromSideChain {
-					payload, ok := tx.Payload.(*PayloadWithdrawFromSideChain)
-					if !ok {
-						log.Error("type cast failed when clean sidechain tx:", tx.Hash())
-					}
-					for _, hash := range payload.SideChainTransactionHashes {
-						pool.delSidechainTx(hash)
-					}
+					// the double spent transaction is dropped
+					pool.removeFromPool(tx)
 				}
 				deleteCount++
 			}
@@ -165,17 +380,74 @@ func (pool *TxPool) GetTransaction(hash Uint256) *Transaction {
 	return pool.txnList[hash]
 }
 
-//verify transaction with txnpool
+//get the metadata of the transaction in txnpool by hash
+func (pool *TxPool) GetTxPoolEntry(hash Uint256) *TxPoolEntry {
+	pool.RLock()
+	defer pool.RUnlock()
+	entry, ok := pool.txnEntries[hash]
+	if !ok {
+		return nil
+	}
+	e := *entry
+	return &e
+}
+
+//get the metadata of all transactions in txnpool, sorted by the time they are added
+func (pool *TxPool) GetTxPoolEntries() []*TxPoolEntry {
+	pool.RLock()
+	entries := make([]*TxPoolEntry, 0, len(pool.txnEntries))
+	for _, entry := range pool.txnEntries {
+		e := *entry
+		entries = append(entries, &e)
+	}
+	pool.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Time.Equal(entries[j].Time) {
+			hi, hj := entries[i].Tx.Hash(), entries[j].Tx.Hash()
+			return bytes.Compare(hi[:], hj[:]) < 0
+		}
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries
+}
+
+//get the summary of txnpool
+func (pool *TxPool) GetTxPoolInfo() *TxPoolInfo {
+	pool.RLock()
+	defer pool.RUnlock()
+	info := &TxPoolInfo{
+		Count:  len(pool.txnList),
+		MinFee: Fixed64(config.Parameters.PowConfiguration.MinTxFee),
+	}
+	for _, entry := range pool.txnEntries {
+		info.Bytes += entry.Size
+	}
+	// estimated memory used by the transactions and the indexes, the key of
+	// inputUTXOList is the hex string of an outpoint (68 bytes), values and
+	// map entries are counted as pointers (8 bytes)
+	info.Usage = info.Bytes + len(pool.txnList)*(UINT256SIZE+8) +
+		len(pool.txnEntries)*(UINT256SIZE+8) +
+		len(pool.inputUTXOList)*(68+8)
+	for key := range pool.conflictList {
+		info.Usage += len(key) + 8
+	}
+	return info
+}
+
+//reserve the conflict keys and inputs of the transaction checked by
+//CheckTransaction, the checks are repeated with the reservation in case of a
+//transaction appended concurrently
 func (pool *TxPool) verifyTransactionWithTxnPool(txn *Transaction) ErrCode {
 	if txn.IsSideChainPowTx() {
 		// check and replace the duplicate sidechainpow tx
 		pool.replaceDuplicateSideChainPowTx(txn)
-	} else if txn.IsWithdrawFromSideChainTx() {
-		// check if the withdraw transaction includes duplicate sidechain tx in pool
-		if err := pool.verifyDuplicateSidechainTx(txn); err != nil {
-			log.Warn(err)
-			return ErrSidechainTxDuplicate
-		}
+	}
+
+	// check if the transaction holds conflict keys of transactions in pool
+	if err := pool.verifyConflictKeys(txn); err != nil {
+		log.Warn(err)
+		return getConflictErrCode(txn)
 	}
 
 	// check if the transaction includes double spent UTXO inputs
@@ -183,23 +455,82 @@ func (pool *TxPool) verifyTransactionWithTxnPool(txn *Transaction) ErrCode {
 		log.Warn(err)
 		return ErrDoubleSpend
 	}
+	pool.addConflictKeys(txn)
 
 	return Success
 }
 
-//remove from associated map
-func (pool *TxPool) removeTransaction(txn *Transaction) {
-	//1.remove from txnList
-	pool.delFromTxList(txn.Hash())
-	//2.remove from UTXO list map
-	result, err := DefaultLedger.Store.GetTxReference(txn)
-	if err != nil {
-		log.Info(fmt.Sprintf("Transaction =%x not Exist in Pool when delete.", txn.Hash()))
-		return
+//check transaction with txnpool without modifying the pool
+func (pool *TxPool) checkTransactionWithTxnPool(txn *Transaction) ErrCode {
+	pool.RLock()
+	defer pool.RUnlock()
+
+	if _, ok := pool.txnList[txn.Hash()]; ok {
+		return ErrTransactionDuplicate
 	}
-	for UTXOTxInput := range result {
-		pool.delInputUTXOList(UTXOTxInput)
+
+	for _, key := range getConflictKeys(txn) {
+		if _, ok := pool.conflictList[key]; ok {
+			return getConflictErrCode(txn)
+		}
+	}
+
+	if err := pool.checkMintingSupply(txn); err != nil {
+		log.Warn("[checkTransactionWithTxnPool],", err)
+		return ErrAssetSupply
+	}
+
+	for _, input := range txn.Inputs {
+		poolTx, ok := pool.inputUTXOList[input.ReferKey()]
+		if !ok {
+			continue
+		}
+		// the sidechainpow transaction with the same genesis hash will be replaced
+		if txn.IsSideChainPowTx() && poolTx.IsSideChainPowTx() &&
+			isSameSideChainPow(txn, poolTx) {
+			continue
+		}
+		return ErrDoubleSpend
+	}
+
+	return Success
+}
+
+// checkMintingSupply checks the amount minted by the transaction together with
+// the transactions in the pool does not exceed the max supply of the asset, the
+// caller must hold the pool lock.
+func (pool *TxPool) checkMintingSupply(txn *Transaction) error {
+	payload, ok := txn.Payload.(*PayloadMintAsset)
+	if !ok {
+		return nil
+	}
+	amount := payload.Amount + pool.getMintingAmount(payload.AssetID)
+	return CheckAssetMaxSupply(payload.AssetID, amount)
+}
+
+// getMintingAmount returns the amount of the asset minted by the transactions
+// in the pool.
+func (pool *TxPool) getMintingAmount(assetID Uint256) Fixed64 {
+	var amount Fixed64
+	for _, tx := range pool.txnList {
+		if payload, ok := tx.Payload.(*PayloadMintAsset); ok &&
+			payload.AssetID == assetID {
+			amount += payload.Amount
+		}
 	}
+	return amount
+}
+
+func isSameSideChainPow(txn1, txn2 *Transaction) bool {
+	payload1, ok := txn1.Payload.(*PayloadSideChainPow)
+	if !ok {
+		return false
+	}
+	payload2, ok := txn2.Payload.(*PayloadSideChainPow)
+	if !ok {
+		return false
+	}
+	return payload1.SideGenesisHash.IsEqual(payload2.SideGenesisHash)
 }
 
 //check and add to utxo list pool
@@ -225,74 +556,59 @@ func (pool *TxPool) verifyDoubleSpend(txn *Transaction) error {
 }
 
 func (pool *TxPool) IsDuplicateSidechainTx(sidechainTxHash Uint256) bool {
-	_, ok := pool.sidechainTxList[sidechainTxHash]
-	if ok {
-		return true
-	}
-
-	return false
+	pool.RLock()
+	defer pool.RUnlock()
+	_, ok := pool.conflictList[sidechainTxKey(sidechainTxHash)]
+	return ok
 }
 
-//check and add to sidechain tx pool
-func (pool *TxPool) verifyDuplicateSidechainTx(txn *Transaction) error {
-	withPayload, ok := txn.Payload.(*PayloadWithdrawFromSideChain)
-	if !ok {
-		return errors.New("convert the payload of withdraw tx failed")
-	}
+//get the withdraw transaction in txnpool holding the sidechain tx, nil if not found
+func (pool *TxPool) GetWithdrawTransaction(sidechainTxHash Uint256) *Transaction {
+	pool.RLock()
+	defer pool.RUnlock()
+	return pool.conflictList[sidechainTxKey(sidechainTxHash)]
+}
 
-	for _, hash := range withPayload.SideChainTransactionHashes {
-		_, ok := pool.sidechainTxList[hash]
-		if ok {
-			return errors.New("duplicate sidechain tx detected")
+//check if the conflict keys of the transaction are held by transactions in pool
+func (pool *TxPool) verifyConflictKeys(txn *Transaction) error {
+	pool.RLock()
+	defer pool.RUnlock()
+	for _, key := range getConflictKeys(txn) {
+		if poolTx, ok := pool.conflictList[key]; ok {
+			return fmt.Errorf("conflict transaction detected, "+
+				"transaction hash: %x, conflict key: %s", poolTx.Hash(), key)
 		}
 	}
-	pool.addSidechainTx(txn)
-
 	return nil
 }
 
 // check and replace the duplicate sidechainpow tx
 func (pool *TxPool) replaceDuplicateSideChainPowTx(txn *Transaction) {
+	var replaced []*Transaction
+	pool.RLock()
 	for _, v := range pool.txnList {
-		if v.TxType == SideChainPow {
-			oldPayload := v.Payload.Data(SideChainPowPayloadVersion)
-			oldGenesisHashData := oldPayload[32:64]
-
-			newPayload := txn.Payload.Data(SideChainPowPayloadVersion)
-			newGenesisHashData := newPayload[32:64]
-
-			if bytes.Equal(oldGenesisHashData, newGenesisHashData) {
-				txid := txn.Hash()
-				log.Warn("replace sidechainpow transaction, txid=", txid.String())
-				pool.removeTransaction(v)
-			}
+		if v.IsSideChainPowTx() && isSameSideChainPow(txn, v) {
+			replaced = append(replaced, v)
 		}
 	}
+	pool.RUnlock()
+
+	for _, v := range replaced {
+		txid := txn.Hash()
+		log.Warn("replace sidechainpow transaction, txid=", txid.String())
+		pool.removeFromPool(v)
+	}
 }
 
-// clean the sidechain tx pool
-func (pool *TxPool) cleanSidechainTx(txs []*Transaction) {
+// clean the transactions in pool holding the conflict keys of the block transactions
+func (pool *TxPool) cleanConflictTransactions(txs []*Transaction) {
 	for _, txn := range txs {
-		if txn.IsWithdrawFromSideChainTx() {
-			withPayload := txn.Payload.(*PayloadWithdrawFromSideChain)
-			for _, hash := range withPayload.SideChainTransactionHashes {
-				poolTx := pool.sidechainTxList[hash]
-				if poolTx != nil {
-					// delete tx
-					pool.delFromTxList(poolTx.Hash())
-					//delete utxo map
-					for _, input := range poolTx.Inputs {
-						pool.delInputUTXOList(input)
-					}
-					//delete sidechain tx map
-					payload, ok := poolTx.Payload.(*PayloadWithdrawFromSideChain)
-					if !ok {
-						log.Error("type cast failed when clean sidechain tx:", poolTx.Hash())
-					}
-					for _, hash := range payload.SideChainTransactionHashes {
-						pool.delSidechainTx(hash)
-					}
-				}
+		for _, key := range getConflictKeys(txn) {
+			pool.RLock()
+			poolTx := pool.conflictList[key]
+			pool.RUnlock()
+			if poolTx != nil {
+				pool.removeFromPool(poolTx)
 			}
 		}
 	}
@@ -305,31 +621,41 @@ func (pool *TxPool) cleanSideChainPowTx() {
 		log.Error("get current arbiter failed")
 		return
 	}
-	pool.Lock()
-	defer pool.Unlock()
-	for hash, txn := range pool.txnList {
+	var invalid []*Transaction
+	pool.RLock()
+	for _, txn := range pool.txnList {
 		if txn.IsSideChainPowTx() {
 			if err = CheckSideChainPowConsensus(txn, arbitrtor); err != nil {
-				// delete tx
-				delete(pool.txnList, hash)
-				//delete utxo map
-				for _, input := range txn.Inputs {
-					delete(pool.inputUTXOList, input.ReferKey())
-				}
+				invalid = append(invalid, txn)
 			}
 		}
 	}
+	pool.RUnlock()
+
+	for _, txn := range invalid {
+		pool.removeFromPool(txn)
+	}
 }
 
 func (pool *TxPool) addToTxList(txn *Transaction) bool {
 	pool.Lock()
-	defer pool.Unlock()
 	txnHash := txn.Hash()
 	if _, ok := pool.txnList[txnHash]; ok {
+		pool.Unlock()
 		return false
 	}
 	pool.txnList[txnHash] = txn
+	pool.txnEntries[txnHash] = &TxPoolEntry{
+		Tx:     txn,
+		Time:   time.Now(),
+		Height: DefaultLedger.Store.GetHeight(),
+		Size:   txn.GetSize(),
+	}
+	pool.Unlock()
+
+	// publish after unlocking, the subscribers may read the pool
 	DefaultLedger.Blockchain.BCEvents.Notify(events.EventNewTransactionPutInPool, txn)
+	notifyWithdrawTransitions([]*Transaction{txn}, WithdrawPooled, 0)
 	return true
 }
 
@@ -340,6 +666,8 @@ func (pool *TxPool) delFromTxList(txId Uint256) bool {
 		return false
 	}
 	delete(pool.txnList, txId)
+	delete(pool.txnEntries, txId)
+	pool.feeEstimator.RemoveTransaction(txId, false)
 	return true
 }
 
@@ -390,24 +718,23 @@ func (pool *TxPool) delInputUTXOList(input *Input) bool {
 	return true
 }
 
-func (pool *TxPool) addSidechainTx(txn *Transaction) {
+func (pool *TxPool) addConflictKeys(txn *Transaction) {
 	pool.Lock()
 	defer pool.Unlock()
-	witPayload := txn.Payload.(*PayloadWithdrawFromSideChain)
-	for _, hash := range witPayload.SideChainTransactionHashes {
-		pool.sidechainTxList[hash] = txn
+	for _, key := range getConflictKeys(txn) {
+		pool.conflictList[key] = txn
 	}
 }
 
-func (pool *TxPool) delSidechainTx(hash Uint256) bool {
+func (pool *TxPool) delConflictKeys(txn *Transaction) {
 	pool.Lock()
 	defer pool.Unlock()
-	_, ok := pool.sidechainTxList[hash]
-	if !ok {
-		return false
+	txHash := txn.Hash()
+	for _, key := range getConflictKeys(txn) {
+		if poolTx, ok := pool.conflictList[key]; ok && poolTx.Hash() == txHash {
+			delete(pool.conflictList, key)
+		}
 	}
-	delete(pool.sidechainTxList, hash)
-	return true
 }
 
 func (pool *TxPool) MaybeAcceptTransaction(txn *Transaction) error {
@@ -432,23 +759,6 @@ func (pool *TxPool) MaybeAcceptTransaction(txn *Transaction) error {
 	return nil
 }
 
-func (pool *TxPool) RemoveTransaction(txn *Transaction) {
-	txHash := txn.Hash()
-	for i := range txn.Outputs {
-		input := Input{
-			Previous: OutPoint{
-				TxID:  txHash,
-				Index: uint16(i),
-			},
-		}
-
-		txn := pool.getInputUTXOList(&input)
-		if txn != nil {
-			pool.removeTransaction(txn)
-		}
-	}
-}
-
 func GetTxFee(tx *Transaction, assetId Uint256) Fixed64 {
 	feeMap, err := GetTxFeeMap(tx)
 	if err != nil {
@@ -510,12 +820,9 @@ func (pool *TxPool) isTransactionCleaned(tx *Transaction) error {
 			return errors.New("has utxo inputs in input list pool" + input.String())
 		}
 	}
-	if tx.TxType == WithdrawFromSideChain {
-		payload := tx.Payload.(*PayloadWithdrawFromSideChain)
-		for _, hash := range payload.SideChainTransactionHashes {
-			if sidechainPoolTx := pool.sidechainTxList[hash]; sidechainPoolTx != nil {
-				return errors.New("has sidechain hash in sidechain list pool" + hash.String())
-			}
+	for _, key := range getConflictKeys(tx) {
+		if conflictPoolTx := pool.conflictList[key]; conflictPoolTx != nil {
+			return errors.New("has conflict key in conflict list pool" + key)
 		}
 	}
 	return nil
@@ -530,12 +837,9 @@ func (pool *TxPool) isTransactionExisted(tx *Transaction) error {
 			return errors.New("does not have utxo inputs in input list pool" + input.String())
 		}
 	}
-	if tx.TxType == WithdrawFromSideChain {
-		payload := tx.Payload.(*PayloadWithdrawFromSideChain)
-		for _, hash := range payload.SideChainTransactionHashes {
-			if sidechainPoolTx := pool.sidechainTxList[hash]; sidechainPoolTx == nil {
-				return errors.New("does not have sidechain hash in sidechain list pool" + hash.String())
-			}
+	for _, key := range getConflictKeys(tx) {
+		if conflictPoolTx := pool.conflictList[key]; conflictPoolTx == nil {
+			return errors.New("does not have conflict key in conflict list pool" + key)
 		}
 	}
 	return nil
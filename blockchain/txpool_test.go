@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/wuyazero/Elastos.ELA/auxpow"
 	"github.com/wuyazero/Elastos.ELA/config"
 	"github.com/wuyazero/Elastos.ELA/core"
 	"github.com/wuyazero/Elastos.ELA/errors"
+	"github.com/wuyazero/Elastos.ELA/events"
 	"github.com/wuyazero/Elastos.ELA/log"
 
 	"github.com/wuyazero/Elastos.ELA.Utility/common"
@@ -67,7 +69,7 @@ func TestTxPool_VerifyDuplicateSidechainTx(t *testing.T) {
 	}
 
 	// 2. Add sidechain Tx to pool
-	txPool.addSidechainTx(txn1)
+	txPool.addConflictKeys(txn1)
 
 	// 3. Generate a withdraw transaction with duplicate sidechain Tx which already in the pool
 	txn2 := new(core.Transaction)
@@ -80,8 +82,8 @@ func TestTxPool_VerifyDuplicateSidechainTx(t *testing.T) {
 		},
 	}
 
-	// 4. Run verifyDuplicateSidechainTx
-	err := txPool.verifyDuplicateSidechainTx(txn2)
+	// 4. Run verifyConflictKeys
+	err := txPool.verifyConflictKeys(txn2)
 	if err == nil {
 		t.Error("Should find the duplicate sidechain tx")
 	}
@@ -140,23 +142,23 @@ func TestTxPool_CleanSidechainTx(t *testing.T) {
 
 	// 2. Add to sidechain txs pool
 	for _, txn := range txns {
-		txPool.addSidechainTx(txn)
+		txPool.addConflictKeys(txn)
 	}
 
 	// Verify sidechain tx pool state
 	for _, txn := range txns {
-		err := txPool.verifyDuplicateSidechainTx(txn)
+		err := txPool.verifyConflictKeys(txn)
 		if err == nil {
 			t.Error("Should find the duplicate sidechain tx")
 		}
 	}
 
-	// 3. Run cleanSidechainTx
-	txPool.cleanSidechainTx(txns)
+	// 3. Run cleanConflictTransactions
+	txPool.cleanConflictTransactions(txns)
 
 	// Verify sidechian tx pool state
 	for _, txn := range txns {
-		err := txPool.verifyDuplicateSidechainTx(txn)
+		err := txPool.verifyConflictKeys(txn)
 		if err != nil {
 			t.Error("Should not find the duplicate sidechain tx")
 		}
@@ -225,7 +227,7 @@ func TestTxPool_IsDuplicateSidechainTx(t *testing.T) {
 	}
 
 	// 2. Add sidechain Tx to pool
-	txPool.addSidechainTx(txn1)
+	txPool.addConflictKeys(txn1)
 
 	// 3. Run IsDuplicateSidechainTx
 	inPool := txPool.IsDuplicateSidechainTx(sideTx1)
@@ -249,6 +251,451 @@ func TestTxPool_AppendToTxnPool(t *testing.T) {
 
 }
 
+func TestTxPool_AppendMintAssetToTxnPool(t *testing.T) {
+	activationHeight := config.Parameters.ChainParam.AssetActivationHeight
+	config.Parameters.ChainParam.AssetActivationHeight = 0
+	defer func() {
+		config.Parameters.ChainParam.AssetActivationHeight = activationHeight
+	}()
+
+	txPool.Init()
+	controller := newAccount(t)
+	store := DefaultLedger.Store.(*ChainStore)
+
+	// 1. Register an asset with 60 issued of the max supply 100, and fund
+	// the controller with ELA to pay the fees
+	register := &core.PayloadRegisterAsset{
+		Asset: core.Asset{
+			Name:      "MINTPOOL",
+			Precision: 0x04,
+			AssetType: core.Token,
+			MaxSupply: common.Fixed64(100 * ELA),
+		},
+		Amount:     common.Fixed64(60 * ELA),
+		Controller: *controller.ProgramHash(),
+	}
+	assetID := register.AssetID()
+	fund := &core.Transaction{
+		TxType:  core.TransferAsset,
+		Payload: new(core.PayloadTransferAsset),
+		Outputs: []*core.Output{
+			{AssetID: DefaultLedger.Blockchain.AssetID, ProgramHash: *controller.ProgramHash(), Value: common.Fixed64(10 * ELA)},
+			{AssetID: DefaultLedger.Blockchain.AssetID, ProgramHash: *controller.ProgramHash(), Value: common.Fixed64(10 * ELA)},
+		},
+	}
+	block := &core.Block{
+		Header: core.Header{Height: 1},
+		Transactions: []*core.Transaction{
+			{TxType: core.RegisterAsset, Payload: register},
+			fund,
+		},
+	}
+	store.NewBatch()
+	store.PersistAsset(assetID, register.Asset)
+	store.PersistAssetSupplies(block)
+	store.PersistTransaction(fund, 1)
+	store.PersistUnspend(block)
+	store.BatchCommit()
+
+	mint := func(index uint16) *core.Transaction {
+		tx := &core.Transaction{
+			TxType:  core.MintAsset,
+			Payload: &core.PayloadMintAsset{AssetID: assetID, Amount: common.Fixed64(30 * ELA)},
+			Inputs: []*core.Input{
+				{Previous: *core.NewOutPoint(fund.Hash(), index), Sequence: MaxTxInSequenceNum},
+			},
+			Outputs: []*core.Output{
+				{AssetID: DefaultLedger.Blockchain.AssetID, ProgramHash: *controller.ProgramHash(), Value: common.Fixed64(9 * ELA)},
+				{AssetID: assetID, ProgramHash: *controller.ProgramHash(), Value: common.Fixed64(30 * ELA)},
+			},
+		}
+		signature, err := controller.Sign(getData(tx))
+		assert.NoError(t, err)
+		tx.Programs = []*core.Program{{Code: controller.RedeemScript(), Parameter: signature}}
+		return tx
+	}
+
+	// 2. Each minting is within the max supply alone
+	mint1 := mint(0)
+	mint2 := mint(1)
+	assert.Equal(t, errors.Success, txPool.CheckTransaction(mint1))
+	assert.Equal(t, errors.Success, txPool.CheckTransaction(mint2))
+
+	// 3. The second minting exceeds the max supply with the first one in pool
+	assert.Equal(t, errors.Success, txPool.AppendToTxnPool(mint1))
+	assert.Equal(t, errors.ErrAssetSupply, txPool.AppendToTxnPool(mint2))
+	assert.Nil(t, txPool.GetTransaction(mint2.Hash()))
+
+	// 4. The rejected minting must not hold its inputs in pool
+	for _, input := range mint2.Inputs {
+		assert.Nil(t, txPool.getInputUTXOList(input))
+	}
+}
+
+func TestTxPool_CheckTransaction(t *testing.T) {
+	txPool.Init()
+
+	coinbase := new(core.Transaction)
+	coinbase.TxType = core.CoinBase
+	coinbase.Payload = new(core.PayloadCoinBase)
+	assert.Equal(t, errors.ErrIneffectiveCoinbase, txPool.CheckTransaction(coinbase))
+
+	// 1. Add a transaction to pool
+	txn1 := buildTx()
+	assert.True(t, txPool.addToTxList(txn1))
+	for _, input := range txn1.Inputs {
+		txPool.addInputUTXOList(txn1, input)
+	}
+
+	// 2. The same transaction should be reported as duplicate
+	assert.Equal(t, errors.ErrTransactionDuplicate, txPool.checkTransactionWithTxnPool(txn1))
+
+	// 3. A transaction spending the same input should be reported as double spend
+	txn2 := buildTx()
+	txn2.Inputs = append(txn2.Inputs, txn1.Inputs[0])
+	assert.Equal(t, errors.ErrDoubleSpend, txPool.checkTransactionWithTxnPool(txn2))
+
+	// 4. A transaction spending other inputs should pass
+	txn3 := buildTx()
+	assert.Equal(t, errors.Success, txPool.checkTransactionWithTxnPool(txn3))
+
+	// 5. The pool should not be modified
+	assert.Equal(t, 1, txPool.GetTransactionCount())
+	assert.Nil(t, txPool.GetTransaction(txn3.Hash()))
+	for _, input := range txn3.Inputs {
+		assert.Nil(t, txPool.getInputUTXOList(input))
+	}
+
+	// 6. A withdraw transaction including sidechain tx in pool should be reported
+	var sideTx common.Uint256
+	rand.Read(sideTx[:])
+	withdraw1 := new(core.Transaction)
+	withdraw1.TxType = core.WithdrawFromSideChain
+	withdraw1.Payload = &core.PayloadWithdrawFromSideChain{
+		SideChainTransactionHashes: []common.Uint256{sideTx},
+	}
+	txPool.addConflictKeys(withdraw1)
+	withdraw2 := new(core.Transaction)
+	withdraw2.TxType = core.WithdrawFromSideChain
+	withdraw2.Payload = &core.PayloadWithdrawFromSideChain{
+		BlockHeight:                100,
+		SideChainTransactionHashes: []common.Uint256{sideTx},
+	}
+	assert.Equal(t, errors.ErrSidechainTxDuplicate, txPool.checkTransactionWithTxnPool(withdraw2))
+}
+
+func TestTxPool_TxPoolEntry(t *testing.T) {
+	txPool.Init()
+
+	// 1. Add transactions to pool
+	txn1 := buildTx()
+	assert.True(t, txPool.addToTxList(txn1))
+	txn2 := buildTx()
+	assert.True(t, txPool.addToTxList(txn2))
+
+	// 2. Metadata should be recorded
+	entry := txPool.GetTxPoolEntry(txn1.Hash())
+	if !assert.NotNil(t, entry) {
+		return
+	}
+	assert.Equal(t, txn1, entry.Tx)
+	assert.Equal(t, txn1.GetSize(), entry.Size)
+	assert.Equal(t, DefaultLedger.Blockchain.GetBestHeight(), entry.Height)
+	assert.False(t, entry.Time.IsZero())
+
+	entries := txPool.GetTxPoolEntries()
+	assert.Equal(t, 2, len(entries))
+	assert.False(t, entries[1].Time.Before(entries[0].Time))
+
+	info := txPool.GetTxPoolInfo()
+	assert.Equal(t, 2, info.Count)
+	assert.Equal(t, txn1.GetSize()+txn2.GetSize(), info.Bytes)
+	assert.True(t, info.Usage > info.Bytes)
+
+	// 3. Metadata should be removed with the transaction
+	txPool.delFromTxList(txn1.Hash())
+	assert.Nil(t, txPool.GetTxPoolEntry(txn1.Hash()))
+	assert.Equal(t, 1, len(txPool.GetTxPoolEntries()))
+}
+
+func TestTxPool_ExpireTransactions(t *testing.T) {
+	txPool.Init()
+	now := time.Now()
+	height := DefaultLedger.Blockchain.GetBestHeight()
+
+	// 1. A transfer transaction and a withdraw transaction
+	txn1 := buildTx()
+	assert.True(t, txPool.addToTxList(txn1))
+	for _, input := range txn1.Inputs {
+		txPool.addInputUTXOList(txn1, input)
+	}
+
+	var sideTx common.Uint256
+	rand.Read(sideTx[:])
+	txn2 := buildTx()
+	txn2.TxType = core.WithdrawFromSideChain
+	txn2.Payload = &core.PayloadWithdrawFromSideChain{
+		SideChainTransactionHashes: []common.Uint256{sideTx},
+	}
+	assert.True(t, txPool.addToTxList(txn2))
+	txPool.addConflictKeys(txn2)
+
+	// 2. A sidechainpow transaction
+	var sideGenesisHash common.Uint256
+	rand.Read(sideGenesisHash[:])
+	txn3 := new(core.Transaction)
+	txn3.TxType = core.SideChainPow
+	txn3.Payload = &core.PayloadSideChainPow{
+		SideGenesisHash: sideGenesisHash,
+		BlockHeight:     100,
+	}
+	assert.True(t, txPool.addToTxList(txn3))
+
+	// 3. Nothing expires right away
+	txPool.expireTransactions(now, height)
+	assert.Equal(t, 3, txPool.GetTransactionCount())
+
+	// 4. The sidechainpow transaction expires after SideChainPowExpiryBlocks
+	txPool.expireTransactions(now, height+SideChainPowExpiryBlocks)
+	assert.Nil(t, txPool.GetTransaction(txn3.Hash()))
+	assert.Equal(t, 2, txPool.GetTransactionCount())
+
+	// 5. The withdraw transaction expires after WithdrawExpiryBlocks and
+	// releases the side chain transaction
+	txPool.expireTransactions(now, height+WithdrawExpiryBlocks-1)
+	assert.True(t, txPool.IsDuplicateSidechainTx(sideTx))
+	txPool.expireTransactions(now, height+WithdrawExpiryBlocks)
+	assert.Nil(t, txPool.GetTransaction(txn2.Hash()))
+	assert.False(t, txPool.IsDuplicateSidechainTx(sideTx))
+	assert.NotNil(t, txPool.GetTransaction(txn1.Hash()))
+
+	// 6. Other transactions expire after DefaultTxPoolExpiry
+	txPool.txnEntries[txn1.Hash()].Time = now.Add(-DefaultTxPoolExpiry)
+	txPool.expireTransactions(now, height)
+	assert.Equal(t, 0, txPool.GetTransactionCount())
+	for _, input := range txn1.Inputs {
+		assert.Nil(t, txPool.getInputUTXOList(input))
+	}
+	assert.Nil(t, txPool.GetTxPoolEntry(txn1.Hash()))
+}
+
+func TestTxPool_RemoveNotification(t *testing.T) {
+	txPool.Init()
+	removed := make(chan common.Uint256, 1)
+	sub := DefaultLedger.Blockchain.BCEvents.Subscribe(events.EventRemoveTransactionFromPool,
+		func(v interface{}) { removed <- v.(*core.Transaction).Hash() })
+	defer DefaultLedger.Blockchain.BCEvents.UnSubscribe(events.EventRemoveTransactionFromPool, sub)
+
+	// 1. A withdraw transaction in pool
+	var sideTx common.Uint256
+	rand.Read(sideTx[:])
+	txn1 := buildTx()
+	txn1.TxType = core.WithdrawFromSideChain
+	txn1.Payload = &core.PayloadWithdrawFromSideChain{
+		SideChainTransactionHashes: []common.Uint256{sideTx},
+	}
+	assert.True(t, txPool.addToTxList(txn1))
+	txPool.addConflictKeys(txn1)
+
+	// 2. Another withdraw transaction of the side chain transaction is included
+	// in a block, the pool transaction is dropped with a removal event
+	txn2 := buildTx()
+	txn2.TxType = core.WithdrawFromSideChain
+	txn2.Payload = &core.PayloadWithdrawFromSideChain{
+		SideChainTransactionHashes: []common.Uint256{sideTx},
+	}
+	txPool.cleanConflictTransactions([]*core.Transaction{txn2})
+	assert.Nil(t, txPool.GetTransaction(txn1.Hash()))
+	select {
+	case hash := <-removed:
+		assert.Equal(t, txn1.Hash(), hash)
+	case <-time.After(time.Second):
+		t.Error("removal of the conflict transaction not published")
+	}
+
+	// 3. Removing a transaction not in pool publishes nothing
+	txPool.removeFromPool(txn1)
+	select {
+	case <-removed:
+		t.Error("removal of a transaction not in pool published")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestTxPool_WithdrawTransitions(t *testing.T) {
+	txPool.Init()
+	var transitions []*WithdrawIndex
+	sub := DefaultLedger.Blockchain.BCEvents.Subscribe(events.EventWithdrawStatusChanged,
+		func(v interface{}) { transitions = append(transitions, v.([]*WithdrawIndex)...) })
+	defer DefaultLedger.Blockchain.BCEvents.UnSubscribe(events.EventWithdrawStatusChanged, sub)
+
+	programHash := common.Uint168{common.PrefixCrossChain, 4, 5, 6}
+	address, _ := programHash.ToAddress()
+	newWithdraw := func(hashes ...common.Uint256) *core.Transaction {
+		txn := buildTx()
+		txn.TxType = core.WithdrawFromSideChain
+		txn.Payload = &core.PayloadWithdrawFromSideChain{
+			GenesisBlockAddress:        address,
+			SideChainTransactionHashes: hashes,
+		}
+		return txn
+	}
+	checkTransition := func(i int, txn *core.Transaction, hash common.Uint256, status WithdrawStatus) {
+		if !assert.True(t, len(transitions) > i) {
+			return
+		}
+		assert.Equal(t, hash, transitions[i].SideChainTxHash)
+		assert.Equal(t, txn.Hash(), transitions[i].TxID)
+		assert.Equal(t, programHash, transitions[i].GenesisProgramHash)
+		assert.Equal(t, status, transitions[i].Status)
+	}
+
+	// 1. A withdraw transaction of two side chain transactions is put in pool
+	var sideTx1, sideTx2 common.Uint256
+	rand.Read(sideTx1[:])
+	rand.Read(sideTx2[:])
+	txn1 := newWithdraw(sideTx1, sideTx2)
+	assert.True(t, txPool.addToTxList(txn1))
+	txPool.addConflictKeys(txn1)
+	assert.Equal(t, 2, len(transitions))
+	checkTransition(0, txn1, sideTx1, WithdrawPooled)
+	checkTransition(1, txn1, sideTx2, WithdrawPooled)
+
+	// 2. Another withdraw transaction of the second side chain transaction is
+	// confirmed, the pool transaction is dropped after the confirmation, and
+	// only the first side chain transaction is reported dropped
+	transitions = nil
+	txn2 := newWithdraw(sideTx2)
+	store := DefaultLedger.Store.(*ChainStore)
+	store.PersistWithdraw(txn2, 5)
+	store.BatchCommit()
+	txPool.BlockPersistCompleted(&core.Block{
+		Header:       core.Header{Height: 5},
+		Transactions: []*core.Transaction{txn2},
+	})
+	assert.Nil(t, txPool.GetTransaction(txn1.Hash()))
+	assert.Equal(t, 2, len(transitions))
+	checkTransition(0, txn2, sideTx2, WithdrawConfirmed)
+	checkTransition(1, txn1, sideTx1, WithdrawDropped)
+
+	store.BatchDelete(getWithdrawKey(sideTx2))
+	store.BatchDelete(getWithdrawAddressKey(programHash, 5, sideTx2))
+	store.BatchCommit()
+}
+
+func TestTxPool_Reorganize(t *testing.T) {
+	txPool.Init()
+
+	genesisHash, err := DefaultLedger.Store.GetBlockHash(0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	genesis, err := DefaultLedger.Store.GetBlock(genesisHash)
+	if !assert.NoError(t, err) {
+		return
+	}
+	coinbase := genesis.Transactions[0]
+
+	// 1. Transactions of a disconnected block are re-admitted except coinbase,
+	// transactions which are not valid any more are rejected
+	account := newAccount(t)
+	fund := &core.Transaction{
+		TxType:  core.TransferAsset,
+		Payload: new(core.PayloadTransferAsset),
+		Outputs: []*core.Output{
+			{AssetID: DefaultLedger.Blockchain.AssetID, ProgramHash: *account.ProgramHash(), Value: common.Fixed64(10 * ELA)},
+		},
+	}
+	store := DefaultLedger.Store.(*ChainStore)
+	store.NewBatch()
+	store.PersistTransaction(fund, 1)
+	store.PersistUnspend(&core.Block{
+		Header:       core.Header{Height: 1},
+		Transactions: []*core.Transaction{fund},
+	})
+	store.BatchCommit()
+	valid := &core.Transaction{
+		TxType:  core.TransferAsset,
+		Payload: new(core.PayloadTransferAsset),
+		Inputs: []*core.Input{
+			{Previous: *core.NewOutPoint(fund.Hash(), 0), Sequence: MaxTxInSequenceNum},
+		},
+		Outputs: []*core.Output{
+			{AssetID: DefaultLedger.Blockchain.AssetID, ProgramHash: *account.ProgramHash(), Value: common.Fixed64(9 * ELA)},
+		},
+	}
+	signature, err := account.Sign(getData(valid))
+	assert.NoError(t, err)
+	valid.Programs = []*core.Program{{Code: account.RedeemScript(), Parameter: signature}}
+
+	invalid := buildTx()
+	txPool.RollbackTransaction(&core.Block{
+		Header:       core.Header{Height: 1},
+		Transactions: []*core.Transaction{coinbase, valid, invalid},
+	})
+	assert.Nil(t, txPool.GetTransaction(coinbase.Hash()))
+	assert.NotNil(t, txPool.GetTransaction(valid.Hash()))
+	assert.Nil(t, txPool.GetTransaction(invalid.Hash()))
+	assert.Equal(t, 1, txPool.GetTransactionCount())
+
+	addTx := func(txn *core.Transaction) {
+		assert.True(t, txPool.addToTxList(txn))
+		for _, input := range txn.Inputs {
+			txPool.addInputUTXOList(txn, input)
+		}
+		if txn.IsWithdrawFromSideChainTx() {
+			txPool.addConflictKeys(txn)
+		}
+	}
+
+	// 2. A transaction whose references are not in chain
+	txn1 := buildTx()
+	addTx(txn1)
+
+	// 3. A transaction spending the genesis coinbase output
+	txn2 := buildTx()
+	txn2.Inputs = []*core.Input{{Previous: *core.NewOutPoint(coinbase.Hash(), 0)}}
+	addTx(txn2)
+
+	// 4. Withdraw transactions, the side chain transaction of txn3 is in chain
+	var sideTx1, sideTx2 common.Uint256
+	rand.Read(sideTx1[:])
+	rand.Read(sideTx2[:])
+	txn3 := new(core.Transaction)
+	txn3.TxType = core.WithdrawFromSideChain
+	txn3.Payload = &core.PayloadWithdrawFromSideChain{
+		SideChainTransactionHashes: []common.Uint256{sideTx1},
+	}
+	addTx(txn3)
+	txn4 := new(core.Transaction)
+	txn4.TxType = core.WithdrawFromSideChain
+	txn4.Payload = &core.PayloadWithdrawFromSideChain{
+		SideChainTransactionHashes: []common.Uint256{sideTx2},
+	}
+	addTx(txn4)
+
+	store.PersistSidechainTx(sideTx1)
+	store.BatchCommit()
+
+	// 5. Evict the transactions which become invalid
+	txPool.evictInvalidTransactions()
+	assert.Nil(t, txPool.GetTransaction(txn1.Hash()))
+	if DefaultLedger.Store.GetHeight() < config.Parameters.ChainParam.CoinbaseLockTime {
+		assert.Nil(t, txPool.GetTransaction(txn2.Hash()))
+		for _, input := range txn2.Inputs {
+			assert.Nil(t, txPool.getInputUTXOList(input))
+		}
+	}
+	assert.Nil(t, txPool.GetTransaction(txn3.Hash()))
+	assert.False(t, txPool.IsDuplicateSidechainTx(sideTx1))
+	assert.NotNil(t, txPool.GetTransaction(valid.Hash()))
+	assert.NotNil(t, txPool.GetTransaction(txn4.Hash()))
+	assert.True(t, txPool.IsDuplicateSidechainTx(sideTx2))
+
+	store.RollbackSidechainTx(sideTx1)
+	store.BatchCommit()
+}
+
 func TestTxPool_CleanSubmittedTransactions(t *testing.T) {
 	txPool.Init()
 	var input *core.Input
@@ -421,19 +868,19 @@ func TestTxPool_CleanSubmittedTransactions(t *testing.T) {
 	for _, v := range tx4.Inputs {
 		txPool.addInputUTXOList(tx4, v)
 	}
-	txPool.addSidechainTx(tx4)
+	txPool.addConflictKeys(tx4)
 
 	txPool.addToTxList(tx5)
 	for _, v := range tx5.Inputs {
 		txPool.addInputUTXOList(tx5, v)
 	}
-	txPool.addSidechainTx(tx5)
+	txPool.addConflictKeys(tx5)
 
 	txPool.addToTxList(tx6)
 	for _, v := range tx6.Inputs {
 		txPool.addInputUTXOList(tx6, v)
 	}
-	txPool.addSidechainTx(tx6)
+	txPool.addConflictKeys(tx6)
 
 	newBLock.Transactions = []*core.Transaction{tx3}
 	txPool.CleanSubmittedTransactions(&newBLock)
@@ -457,7 +904,7 @@ func TestTxPool_CleanSubmittedTransactions(t *testing.T) {
 	for _, v := range tx4.Inputs {
 		txPool.addInputUTXOList(tx4, v)
 	}
-	txPool.addSidechainTx(tx4)
+	txPool.addConflictKeys(tx4)
 
 	newBLock.Transactions = []*core.Transaction{tx4}
 
@@ -473,7 +920,7 @@ func TestTxPool_CleanSubmittedTransactions(t *testing.T) {
 	for _, v := range tx6.Inputs {
 		txPool.addInputUTXOList(tx6, v)
 	}
-	txPool.addSidechainTx(tx6)
+	txPool.addConflictKeys(tx6)
 	newBLock.Transactions = []*core.Transaction{tx3}
 	txPool.CleanSubmittedTransactions(&newBLock)
 	if err := txPool.isTransactionExisted(tx6); err != nil {
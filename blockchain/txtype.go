@@ -0,0 +1,227 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	. "github.com/wuyazero/Elastos.ELA/core"
+	. "github.com/wuyazero/Elastos.ELA/errors"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+// TxTypeRules are the rules of a transaction type checked by the validators
+// and the txnpool besides the common rules of all transactions. Transactions
+// of a type without registered rules are rejected.
+type TxTypeRules struct {
+	// CheckPayload checks the payload without the ledger.
+	CheckPayload func(txn *Transaction) error
+	// PayloadErrCode is returned by the sanity check if CheckPayload fails,
+	// ErrTransactionPayload by default.
+	PayloadErrCode ErrCode
+
+	// CheckContext checks the transaction with the ledger, it is checked again
+	// when the best chain changes while the transaction is in the txnpool.
+	CheckContext func(txn *Transaction) error
+	// ContextErrCode is returned by the context check if CheckContext fails,
+	// ErrTransactionPayload by default.
+	ContextErrCode ErrCode
+
+	// ConflictKeys returns the keys the transaction holds, a transaction is
+	// rejected by the txnpool and the block sanity check if any of its keys is
+	// held by another transaction.
+	ConflictKeys func(txn *Transaction) []string
+	// ConflictErrCode is returned by the txnpool if the transaction conflicts,
+	// ErrDoubleSpend by default.
+	ConflictErrCode ErrCode
+}
+
+var txTypeRules = make(map[TransactionType]*TxTypeRules)
+
+// RegisterTxTypeRules registers the rules of a transaction type, the payload of
+// the type must be registered by core.RegisterPayload. It is meant to be called
+// by init functions, and panics if the type is already registered.
+func RegisterTxTypeRules(txType TransactionType, rules *TxTypeRules) {
+	if !IsRegisteredTxType(txType) {
+		panic(fmt.Sprintf("blockchain: payload of transaction type %d is not registered", txType))
+	}
+	if _, ok := txTypeRules[txType]; ok {
+		panic(fmt.Sprintf("blockchain: RegisterTxTypeRules called twice for transaction type %d", txType))
+	}
+	r := *rules
+	if r.PayloadErrCode == Success {
+		r.PayloadErrCode = ErrTransactionPayload
+	}
+	if r.ContextErrCode == Success {
+		r.ContextErrCode = ErrTransactionPayload
+	}
+	if r.ConflictErrCode == Success {
+		r.ConflictErrCode = ErrDoubleSpend
+	}
+	txTypeRules[txType] = &r
+}
+
+func getTxTypeRules(txType TransactionType) (*TxTypeRules, bool) {
+	rules, ok := txTypeRules[txType]
+	return rules, ok
+}
+
+// checkTxTypePayload checks the payload is of the transaction type and the
+// transaction with the payload rule of its type.
+func checkTxTypePayload(txn *Transaction) (ErrCode, error) {
+	rules, ok := getTxTypeRules(txn.TxType)
+	if !ok || !isPayloadOfType(txn.TxType, txn.Payload) {
+		return ErrTransactionPayload, errors.New("[txValidator],invalidate transaction payload type.")
+	}
+	if rules.CheckPayload == nil {
+		return Success, nil
+	}
+	if err := rules.CheckPayload(txn); err != nil {
+		return rules.PayloadErrCode, err
+	}
+	return Success, nil
+}
+
+// checkTxTypeContext checks the transaction with the context rule of its type.
+func checkTxTypeContext(txn *Transaction) (ErrCode, error) {
+	rules, ok := getTxTypeRules(txn.TxType)
+	if !ok {
+		return ErrTransactionPayload, errors.New("unknown transaction type")
+	}
+	if rules.CheckContext == nil {
+		return Success, nil
+	}
+	if err := rules.CheckContext(txn); err != nil {
+		return rules.ContextErrCode, err
+	}
+	return Success, nil
+}
+
+// getConflictKeys returns the conflict keys of the transaction.
+func getConflictKeys(txn *Transaction) []string {
+	rules, ok := getTxTypeRules(txn.TxType)
+	if !ok || rules.ConflictKeys == nil {
+		return nil
+	}
+	return rules.ConflictKeys(txn)
+}
+
+// getConflictErrCode returns the error code of the transaction conflicting
+// with the txnpool.
+func getConflictErrCode(txn *Transaction) ErrCode {
+	if rules, ok := getTxTypeRules(txn.TxType); ok {
+		return rules.ConflictErrCode
+	}
+	return ErrDoubleSpend
+}
+
+// isPayloadOfType checks if the payload is the registered payload of the
+// transaction type.
+func isPayloadOfType(txType TransactionType, payload Payload) bool {
+	expected, err := GetPayload(txType)
+	if err != nil || payload == nil {
+		return false
+	}
+	return reflect.TypeOf(payload) == reflect.TypeOf(expected)
+}
+
+func sidechainTxKey(hash Uint256) string {
+	return "sidechaintx:" + hash.String()
+}
+
+func registeredAssetKey(assetID Uint256) string {
+	return "asset:" + assetID.String()
+}
+
+func init() {
+	RegisterTxTypeRules(CoinBase, &TxTypeRules{})
+	RegisterTxTypeRules(TransferAsset, &TxTypeRules{})
+	RegisterTxTypeRules(Record, &TxTypeRules{})
+	RegisterTxTypeRules(RegisterAsset, &TxTypeRules{
+		CheckPayload:    checkRegisterAssetPayload,
+		CheckContext:    CheckRegisterAssetTransaction,
+		ContextErrCode:  ErrRegisterAsset,
+		ConflictKeys:    registerAssetConflictKeys,
+		ConflictErrCode: ErrRegisterAsset,
+	})
+	RegisterTxTypeRules(SideChainPow, &TxTypeRules{
+		CheckContext:   checkSideChainPowContext,
+		ContextErrCode: ErrSideChainPowConsensus,
+	})
+	RegisterTxTypeRules(WithdrawFromSideChain, &TxTypeRules{
+		CheckPayload:    CheckDuplicateSidechainTx,
+		PayloadErrCode:  ErrSidechainTxDuplicate,
+		CheckContext:    CheckWithdrawFromSideChainTransaction,
+		ContextErrCode:  ErrSidechainTxDuplicate,
+		ConflictKeys:    withdrawConflictKeys,
+		ConflictErrCode: ErrSidechainTxDuplicate,
+	})
+	RegisterTxTypeRules(TransferCrossChainAsset, &TxTypeRules{
+		CheckContext:   CheckTransferCrossChainAssetTransaction,
+		ContextErrCode: ErrInvalidOutput,
+	})
+	RegisterTxTypeRules(MintAsset, &TxTypeRules{
+		CheckPayload:   checkMintAssetPayload,
+		CheckContext:   CheckAssetSupplyTransaction,
+		ContextErrCode: ErrAssetSupply,
+	})
+	RegisterTxTypeRules(BurnAsset, &TxTypeRules{
+		CheckPayload:   checkBurnAssetPayload,
+		CheckContext:   CheckAssetSupplyTransaction,
+		ContextErrCode: ErrAssetSupply,
+	})
+	RegisterTxTypeRules(RegisterProducer, &TxTypeRules{
+		CheckPayload:    checkRegisterProducerPayload,
+		CheckContext:    CheckRegisterProducerTransaction,
+		ContextErrCode:  ErrProducer,
+		ConflictKeys:    producerConflictKeys,
+		ConflictErrCode: ErrProducer,
+	})
+	RegisterTxTypeRules(CancelProducer, &TxTypeRules{
+		CheckPayload:    checkCancelProducerPayload,
+		CheckContext:    CheckCancelProducerTransaction,
+		ContextErrCode:  ErrProducer,
+		ConflictKeys:    producerConflictKeys,
+		ConflictErrCode: ErrProducer,
+	})
+	RegisterTxTypeRules(VoteProducer, &TxTypeRules{
+		CheckPayload:   checkVoteProducerPayload,
+		CheckContext:   CheckVoteProducerTransaction,
+		ContextErrCode: ErrVoteProducer,
+	})
+	// the signature of the current arbitrators is checked with the program
+	// hashes of the transaction
+	RegisterTxTypeRules(UpdateArbitrators, &TxTypeRules{
+		CheckPayload:    checkUpdateArbitratorsPayload,
+		ConflictKeys:    updateArbitratorsConflictKeys,
+		ConflictErrCode: ErrUpdateArbitrators,
+	})
+	RegisterTxTypeRules(RegisterSideChain, &TxTypeRules{
+		CheckPayload:    checkRegisterSideChainPayload,
+		CheckContext:    CheckRegisterSideChainTransaction,
+		ContextErrCode:  ErrSideChain,
+		ConflictKeys:    registerSideChainConflictKeys,
+		ConflictErrCode: ErrSideChain,
+	})
+}
+
+func registerAssetConflictKeys(txn *Transaction) []string {
+	payload, ok := txn.Payload.(*PayloadRegisterAsset)
+	if !ok {
+		return nil
+	}
+	return []string{registeredAssetKey(payload.AssetID())}
+}
+
+func withdrawConflictKeys(txn *Transaction) []string {
+	payload, ok := txn.Payload.(*PayloadWithdrawFromSideChain)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(payload.SideChainTransactionHashes))
+	for _, hash := range payload.SideChainTransactionHashes {
+		keys = append(keys, sidechainTxKey(hash))
+	}
+	return keys
+}
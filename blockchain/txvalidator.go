@@ -42,14 +42,9 @@ func CheckTransactionSanity(version uint32, txn *Transaction) ErrCode {
 		return ErrAttributeProgram
 	}
 
-	if err := CheckTransactionPayload(txn); err != nil {
+	if errCode, err := checkTxTypePayload(txn); err != nil {
 		log.Warn("[CheckTransactionPayload],", err)
-		return ErrTransactionPayload
-	}
-
-	if err := CheckDuplicateSidechainTx(txn); err != nil {
-		log.Warn("[CheckDuplicateSidechainTx],", err)
-		return ErrSidechainTxDuplicate
+		return errCode
 	}
 
 	// check iterms above for Coinbase transaction
@@ -68,33 +63,19 @@ func CheckTransactionContext(txn *Transaction) ErrCode {
 		return ErrTransactionDuplicate
 	}
 
-	if txn.IsCoinBaseTx() {
-		return Success
-	}
-
-	if txn.IsSideChainPowTx() {
-		arbitrtor, err := GetCurrentArbiter()
-		if err != nil {
-			return ErrSideChainPowConsensus
-		}
-		if err = CheckSideChainPowConsensus(txn, arbitrtor); err != nil {
-			log.Warn("[CheckSideChainPowConsensus],", err)
-			return ErrSideChainPowConsensus
-		}
+	blockHeight := DefaultLedger.Store.GetHeight() + 1
+	if err := CheckTransactionVersion(txn, blockHeight); err != nil {
+		log.Warn("[CheckTransactionVersion],", err)
+		return ErrTransactionVersion
 	}
 
-	if txn.IsWithdrawFromSideChainTx() {
-		if err := CheckWithdrawFromSideChainTransaction(txn); err != nil {
-			log.Warn("[CheckWithdrawFromSideChainTransaction],", err)
-			return ErrSidechainTxDuplicate
-		}
+	if txn.IsCoinBaseTx() {
+		return Success
 	}
 
-	if txn.IsTransferCrossChainAssetTx() {
-		if err := CheckTransferCrossChainAssetTransaction(txn); err != nil {
-			log.Warn("[CheckTransferCrossChainAssetTransaction],", err)
-			return ErrInvalidOutput
-		}
+	if errCode, err := checkTxTypeContext(txn); err != nil {
+		log.Warn("[CheckTransactionContext] "+txn.TxType.Name()+",", err)
+		return errCode
 	}
 
 	// check double spent transaction
@@ -114,6 +95,16 @@ func CheckTransactionContext(txn *Transaction) ErrCode {
 		return ErrUTXOLocked
 	}
 
+	if err := checkTransactionSequenceLock(txn); err != nil {
+		log.Warn("[CheckTransactionSequenceLock],", err)
+		return ErrUTXOLocked
+	}
+
+	if err := CheckProducerDeposit(txn); err != nil {
+		log.Warn("[CheckProducerDeposit],", err)
+		return ErrUTXOLocked
+	}
+
 	if err := CheckTransactionFee(txn, references); err != nil {
 		log.Warn("[CheckTransactionFee],", err)
 		return ErrTransactionBalance
@@ -126,6 +117,10 @@ func CheckTransactionContext(txn *Transaction) ErrCode {
 		log.Warn("[CheckTransactionSignature],", err)
 		return ErrTransactionSignature
 	}
+	if err := CheckTransactionHTLC(txn, blockHeight); err != nil {
+		log.Warn("[CheckTransactionHTLC],", err)
+		return ErrTransactionSignature
+	}
 
 	if err := CheckTransactionCoinbaseOutputLock(txn); err != nil {
 		log.Warn("[CheckTransactionCoinbaseLock]", err)
@@ -134,6 +129,70 @@ func CheckTransactionContext(txn *Transaction) ErrCode {
 	return Success
 }
 
+// CheckTransactionVersion checks the transaction version is known, and only the
+// default version is accepted before the version activation height.
+func CheckTransactionVersion(txn *Transaction, blockHeight uint32) error {
+	if txn.Version > TxVersionLatest {
+		return fmt.Errorf("unknown transaction version %d", txn.Version)
+	}
+	if txn.Version != TxVersionDefault &&
+		blockHeight < config.Parameters.ChainParam.TxVersionActivationHeight {
+		return fmt.Errorf("transaction version %d is not activated at height %d",
+			txn.Version, blockHeight)
+	}
+	return nil
+}
+
+// CheckTransactionHTLC checks the hash time-locked contracts are activated in
+// the block of the given height, and they are claimed before the refund height
+// or refunded by a transaction locked until the refund height.
+func CheckTransactionHTLC(txn *Transaction, blockHeight uint32) error {
+	activated := blockHeight >= config.Parameters.ChainParam.HTLCActivationHeight
+	for _, output := range txn.Outputs {
+		if output.ProgramHash[0] == PrefixHTLC && !activated {
+			return errors.New("HTLC is not activated")
+		}
+	}
+	for _, program := range txn.Programs {
+		if !IsHTLCScript(program.Code) {
+			continue
+		}
+		if !activated {
+			return errors.New("HTLC is not activated")
+		}
+		script, err := ParseHTLCScript(program.Code)
+		if err != nil {
+			return err
+		}
+		if !isHTLCRefund(*program) {
+			if blockHeight >= script.RefundHeight {
+				return errors.New("HTLC claim height expired")
+			}
+			continue
+		}
+		if err := checkHTLCRefundLockTime(txn, script.RefundHeight, blockHeight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkHTLCRefundLockTime checks the refund transaction can not be packed
+// before the refund height, that is the lock time is not less than the refund
+// height and the lock time is enforced by a non final input sequence in the
+// block of the given height.
+func checkHTLCRefundLockTime(txn *Transaction, refundHeight, blockHeight uint32) error {
+	if txn.LockTime >= LockTimeThreshold || txn.LockTime < refundHeight {
+		return errors.New("HTLC refund height not reached")
+	}
+	for _, input := range txn.Inputs {
+		if !IsFinalSequence(input.Sequence, blockHeight) {
+			return nil
+		}
+	}
+	return errors.New("HTLC refund lock time not enforced")
+}
+
 func CheckDestructionAddress(references map[*Input]*Output) error {
 	for _, output := range references {
 		// this uint168 code
@@ -150,11 +209,16 @@ func CheckDestructionAddress(references map[*Input]*Output) error {
 func CheckTransactionCoinbaseOutputLock(txn *Transaction) error {
 	for _, input := range txn.Inputs {
 		referHash := input.Previous.TxID
-		referTxn, _, _ := DefaultLedger.Store.GetTransaction(referHash)
+		referTxn, _, err := DefaultLedger.Store.GetTransaction(referHash)
+		if err != nil {
+			return errors.New("referenced transaction not found")
+		}
 		if referTxn.IsCoinBaseTx() {
 			lockHeight := referTxn.LockTime
 			currentHeight := DefaultLedger.Store.GetHeight()
-			if currentHeight-lockHeight < config.Parameters.ChainParam.CoinbaseLockTime {
+			// the chain may be shorter than the lock height after a reorganize
+			if currentHeight < lockHeight ||
+				currentHeight-lockHeight < config.Parameters.ChainParam.CoinbaseLockTime {
 				return errors.New("cannot unlock coinbase transaction output")
 			}
 		}
@@ -217,8 +281,9 @@ func CheckTransactionOutput(version uint32, txn *Transaction) error {
 				foundationReward += output.Value
 			}
 		}
-		if Fixed64(foundationReward) < Fixed64(float64(totalReward)*0.3) {
-			return errors.New("Reward to foundation in coinbase < 30%")
+		percent := config.Parameters.ChainParam.FoundationRewardPercent
+		if foundationReward*100 < totalReward*Fixed64(percent) {
+			return fmt.Errorf("Reward to foundation in coinbase < %d%%", percent)
 		}
 
 		return nil
@@ -230,7 +295,12 @@ func CheckTransactionOutput(version uint32, txn *Transaction) error {
 	// check if output address is valid
 	for _, output := range txn.Outputs {
 		if output.AssetID != DefaultLedger.Blockchain.AssetID {
-			return errors.New("asset ID in output is invalid")
+			if !isAssetActivated(DefaultLedger.Store.GetHeight() + 1) {
+				return errors.New("asset ID in output is invalid")
+			}
+			if _, err := getOutputAsset(txn, output.AssetID); err != nil {
+				return errors.New("asset ID in output is invalid")
+			}
 		}
 
 		// output value must >= 0
@@ -254,6 +324,7 @@ func CheckOutputProgramHash(programHash Uint168) bool {
 	if prefix == PrefixStandard ||
 		prefix == PrefixMultisig ||
 		prefix == PrefixCrossChain ||
+		prefix == PrefixHTLC ||
 		programHash == empty {
 		return true
 	}
@@ -264,13 +335,23 @@ func CheckTransactionUTXOLock(txn *Transaction, references map[*Input]*Output) e
 	if txn.IsCoinBaseTx() {
 		return nil
 	}
+	activated := IsLockTimeActivated(DefaultLedger.Store.GetHeight() + 1)
 	for input, output := range references {
 
 		if output.OutputLock == 0 {
 			//check next utxo
 			continue
 		}
-		if input.Sequence != math.MaxUint32-1 {
+		// the lock time of the transaction must be enforced, so the input
+		// must not be final
+		if activated {
+			if input.Sequence == MaxTxInSequenceNum {
+				return errors.New("Invalid input sequence")
+			}
+			if !isSameLockTimeType(txn.LockTime, output.OutputLock) {
+				return errors.New("UTXO output locked")
+			}
+		} else if input.Sequence != math.MaxUint32-1 {
 			return errors.New("Invalid input sequence")
 		}
 		if txn.LockTime < output.OutputLock {
@@ -280,6 +361,20 @@ func CheckTransactionUTXOLock(txn *Transaction, references map[*Input]*Output) e
 	return nil
 }
 
+// checkTransactionSequenceLock checks the relative lock times of the inputs
+// against the next block of the best chain in store.
+func checkTransactionSequenceLock(txn *Transaction) error {
+	height := DefaultLedger.Store.GetHeight()
+	if !IsRelativeLockTimeActivated(height + 1) {
+		return nil
+	}
+	medianTime, err := calcPastMedianTimeByHeight(height)
+	if err != nil {
+		return err
+	}
+	return CheckTransactionSequenceLock(txn, height+1, medianTime)
+}
+
 func CheckTransactionSize(txn *Transaction) error {
 	size := txn.GetSize()
 	if size <= 0 || size > config.Parameters.MaxBlockSize {
@@ -299,7 +394,7 @@ func CheckAssetPrecision(txn *Transaction) error {
 		assetOutputs[v.AssetID] = append(assetOutputs[v.AssetID], v)
 	}
 	for k, outputs := range assetOutputs {
-		asset, err := DefaultLedger.GetAsset(k)
+		asset, err := getOutputAsset(txn, k)
 		if err != nil {
 			return errors.New("The asset not exist in local blockchain.")
 		}
@@ -313,18 +408,98 @@ func CheckAssetPrecision(txn *Transaction) error {
 	return nil
 }
 
+// isAssetActivated checks if RegisterAsset transactions in the block of the
+// height issue user assets, the native asset registered in the genesis block
+// follows the legacy rules.
+func isAssetActivated(height uint32) bool {
+	return height > 0 && height >= config.Parameters.ChainParam.AssetActivationHeight
+}
+
+// getOutputAsset returns the asset which outputs of txn with the given asset
+// ID belong to, it is either a registered asset or the asset registered by txn.
+func getOutputAsset(txn *Transaction, assetID Uint256) (*Asset, error) {
+	if payload, ok := txn.Payload.(*PayloadRegisterAsset); ok && payload.AssetID() == assetID {
+		return &payload.Asset, nil
+	}
+	return DefaultLedger.GetAsset(assetID)
+}
+
 func CheckTransactionFee(tx *Transaction, references map[*Input]*Output) error {
-	var outputValue Fixed64
-	var inputValue Fixed64
+	inputs := make(map[Uint256]Fixed64)
+	outputs := make(map[Uint256]Fixed64)
 	for _, output := range tx.Outputs {
-		outputValue += output.Value
+		outputs[output.AssetID] += output.Value
 	}
 	for _, reference := range references {
-		inputValue += reference.Value
+		inputs[reference.AssetID] += reference.Value
 	}
-	if inputValue < Fixed64(config.Parameters.PowConfiguration.MinTxFee)+outputValue {
+
+	// transaction fee is paid in ELA
+	assetID := DefaultLedger.Blockchain.AssetID
+	if inputs[assetID] < Fixed64(config.Parameters.PowConfiguration.MinTxFee)+outputs[assetID] {
 		return fmt.Errorf("transaction fee not enough")
 	}
+
+	// the other assets must be balanced, except the amount registered, minted
+	// or burned by the transaction
+	changed, amount, _ := getSupplyChange(tx)
+	for id := range outputs {
+		if _, ok := inputs[id]; !ok {
+			inputs[id] = 0
+		}
+	}
+	for id, value := range inputs {
+		if id == assetID {
+			continue
+		}
+		if id == changed {
+			value += amount
+		}
+		if value != outputs[id] {
+			return fmt.Errorf("asset %s is not balanced", id.String())
+		}
+	}
+	if _, ok := inputs[changed]; !ok && amount != 0 {
+		return fmt.Errorf("asset %s is not balanced", changed.String())
+	}
+	return nil
+}
+
+// CheckRegisterAssetTransaction checks the asset is not registered yet and the
+// amount of the asset is issued to the controller.
+func CheckRegisterAssetTransaction(txn *Transaction) error {
+	payload, ok := txn.Payload.(*PayloadRegisterAsset)
+	if !ok {
+		return errors.New("invalid register asset payload")
+	}
+
+	if !isAssetActivated(DefaultLedger.Store.GetHeight() + 1) {
+		return errors.New("user issued assets are not activated")
+	}
+
+	prefix := payload.Controller[0]
+	if prefix != PrefixStandard && prefix != PrefixMultisig {
+		return errors.New("invalid asset controller")
+	}
+
+	assetID := payload.AssetID()
+	if _, err := DefaultLedger.Store.GetAsset(assetID); err == nil {
+		return errors.New("asset already registered")
+	}
+
+	var amount Fixed64
+	for _, output := range txn.Outputs {
+		if output.AssetID != assetID {
+			continue
+		}
+		if output.ProgramHash != payload.Controller {
+			return errors.New("registered asset must be issued to the controller")
+		}
+		amount += output.Value
+	}
+	if amount != payload.Amount {
+		return errors.New("registered asset amount mismatch")
+	}
 	return nil
 }
 
@@ -352,7 +527,7 @@ func CheckAttributeProgram(tx *Transaction) error {
 		if program.Parameter == nil {
 			return fmt.Errorf("invalid program parameter nil")
 		}
-		_, err := ToProgramHash(program.Code)
+		_, err := GetProgramHash(program.Code)
 		if err != nil {
 			return fmt.Errorf("invalid program code %x", program.Code)
 		}
@@ -376,27 +551,98 @@ func CheckTransactionSignature(tx *Transaction, references map[*Input]*Output) e
 	return RunPrograms(buf.Bytes(), hashes, tx.Programs)
 }
 
+// CheckAssetSupplyTransaction checks the amount minted or burned is valid for
+// the asset, the controller signature is checked by CheckTransactionSignature.
+func CheckAssetSupplyTransaction(txn *Transaction) error {
+	assetID, amount, ok := getSupplyChange(txn)
+	if !ok {
+		return errors.New("invalid asset supply payload")
+	}
+	asset, err := DefaultLedger.Store.GetAsset(assetID)
+	if err != nil {
+		return errors.New("asset not found")
+	}
+	if !checkAmountPrecise(amount, asset.Precision) {
+		return errors.New("asset amount out of precise")
+	}
+	if amount < 0 {
+		supply, err := DefaultLedger.Store.GetAssetSupply(assetID)
+		if err != nil {
+			return errors.New("asset supply not found")
+		}
+		if supply.Circulating() < -amount {
+			return errors.New("burned amount exceeds circulating supply")
+		}
+		return nil
+	}
+	return CheckAssetMaxSupply(assetID, amount)
+}
+
+// CheckAssetMaxSupply checks the amount can be minted without exceeding the
+// max supply of the asset.
+func CheckAssetMaxSupply(assetID Uint256, amount Fixed64) error {
+	supply, err := DefaultLedger.Store.GetAssetSupply(assetID)
+	if err != nil {
+		return errors.New("asset supply not found")
+	}
+	if supply.MaxSupply > 0 && supply.Issued+amount > supply.MaxSupply {
+		return errors.New("minted amount exceeds max supply")
+	}
+	return nil
+}
+
 func checkAmountPrecise(amount Fixed64, precision byte) bool {
 	return amount.IntValue()%int64(math.Pow(10, float64(8-precision))) == 0
 }
 
+// CheckTransactionPayload checks the payload is of the transaction type, and
+// checks it with the payload rule of the type.
 func CheckTransactionPayload(txn *Transaction) error {
-	switch pld := txn.Payload.(type) {
-	case *PayloadRegisterAsset:
-		if pld.Asset.Precision < MinPrecision || pld.Asset.Precision > MaxPrecision {
-			return errors.New("Invalide asset Precision.")
-		}
-		if !checkAmountPrecise(pld.Amount, pld.Asset.Precision) {
-			return errors.New("Invalide asset value,out of precise.")
-		}
-	case *PayloadTransferAsset:
-	case *PayloadRecord:
-	case *PayloadCoinBase:
-	case *PayloadSideChainPow:
-	case *PayloadWithdrawFromSideChain:
-	case *PayloadTransferCrossChainAsset:
-	default:
-		return errors.New("[txValidator],invalidate transaction payload type.")
+	_, err := checkTxTypePayload(txn)
+	return err
+}
+
+func checkRegisterAssetPayload(txn *Transaction) error {
+	pld, ok := txn.Payload.(*PayloadRegisterAsset)
+	if !ok {
+		return errors.New("invalid register asset payload")
+	}
+	if pld.Asset.Precision < MinPrecision || pld.Asset.Precision > MaxPrecision {
+		return errors.New("Invalide asset Precision.")
+	}
+	if pld.Amount < 0 {
+		return errors.New("Invalide asset amount.")
+	}
+	if pld.Asset.MaxSupply < 0 || !checkAmountPrecise(pld.Asset.MaxSupply, pld.Asset.Precision) {
+		return errors.New("Invalide asset max supply.")
+	}
+	if pld.Asset.MaxSupply > 0 && pld.Amount > pld.Asset.MaxSupply {
+		return errors.New("Invalide asset amount,exceeds max supply.")
+	}
+	if !checkAmountPrecise(pld.Amount, pld.Asset.Precision) {
+		return errors.New("Invalide asset value,out of precise.")
+	}
+	return nil
+}
+
+func checkMintAssetPayload(txn *Transaction) error {
+	pld, ok := txn.Payload.(*PayloadMintAsset)
+	if !ok {
+		return errors.New("invalid mint asset payload")
+	}
+	if pld.Amount <= 0 {
+		return errors.New("Invalide mint amount.")
+	}
+	return nil
+}
+
+func checkBurnAssetPayload(txn *Transaction) error {
+	pld, ok := txn.Payload.(*PayloadBurnAsset)
+	if !ok {
+		return errors.New("invalid burn asset payload")
+	}
+	if pld.Amount <= 0 {
+		return errors.New("Invalide burn amount.")
 	}
 	return nil
 }
@@ -417,15 +663,24 @@ func CheckDuplicateSidechainTx(txn *Transaction) error {
 }
 
 func GetCurrentArbiter() ([]byte, error) {
-	arbitrators, err := config.Parameters.GetArbitrators()
+	arbitrators, index, err := GetArbitratorGroup(DefaultLedger.Store.GetHeight())
 	if err != nil {
 		return nil, err
 	}
-	height := DefaultLedger.Store.GetHeight()
-	index := height % uint32(len(arbitrators))
-	arbitrator := arbitrators[index]
+	return arbitrators[index], nil
+}
 
-	return arbitrator, nil
+// checkSideChainPowContext checks the sidechainpow transaction mines a
+// registered side chain, and is signed by the current arbitrator.
+func checkSideChainPowContext(txn *Transaction) error {
+	if err := checkSideChainPowGenesis(txn); err != nil {
+		return err
+	}
+	arbitrator, err := GetCurrentArbiter()
+	if err != nil {
+		return err
+	}
+	return CheckSideChainPowConsensus(txn, arbitrator)
 }
 
 func CheckSideChainPowConsensus(txn *Transaction, arbitrator []byte) error {
@@ -474,7 +729,7 @@ func CheckWithdrawFromSideChainTransaction(txn *Transaction) error {
 		}
 	}
 
-	return nil
+	return checkSideChainWithdrawal(txn, reference)
 }
 
 func CheckTransferCrossChainAssetTransaction(txn *Transaction) error {
@@ -506,6 +761,9 @@ func CheckTransferCrossChainAssetTransaction(txn *Transaction) error {
 		if payloadObj.CrossChainAddresses[i] == "" {
 			return errors.New("Invalid transaction cross chain address ")
 		}
+		if err := checkCrossChainOutput(txn.Outputs[payloadObj.OutputIndexes[i]]); err != nil {
+			return err
+		}
 	}
 
 	//check cross chain amount in payload
@@ -8,9 +8,11 @@ import (
 	"math"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/wuyazero/Elastos.ELA/config"
 	"github.com/wuyazero/Elastos.ELA/core"
+	"github.com/wuyazero/Elastos.ELA/errors"
 	"github.com/wuyazero/Elastos.ELA/log"
 
 	"github.com/wuyazero/Elastos.ELA.Utility/common"
@@ -165,7 +167,7 @@ func TestCheckTransactionOutput(t *testing.T) {
 	assert.EqualError(t, err, "asset ID in coinbase is invalid")
 
 	// reward to foundation in coinbase = 30%
-	totalReward := RewardAmountPerBlock
+	totalReward := CalcBlockSubsidy(1, config.Parameters.ChainParam)
 	t.Logf("Block reward amount %s", totalReward.String())
 	foundationReward := common.Fixed64(float64(totalReward) * 0.3)
 	t.Logf("Foundation reward amount %s", foundationReward.String())
@@ -375,6 +377,7 @@ func TestCheckAttributeProgram(t *testing.T) {
 func TestCheckTransactionPayload(t *testing.T) {
 	// normal
 	tx := new(core.Transaction)
+	tx.TxType = core.RegisterAsset
 	payload := &core.PayloadRegisterAsset{
 		Asset: core.Asset{
 			Name:      "ELA",
@@ -398,6 +401,11 @@ func TestCheckTransactionPayload(t *testing.T) {
 	err = CheckTransactionPayload(tx)
 	assert.EqualError(t, err, "Invalide asset value,out of precise.")
 
+	// payload not of the transaction type
+	tx.TxType = core.TransferAsset
+	err = CheckTransactionPayload(tx)
+	assert.EqualError(t, err, "[txValidator],invalidate transaction payload type.")
+
 	t.Log("[TestCheckTransactionPayload] PASSED")
 }
 
@@ -426,6 +434,11 @@ func TestCheckDuplicateSidechainTx(t *testing.T) {
 	err := CheckDuplicateSidechainTx(txn)
 	assert.EqualError(t, err, "Duplicate sidechain tx detected in a transaction")
 
+	// 3. The sanity check reports the duplicate sidechain tx
+	errCode, err := checkTxTypePayload(txn)
+	assert.EqualError(t, err, "Duplicate sidechain tx detected in a transaction")
+	assert.Equal(t, errors.ErrSidechainTxDuplicate, errCode)
+
 	t.Log("[TestCheckDuplicateSidechainTx] PASSED")
 }
 
@@ -475,6 +488,370 @@ func TestCheckTransactionBalance(t *testing.T) {
 	t.Log("[TestCheckTransactionBalance] PASSED")
 }
 
+func TestCheckAssetBalance(t *testing.T) {
+	config.Parameters.PowConfiguration.MinTxFee = int(1 * ELA)
+	token := common.Uint256{1, 2, 3}
+	tx := new(core.Transaction)
+	tx.TxType = core.TransferAsset
+	tx.Payload = new(core.PayloadTransferAsset)
+	references := map[*core.Input]*core.Output{
+		{Sequence: 0}: {AssetID: DefaultLedger.Blockchain.AssetID, Value: common.Fixed64(2 * ELA)},
+		{Sequence: 1}: {AssetID: token, Value: common.Fixed64(10 * ELA)},
+	}
+
+	// balanced asset
+	tx.Outputs = []*core.Output{
+		{AssetID: DefaultLedger.Blockchain.AssetID, Value: common.Fixed64(1 * ELA)},
+		{AssetID: token, Value: common.Fixed64(4 * ELA)},
+		{AssetID: token, Value: common.Fixed64(6 * ELA)},
+	}
+	err := CheckTransactionFee(tx, references)
+	assert.NoError(t, err)
+
+	// unbalanced asset
+	tx.Outputs[2].Value = common.Fixed64(5 * ELA)
+	err = CheckTransactionFee(tx, references)
+	assert.EqualError(t, err, fmt.Sprintf("asset %s is not balanced", token.String()))
+
+	// fee is not paid in user issued asset
+	tx.Outputs = []*core.Output{
+		{AssetID: DefaultLedger.Blockchain.AssetID, Value: common.Fixed64(2 * ELA)},
+		{AssetID: token, Value: common.Fixed64(9 * ELA)},
+	}
+	err = CheckTransactionFee(tx, references)
+	assert.EqualError(t, err, "transaction fee not enough")
+
+	t.Log("[TestCheckAssetBalance] PASSED")
+}
+
+func TestCheckRegisterAssetTransaction(t *testing.T) {
+	activationHeight := config.Parameters.ChainParam.AssetActivationHeight
+	config.Parameters.ChainParam.AssetActivationHeight = 0
+	defer func() {
+		config.Parameters.ChainParam.AssetActivationHeight = activationHeight
+	}()
+
+	payload := &core.PayloadRegisterAsset{
+		Asset: core.Asset{
+			Name:      "TOKEN",
+			Precision: 0x04,
+			AssetType: core.Token,
+		},
+		Amount:     common.Fixed64(100 * ELA),
+		Controller: FoundationAddress,
+	}
+	assetID := payload.AssetID()
+	tx := &core.Transaction{
+		TxType:  core.RegisterAsset,
+		Payload: payload,
+		Outputs: []*core.Output{
+			{AssetID: DefaultLedger.Blockchain.AssetID, ProgramHash: common.Uint168{}, Value: common.Fixed64(1 * ELA)},
+			{AssetID: assetID, ProgramHash: FoundationAddress, Value: common.Fixed64(60 * ELA)},
+			{AssetID: assetID, ProgramHash: FoundationAddress, Value: common.Fixed64(40 * ELA)},
+		},
+	}
+
+	// the outputs are able to use the asset being registered
+	err := CheckTransactionOutput(core.CheckTxOut, tx)
+	assert.NoError(t, err)
+	err = CheckAssetPrecision(tx)
+	assert.NoError(t, err)
+	err = CheckRegisterAssetTransaction(tx)
+	assert.NoError(t, err)
+
+	// the registered asset is not counted into the asset balance
+	references := map[*core.Input]*core.Output{
+		{}: {AssetID: DefaultLedger.Blockchain.AssetID, Value: common.Fixed64(2 * ELA)},
+	}
+	err = CheckTransactionFee(tx, references)
+	assert.NoError(t, err)
+
+	// the controller signs the transaction
+	hashes, err := GetTxProgramHashes(tx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Uint168{FoundationAddress}, hashes)
+
+	// amount mismatch
+	tx.Outputs[2].Value = common.Fixed64(30 * ELA)
+	err = CheckRegisterAssetTransaction(tx)
+	assert.EqualError(t, err, "registered asset amount mismatch")
+
+	// issued to another address
+	tx.Outputs[2].Value = common.Fixed64(40 * ELA)
+	tx.Outputs[2].ProgramHash = common.Uint168{}
+	err = CheckRegisterAssetTransaction(tx)
+	assert.EqualError(t, err, "registered asset must be issued to the controller")
+	tx.Outputs[2].ProgramHash = FoundationAddress
+
+	// not activated yet
+	config.Parameters.ChainParam.AssetActivationHeight = math.MaxUint32
+	err = CheckTransactionOutput(core.CheckTxOut, tx)
+	assert.EqualError(t, err, "asset ID in output is invalid")
+	err = CheckRegisterAssetTransaction(tx)
+	assert.EqualError(t, err, "user issued assets are not activated")
+	config.Parameters.ChainParam.AssetActivationHeight = 0
+
+	// asset already registered
+	DefaultLedger.Store.(*ChainStore).NewBatch()
+	DefaultLedger.Store.PersistAsset(assetID, payload.Asset)
+	DefaultLedger.Store.(*ChainStore).BatchCommit()
+	err = CheckRegisterAssetTransaction(tx)
+	assert.EqualError(t, err, "asset already registered")
+	DefaultLedger.Store.(*ChainStore).NewBatch()
+	DefaultLedger.Store.(*ChainStore).RollbackAsset(assetID)
+	DefaultLedger.Store.(*ChainStore).BatchCommit()
+
+	// invalid controller
+	payload.Controller = common.Uint168{}
+	err = CheckRegisterAssetTransaction(tx)
+	assert.EqualError(t, err, "invalid asset controller")
+
+	t.Log("[TestCheckRegisterAssetTransaction] PASSED")
+}
+
+func TestCheckAssetSupplyTransaction(t *testing.T) {
+	activationHeight := config.Parameters.ChainParam.AssetActivationHeight
+	config.Parameters.ChainParam.AssetActivationHeight = 0
+	defer func() {
+		config.Parameters.ChainParam.AssetActivationHeight = activationHeight
+	}()
+
+	payload := &core.PayloadRegisterAsset{
+		Asset: core.Asset{
+			Name:      "SUPPLY",
+			Precision: 0x04,
+			AssetType: core.Token,
+			MaxSupply: common.Fixed64(100 * ELA),
+		},
+		Amount:     common.Fixed64(60 * ELA),
+		Controller: FoundationAddress,
+	}
+	assetID := payload.AssetID()
+	block := &core.Block{
+		Header: core.Header{Height: 1},
+		Transactions: []*core.Transaction{
+			{TxType: core.RegisterAsset, Payload: payload},
+		},
+	}
+	DefaultLedger.Store.(*ChainStore).NewBatch()
+	DefaultLedger.Store.PersistAsset(assetID, payload.Asset)
+	DefaultLedger.Store.(*ChainStore).PersistAssetSupplies(block)
+	DefaultLedger.Store.(*ChainStore).BatchCommit()
+
+	// mint within max supply
+	mint := &core.PayloadMintAsset{AssetID: assetID, Amount: common.Fixed64(40 * ELA)}
+	tx := &core.Transaction{TxType: core.MintAsset, Payload: mint}
+	assert.NoError(t, CheckAssetSupplyTransaction(tx))
+
+	// the controller signs the minting
+	hashes, err := GetTxProgramHashes(tx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Uint168{FoundationAddress}, hashes)
+
+	// minted amount is issued
+	references := map[*core.Input]*core.Output{
+		{}: {AssetID: DefaultLedger.Blockchain.AssetID, Value: common.Fixed64(2 * ELA)},
+	}
+	tx.Outputs = []*core.Output{
+		{AssetID: assetID, Value: common.Fixed64(40 * ELA)},
+	}
+	assert.NoError(t, CheckTransactionFee(tx, references))
+	tx.Outputs = nil
+	assert.EqualError(t, CheckTransactionFee(tx, references),
+		fmt.Sprintf("asset %s is not balanced", assetID.String()))
+
+	// exceeds max supply
+	mint.Amount = common.Fixed64(41 * ELA)
+	assert.EqualError(t, CheckAssetSupplyTransaction(tx), "minted amount exceeds max supply")
+
+	// out of precise
+	mint.Amount = 1
+	assert.EqualError(t, CheckAssetSupplyTransaction(tx), "asset amount out of precise")
+
+	// burn within circulating supply
+	burn := &core.PayloadBurnAsset{AssetID: assetID, Amount: common.Fixed64(60 * ELA)}
+	tx = &core.Transaction{TxType: core.BurnAsset, Payload: burn}
+	assert.NoError(t, CheckAssetSupplyTransaction(tx))
+
+	// burned amount is destroyed
+	references[&core.Input{Sequence: 1}] = &core.Output{AssetID: assetID, Value: common.Fixed64(60 * ELA)}
+	assert.NoError(t, CheckTransactionFee(tx, references))
+
+	// exceeds circulating supply
+	burn.Amount = common.Fixed64(61 * ELA)
+	assert.EqualError(t, CheckAssetSupplyTransaction(tx), "burned amount exceeds circulating supply")
+
+	// native asset has no supply
+	burn.AssetID = DefaultLedger.Blockchain.AssetID
+	burn.Amount = common.Fixed64(1 * ELA)
+	assert.EqualError(t, CheckAssetSupplyTransaction(tx), "asset supply not found")
+
+	DefaultLedger.Store.(*ChainStore).NewBatch()
+	DefaultLedger.Store.(*ChainStore).RollbackAssetSupplies(block)
+	DefaultLedger.Store.(*ChainStore).RollbackAsset(assetID)
+	DefaultLedger.Store.(*ChainStore).BatchCommit()
+
+	t.Log("[TestCheckAssetSupplyTransaction] PASSED")
+}
+
+func TestCheckTransactionUTXOLock(t *testing.T) {
+	activationHeight := config.Parameters.ChainParam.LockTimeActivationHeight
+	defer func() {
+		config.Parameters.ChainParam.LockTimeActivationHeight = activationHeight
+	}()
+
+	input := &core.Input{Sequence: math.MaxUint32 - 1}
+	output := &core.Output{OutputLock: 100}
+	references := map[*core.Input]*core.Output{input: output}
+	tx := &core.Transaction{TxType: core.TransferAsset, LockTime: 100}
+
+	// before the activation, only MaxUint32-1 sequence is allowed
+	config.Parameters.ChainParam.LockTimeActivationHeight = math.MaxUint32
+	assert.NoError(t, CheckTransactionUTXOLock(tx, references))
+	input.Sequence = 0
+	assert.EqualError(t, CheckTransactionUTXOLock(tx, references), "Invalid input sequence")
+
+	// after the activation, any non final sequence is allowed
+	config.Parameters.ChainParam.LockTimeActivationHeight = 0
+	assert.NoError(t, CheckTransactionUTXOLock(tx, references))
+	input.Sequence = MaxTxInSequenceNum
+	assert.EqualError(t, CheckTransactionUTXOLock(tx, references), "Invalid input sequence")
+	input.Sequence = 0
+
+	// lock time not reached
+	tx.LockTime = 99
+	assert.EqualError(t, CheckTransactionUTXOLock(tx, references), "UTXO output locked")
+
+	// time based output lock
+	output.OutputLock = 1600000000
+	tx.LockTime = 1600000000
+	assert.NoError(t, CheckTransactionUTXOLock(tx, references))
+	tx.LockTime = 1599999999
+	assert.EqualError(t, CheckTransactionUTXOLock(tx, references), "UTXO output locked")
+
+	// lock time types mismatch
+	output.OutputLock = 100
+	tx.LockTime = 1600000000
+	assert.EqualError(t, CheckTransactionUTXOLock(tx, references), "UTXO output locked")
+
+	t.Log("[TestCheckTransactionUTXOLock] PASSED")
+}
+
+func TestCalcSequenceLock(t *testing.T) {
+	// deposit confirmed in the genesis block
+	deposit := NewCoinBaseTransaction(new(core.PayloadCoinBase), 0)
+	deposit.Outputs = []*core.Output{
+		{AssetID: DefaultLedger.Blockchain.AssetID, ProgramHash: FoundationAddress, Value: common.Fixed64(100 * ELA)},
+	}
+	DefaultLedger.Store.(*ChainStore).NewBatch()
+	DefaultLedger.Store.(*ChainStore).PersistTransaction(deposit, 0)
+	DefaultLedger.Store.(*ChainStore).BatchCommit()
+	defer func() {
+		DefaultLedger.Store.(*ChainStore).NewBatch()
+		DefaultLedger.Store.(*ChainStore).RollbackTransaction(deposit)
+		DefaultLedger.Store.(*ChainStore).BatchCommit()
+	}()
+	genesis, err := GetGenesisBlock()
+	if !assert.NoError(t, err) {
+		return
+	}
+	genesisTime := int64(genesis.Timestamp)
+
+	input := &core.Input{Previous: *core.NewOutPoint(deposit.Hash(), 0)}
+	tx := &core.Transaction{TxType: core.TransferAsset, Inputs: []*core.Input{input}}
+
+	// disabled relative lock time
+	input.Sequence = SequenceLockTimeDisabled | SequenceLockTimeEnabled | 10
+	lock, err := CalcSequenceLock(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, &SequenceLock{Seconds: -1, BlockHeight: -1}, lock)
+
+	// block based relative lock time
+	input.Sequence = SequenceLockTimeEnabled | 10
+	lock, err = CalcSequenceLock(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, &SequenceLock{Seconds: -1, BlockHeight: 9}, lock)
+	assert.False(t, IsSequenceLockActive(lock, 9, time.Unix(genesisTime, 0)))
+	assert.True(t, IsSequenceLockActive(lock, 10, time.Unix(genesisTime, 0)))
+
+	// time based relative lock time
+	input.Sequence = SequenceLockTimeEnabled | SequenceLockTimeIsSeconds | 2
+	lock, err = CalcSequenceLock(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, &SequenceLock{Seconds: genesisTime + 1023, BlockHeight: -1}, lock)
+	assert.False(t, IsSequenceLockActive(lock, 1, time.Unix(genesisTime+1023, 0)))
+	assert.True(t, IsSequenceLockActive(lock, 1, time.Unix(genesisTime+1024, 0)))
+
+	// not applied before the activation
+	activationHeight := config.Parameters.ChainParam.RelativeLockTimeActivationHeight
+	config.Parameters.ChainParam.RelativeLockTimeActivationHeight = 100
+	assert.NoError(t, CheckTransactionSequenceLock(tx, 99, time.Unix(genesisTime, 0)))
+	assert.EqualError(t, CheckTransactionSequenceLock(tx, 100, time.Unix(genesisTime, 0)),
+		"transaction sequence locks not met")
+
+	// not applied to the legacy inputs after the activation
+	legacy := &core.Transaction{
+		TxType: core.TransferAsset,
+		Inputs: []*core.Input{{Previous: input.Previous, Sequence: 0xFFFF}},
+	}
+	lock, err = CalcSequenceLock(legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, &SequenceLock{Seconds: -1, BlockHeight: -1}, lock)
+	assert.NoError(t, CheckTransactionSequenceLock(legacy, 100, time.Unix(genesisTime, 0)))
+	config.Parameters.ChainParam.RelativeLockTimeActivationHeight = activationHeight
+
+	t.Log("[TestCalcSequenceLock] PASSED")
+}
+
+func TestRegisterTxTypeRules(t *testing.T) {
+	const testTxType core.TransactionType = 0xfd
+
+	// 1. Transactions of a type without rules are rejected, the payload is
+	// left registered because it can not be unregistered out of core
+	assert.Panics(t, func() { RegisterTxTypeRules(0xfc, &TxTypeRules{}) })
+	if !core.IsRegisteredTxType(testTxType) {
+		core.RegisterPayload(testTxType, "Test", func() core.Payload { return new(core.PayloadRecord) })
+	}
+	tx := &core.Transaction{TxType: testTxType, Payload: new(core.PayloadRecord)}
+	assert.Error(t, CheckTransactionPayload(tx))
+
+	// 2. Register the rules of the type
+	RegisterTxTypeRules(testTxType, &TxTypeRules{
+		CheckPayload: func(txn *core.Transaction) error {
+			if txn.Payload.(*core.PayloadRecord).RecordType == "" {
+				return fmt.Errorf("empty record type")
+			}
+			return nil
+		},
+		CheckContext: func(txn *core.Transaction) error {
+			return fmt.Errorf("context failed")
+		},
+		ConflictKeys: func(txn *core.Transaction) []string {
+			return []string{"test:" + txn.Payload.(*core.PayloadRecord).RecordType}
+		},
+	})
+	defer unregisterTxTypeRules(testTxType)
+	assert.Panics(t, func() { RegisterTxTypeRules(testTxType, &TxTypeRules{}) })
+
+	// 3. The rules are checked
+	assert.EqualError(t, CheckTransactionPayload(tx), "empty record type")
+	tx.Payload.(*core.PayloadRecord).RecordType = "record"
+	assert.NoError(t, CheckTransactionPayload(tx))
+	errCode, err := checkTxTypeContext(tx)
+	assert.EqualError(t, err, "context failed")
+	assert.Equal(t, errors.ErrTransactionPayload, errCode)
+	assert.Equal(t, []string{"test:record"}, getConflictKeys(tx))
+	assert.Equal(t, errors.ErrDoubleSpend, getConflictErrCode(tx))
+
+	t.Log("[TestRegisterTxTypeRules] PASSED")
+}
+
+// unregisterTxTypeRules removes the rules registered by a test, so the test
+// can be run again.
+func unregisterTxTypeRules(txType core.TransactionType) {
+	delete(txTypeRules, txType)
+}
+
 func TestTxValidatorDone(t *testing.T) {
 	DefaultLedger.Store.Close()
 }
@@ -531,3 +908,88 @@ func TestCheckDestructionAddress(t *testing.T) {
 	err := CheckDestructionAddress(reference)
 	assert.EqualError(t, err, fmt.Sprintf("cannot use utxo in the Elastos foundation destruction address"))
 }
+
+func TestCheckUpdateArbitratorsPayload(t *testing.T) {
+	arbitrators := make([][]byte, 0, 3)
+	for i := 0; i < 3; i++ {
+		_, public, _ := crypto.GenerateKeyPair()
+		publicKey, _ := public.EncodePoint(true)
+		arbitrators = append(arbitrators, publicKey)
+	}
+	payload := &core.PayloadUpdateArbitrators{Arbitrators: arbitrators}
+	tx := &core.Transaction{TxType: core.UpdateArbitrators, Payload: payload}
+	assert.NoError(t, checkUpdateArbitratorsPayload(tx))
+
+	payload.Arbitrators = nil
+	assert.EqualError(t, checkUpdateArbitratorsPayload(tx), "invalid arbitrators count")
+
+	payload.Arbitrators = [][]byte{arbitrators[0], arbitrators[1], arbitrators[0]}
+	assert.EqualError(t, checkUpdateArbitratorsPayload(tx), "duplicated arbitrator")
+
+	_, public, _ := crypto.GenerateKeyPair()
+	uncompressed, _ := public.EncodePoint(false)
+	payload.Arbitrators = [][]byte{uncompressed}
+	assert.EqualError(t, checkUpdateArbitratorsPayload(tx), "arbitrator public key must be compressed")
+
+	invalid := make([]byte, 33)
+	invalid[0] = 0x05
+	payload.Arbitrators = [][]byte{invalid}
+	assert.EqualError(t, checkUpdateArbitratorsPayload(tx), "invalid arbitrator public key")
+
+	assert.Equal(t, 1, GetArbitratorsMajority(1))
+	assert.Equal(t, 3, GetArbitratorsMajority(3))
+	assert.Equal(t, 4, GetArbitratorsMajority(5))
+	assert.Equal(t, 11, GetArbitratorsMajority(16))
+}
+
+func TestCheckRegisterSideChainPayload(t *testing.T) {
+	crossChainHash := common.Uint168{common.PrefixCrossChain, 1, 2, 3}
+	address, _ := crossChainHash.ToAddress()
+	payload := &core.PayloadRegisterSideChain{
+		GenesisHash:             common.Uint256{1, 2, 3},
+		GenesisAddress:          address,
+		Name:                    "side",
+		MinArbitratorSignatures: 3,
+	}
+	tx := &core.Transaction{TxType: core.RegisterSideChain, Payload: payload}
+	assert.NoError(t, checkRegisterSideChainPayload(tx))
+
+	payload.MinArbitratorSignatures = 0
+	assert.EqualError(t, checkRegisterSideChainPayload(tx), "invalid side chain arbitrator signatures count")
+
+	payload.MinArbitratorSignatures = 3
+	payload.Name = ""
+	assert.EqualError(t, checkRegisterSideChainPayload(tx), "invalid side chain name")
+
+	payload.Name = "side"
+	standardHash := common.Uint168{common.PrefixStandard, 1, 2, 3}
+	payload.GenesisAddress, _ = standardHash.ToAddress()
+	assert.EqualError(t, checkRegisterSideChainPayload(tx), "invalid side chain genesis address")
+
+	payload.GenesisAddress = address
+	payload.GenesisHash = common.EmptyHash
+	assert.EqualError(t, checkRegisterSideChainPayload(tx), "invalid side chain genesis hash")
+}
+
+func TestCheckTransactionVersion(t *testing.T) {
+	activationHeight := config.Parameters.ChainParam.TxVersionActivationHeight
+	config.Parameters.ChainParam.TxVersionActivationHeight = 100
+	defer func() {
+		config.Parameters.ChainParam.TxVersionActivationHeight = activationHeight
+	}()
+
+	tx := &core.Transaction{TxType: core.TransferAsset, Payload: new(core.PayloadTransferAsset)}
+	assert.NoError(t, CheckTransactionVersion(tx, 99))
+	assert.NoError(t, CheckTransactionVersion(tx, 100))
+
+	// versions other than the default one are accepted since the activation
+	tx.Version = core.TxVersion01
+	assert.EqualError(t, CheckTransactionVersion(tx, 99),
+		"transaction version 1 is not activated at height 99")
+	assert.NoError(t, CheckTransactionVersion(tx, 100))
+
+	// unknown versions are always rejected
+	tx.Version = core.TxVersionLatest + 1
+	assert.EqualError(t, CheckTransactionVersion(tx, 99), "unknown transaction version 2")
+	assert.EqualError(t, CheckTransactionVersion(tx, 100), "unknown transaction version 2")
+}
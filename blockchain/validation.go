@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"sort"
 
-	"github.com/wuyazero/Elastos.ELA/config"
 	. "github.com/wuyazero/Elastos.ELA/core"
 
 	"github.com/wuyazero/Elastos.ELA.Utility/common"
@@ -20,11 +19,21 @@ func RunPrograms(data []byte, hashes []common.Uint168, programs []*Program) erro
 	}
 
 	for i, program := range programs {
-		programHash, err := crypto.ToProgramHash(program.Code)
+		programHash, err := GetProgramHash(program.Code)
 		if err != nil {
 			return err
 		}
 
+		if IsHTLCScript(program.Code) {
+			if !hashes[i].IsEqual(*programHash) {
+				return errors.New("The data hashes is different with corresponding program code.")
+			}
+			if err := checkHTLCSignature(*program, data); err != nil {
+				return err
+			}
+			continue
+		}
+
 		signType, err := crypto.GetScriptType(program.Code)
 		if err != nil {
 			return err
@@ -68,6 +77,39 @@ func GetTxProgramHashes(tx *Transaction, references map[*Input]*Output) ([]commo
 		programHash := output.ProgramHash
 		hashes = append(hashes, programHash)
 	}
+	// the controller must sign the registration, minting and burning of an
+	// asset, the producer must sign its registration and cancellation, and the
+	// current arbitrators must sign the update of the arbitrators and the
+	// registration of a side chain
+	switch payload := tx.Payload.(type) {
+	case *PayloadRegisterAsset:
+		hashes = append(hashes, payload.Controller)
+	case *PayloadMintAsset, *PayloadBurnAsset:
+		assetID, _, _ := getSupplyChange(tx)
+		supply, err := DefaultLedger.Store.GetAssetSupply(assetID)
+		if err != nil {
+			return nil, errors.New("[Transaction], GetProgramHashes asset supply not found.")
+		}
+		hashes = append(hashes, supply.Controller)
+	case *PayloadRegisterProducer:
+		programHash, err := GetProducerProgramHash(payload.PublicKey)
+		if err != nil {
+			return nil, errors.New("[Transaction], GetProgramHashes invalid producer public key.")
+		}
+		hashes = append(hashes, *programHash)
+	case *PayloadCancelProducer:
+		programHash, err := GetProducerProgramHash(payload.PublicKey)
+		if err != nil {
+			return nil, errors.New("[Transaction], GetProgramHashes invalid producer public key.")
+		}
+		hashes = append(hashes, *programHash)
+	case *PayloadUpdateArbitrators, *PayloadRegisterSideChain:
+		programHash, err := getCurrentArbitratorsProgramHash()
+		if err != nil {
+			return nil, errors.New("[Transaction], GetProgramHashes invalid arbitrators.")
+		}
+		hashes = append(hashes, *programHash)
+	}
 	for _, attribute := range tx.Attributes {
 		if attribute.Usage == Script {
 			dataHash, err := common.Uint168FromBytes(attribute.Data)
@@ -102,6 +144,43 @@ func checkStandardSignature(program Program, data []byte) error {
 	return crypto.Verify(*publicKey, data, program.Parameter[1:])
 }
 
+// checkHTLCSignature checks the hash time-locked contract is claimed by the
+// recipient with the preimage, or refunded to the sender. The parameter is the
+// signature followed by the preimage when claiming, and the signature only when
+// refunding. The heights are checked by CheckTransactionHTLC.
+func checkHTLCSignature(program Program, data []byte) error {
+	script, err := ParseHTLCScript(program.Code)
+	if err != nil {
+		return err
+	}
+	if len(program.Parameter) < crypto.SignatureScriptLength {
+		return errors.New("Invalid signature length")
+	}
+	signature := program.Parameter[1:crypto.SignatureScriptLength]
+
+	// refund path
+	if isHTLCRefund(program) {
+		return crypto.Verify(*script.Sender, data, signature)
+	}
+
+	// claim path
+	preimage := program.Parameter[crypto.SignatureScriptLength:]
+	if int(preimage[0]) != len(preimage)-1 || len(preimage) == 1 ||
+		len(preimage)-1 > HTLCMaxPreimageLength {
+		return errors.New("invalid HTLC preimage")
+	}
+	if common.Uint256(sha256.Sum256(preimage[1:])) != script.Hash {
+		return errors.New("HTLC preimage not match")
+	}
+	return crypto.Verify(*script.Recipient, data, signature)
+}
+
+// isHTLCRefund returns if the hash time-locked contract program refunds the
+// contract to the sender, that is the parameter is the signature only.
+func isHTLCRefund(program Program) bool {
+	return len(program.Parameter) == crypto.SignatureScriptLength
+}
+
 func checkMultiSigSignatures(program Program, data []byte) error {
 	code := program.Code
 	// Get N parameter
@@ -184,7 +263,7 @@ func verifyMultisigSignatures(m, n int, publicKeys [][]byte, signatures, data []
 }
 
 func checkCrossChainArbitrators(publicKeys [][]byte) error {
-	arbitrators, err := config.Parameters.GetArbitrators()
+	arbitrators, err := DefaultLedger.Store.GetArbitrators(DefaultLedger.Store.GetHeight())
 	if err != nil {
 		return err
 	}
@@ -223,11 +302,11 @@ type byHash []*Program
 func (p byHash) Len() int      { return len(p) }
 func (p byHash) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
 func (p byHash) Less(i, j int) bool {
-	hashi, err := crypto.ToProgramHash(p[i].Code)
+	hashi, err := GetProgramHash(p[i].Code)
 	if err != nil {
 		panic(p[i].Code)
 	}
-	hashj, err := crypto.ToProgramHash(p[j].Code)
+	hashj, err := GetProgramHash(p[j].Code)
 	if err != nil {
 		panic(p[j].Code)
 	}
@@ -3,11 +3,13 @@ package blockchain
 import (
 	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	math "math/rand"
 	"sort"
 	"testing"
 
+	"github.com/wuyazero/Elastos.ELA/config"
 	"github.com/wuyazero/Elastos.ELA/core"
 
 	"github.com/wuyazero/Elastos.ELA.Utility/common"
@@ -302,6 +304,124 @@ func TestRunPrograms(t *testing.T) {
 	t.Log("TestRunPrograms passed")
 }
 
+func TestCheckHTLCSignature(t *testing.T) {
+	recipient := newAccount(t)
+	sender := newAccount(t)
+	preimage := make([]byte, 32)
+	rand.Read(preimage)
+	hash := common.Uint256(sha256.Sum256(preimage))
+	refundHeight := uint32(1000)
+
+	code, err := core.CreateHTLCRedeemScript(hash, recipient.public, sender.public, refundHeight)
+	assert.NoError(t, err)
+	assert.Equal(t, core.HTLCScriptLength, len(code))
+	programHash, err := core.GetProgramHash(code)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(core.PrefixHTLC), programHash[0])
+	assert.True(t, CheckOutputProgramHash(*programHash))
+
+	script, err := core.ParseHTLCScript(code)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, script.Hash)
+	assert.Equal(t, refundHeight, script.RefundHeight)
+
+	address, err := core.CreateHTLCAddress(hash, recipient.public, sender.public, refundHeight)
+	assert.NoError(t, err)
+	expected, _ := programHash.ToAddress()
+	assert.Equal(t, expected, address)
+
+	run := func(tx *core.Transaction, act *account, preimage []byte) error {
+		data := getData(tx)
+		parameter, err := act.Sign(data)
+		assert.NoError(t, err)
+		if preimage != nil {
+			parameter = append(parameter, byte(len(preimage)))
+			parameter = append(parameter, preimage...)
+		}
+		program := &core.Program{Code: code, Parameter: parameter}
+		return RunPrograms(data, []common.Uint168{*programHash}, []*core.Program{program})
+	}
+
+	// claim by the recipient with the preimage
+	tx := buildTx()
+	assert.NoError(t, run(tx, recipient, preimage))
+
+	// claim with a wrong preimage
+	fakePreimage := make([]byte, 32)
+	rand.Read(fakePreimage)
+	err = run(tx, recipient, fakePreimage)
+	assert.EqualError(t, err, "HTLC preimage not match")
+
+	// claim by the sender
+	assert.Error(t, run(tx, sender, preimage))
+
+	// refund by the sender, and by the recipient
+	assert.NoError(t, run(tx, sender, nil))
+	assert.Error(t, run(tx, recipient, nil))
+}
+
+func TestCheckTransactionHTLC(t *testing.T) {
+	recipient := newAccount(t)
+	sender := newAccount(t)
+	refundHeight := uint32(1000)
+	code, err := core.CreateHTLCRedeemScript(common.Uint256{1}, recipient.public, sender.public, refundHeight)
+	assert.NoError(t, err)
+	programHash, err := core.GetProgramHash(code)
+	assert.NoError(t, err)
+
+	htlcActivationHeight := config.Parameters.ChainParam.HTLCActivationHeight
+	lockTimeActivationHeight := config.Parameters.ChainParam.LockTimeActivationHeight
+	defer func() {
+		config.Parameters.ChainParam.HTLCActivationHeight = htlcActivationHeight
+		config.Parameters.ChainParam.LockTimeActivationHeight = lockTimeActivationHeight
+	}()
+	config.Parameters.ChainParam.HTLCActivationHeight = 100
+	config.Parameters.ChainParam.LockTimeActivationHeight = 0
+
+	// paid to a contract before the activation
+	tx := buildTx()
+	tx.Outputs[0].ProgramHash = *programHash
+	assert.EqualError(t, CheckTransactionHTLC(tx, 99), "HTLC is not activated")
+	assert.NoError(t, CheckTransactionHTLC(tx, 100))
+
+	// claimed before the activation and before the refund height
+	claim := make([]byte, crypto.SignatureScriptLength+33)
+	tx = buildTx()
+	tx.Programs = []*core.Program{{Code: code, Parameter: claim}}
+	assert.EqualError(t, CheckTransactionHTLC(tx, 99), "HTLC is not activated")
+	assert.NoError(t, CheckTransactionHTLC(tx, refundHeight-1))
+	assert.EqualError(t, CheckTransactionHTLC(tx, refundHeight), "HTLC claim height expired")
+
+	// refund before the refund height
+	refund := make([]byte, crypto.SignatureScriptLength)
+	tx.Programs = []*core.Program{{Code: code, Parameter: refund}}
+	tx.LockTime = refundHeight - 1
+	assert.EqualError(t, CheckTransactionHTLC(tx, refundHeight),
+		"HTLC refund height not reached")
+
+	// refund with final input sequences
+	tx.LockTime = refundHeight
+	for _, input := range tx.Inputs {
+		input.Sequence = MaxTxInSequenceNum
+	}
+	assert.EqualError(t, CheckTransactionHTLC(tx, refundHeight),
+		"HTLC refund lock time not enforced")
+
+	// refund after the refund height
+	tx.Inputs[0].Sequence = MaxTxInSequenceNum - 1
+	assert.NoError(t, CheckTransactionHTLC(tx, refundHeight))
+
+	// the input sequences are final by the same rule as IsFinalizedTransaction,
+	// MaxUint16 is only final before the lock time activation
+	for _, input := range tx.Inputs {
+		input.Sequence = 0xFFFF
+	}
+	assert.NoError(t, CheckTransactionHTLC(tx, refundHeight))
+	config.Parameters.ChainParam.LockTimeActivationHeight = refundHeight + 1
+	assert.EqualError(t, CheckTransactionHTLC(tx, refundHeight),
+		"HTLC refund lock time not enforced")
+}
+
 func newAccount(t *testing.T) *account {
 	a := new(account)
 	var err error
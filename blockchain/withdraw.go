@@ -0,0 +1,264 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+
+	. "github.com/wuyazero/Elastos.ELA/core"
+	"github.com/wuyazero/Elastos.ELA/events"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+// WithdrawStatus is the state of a side chain withdrawal in its lifecycle on
+// the main chain.
+type WithdrawStatus byte
+
+const (
+	// WithdrawUnseen means no withdraw transaction of the side chain
+	// transaction has been seen.
+	WithdrawUnseen WithdrawStatus = iota
+	// WithdrawPooled means a withdraw transaction of the side chain
+	// transaction is in the transaction pool.
+	WithdrawPooled
+	// WithdrawConfirmed means the withdraw transaction of the side chain
+	// transaction is in the best chain.
+	WithdrawConfirmed
+	// WithdrawRolledBack means the block including the withdraw transaction
+	// of the side chain transaction has been disconnected from the best chain.
+	WithdrawRolledBack
+	// WithdrawDropped means the withdraw transaction of the side chain
+	// transaction has been removed from the transaction pool without being
+	// confirmed, it is only published as a transition.
+	WithdrawDropped
+)
+
+func (s WithdrawStatus) String() string {
+	switch s {
+	case WithdrawUnseen:
+		return "unseen"
+	case WithdrawPooled:
+		return "pooled"
+	case WithdrawConfirmed:
+		return "confirmed"
+	case WithdrawRolledBack:
+		return "rolledback"
+	case WithdrawDropped:
+		return "dropped"
+	default:
+		return "unknown"
+	}
+}
+
+// WithdrawIndex records the last withdraw transaction included on chain for a
+// side chain transaction.
+type WithdrawIndex struct {
+	SideChainTxHash    Uint256
+	GenesisProgramHash Uint168
+	TxID               Uint256
+	Height             uint32
+	Status             WithdrawStatus
+}
+
+func (w *WithdrawIndex) Serialize(writer io.Writer) error {
+	if err := w.GenesisProgramHash.Serialize(writer); err != nil {
+		return err
+	}
+	if err := w.TxID.Serialize(writer); err != nil {
+		return err
+	}
+	if err := WriteUint32(writer, w.Height); err != nil {
+		return err
+	}
+	return WriteUint8(writer, uint8(w.Status))
+}
+
+func (w *WithdrawIndex) Deserialize(reader io.Reader) error {
+	if err := w.GenesisProgramHash.Deserialize(reader); err != nil {
+		return err
+	}
+	if err := w.TxID.Deserialize(reader); err != nil {
+		return err
+	}
+	height, err := ReadUint32(reader)
+	if err != nil {
+		return err
+	}
+	w.Height = height
+	status, err := ReadUint8(reader)
+	if err != nil {
+		return err
+	}
+	w.Status = WithdrawStatus(status)
+	return nil
+}
+
+func getWithdrawKey(sidechainTxHash Uint256) []byte {
+	return append([]byte{byte(IX_Withdraw)}, sidechainTxHash.Bytes()...)
+}
+
+func getWithdrawAddressPrefix(programHash Uint168) []byte {
+	return append([]byte{byte(IX_Withdraw_Address)}, programHash.Bytes()...)
+}
+
+// getWithdrawAddressKey returns the key of the withdrawal in the index of the
+// genesis address, indexed by the height it is included on chain.
+func getWithdrawAddressKey(programHash Uint168, height uint32, sidechainTxHash Uint256) []byte {
+	return getHeightIndexKey(getWithdrawAddressPrefix(programHash), height, sidechainTxHash)
+}
+
+func (c *ChainStore) putWithdraw(index *WithdrawIndex) error {
+	value := new(bytes.Buffer)
+	if err := index.Serialize(value); err != nil {
+		return err
+	}
+	c.BatchPut(getWithdrawKey(index.SideChainTxHash), value.Bytes())
+	return nil
+}
+
+// PersistWithdraw marks the side chain transactions of the withdraw
+// transaction confirmed at the height.
+func (c *ChainStore) PersistWithdraw(txn *Transaction, height uint32) error {
+	payload, ok := txn.Payload.(*PayloadWithdrawFromSideChain)
+	if !ok {
+		return nil
+	}
+	programHash, err := Uint168FromAddress(payload.GenesisBlockAddress)
+	if err != nil {
+		return err
+	}
+	for _, hash := range payload.SideChainTransactionHashes {
+		// a withdrawal rolled back and confirmed again at another height is
+		// listed at the new height only
+		if old, err := c.GetWithdraw(hash); err == nil && old.Height != height {
+			c.BatchDelete(getWithdrawAddressKey(old.GenesisProgramHash, old.Height, hash))
+		}
+		err := c.putWithdraw(&WithdrawIndex{
+			SideChainTxHash:    hash,
+			GenesisProgramHash: *programHash,
+			TxID:               txn.Hash(),
+			Height:             height,
+			Status:             WithdrawConfirmed,
+		})
+		if err != nil {
+			return err
+		}
+		c.BatchPut(getWithdrawAddressKey(*programHash, height, hash), []byte{byte(ValueExist)})
+	}
+	return nil
+}
+
+// RollbackWithdraw marks the side chain transactions of the withdraw
+// transaction rolled back, the withdraw transaction and height are kept so the
+// arbiters can tell which confirmation was undone.
+func (c *ChainStore) RollbackWithdraw(txn *Transaction, height uint32) error {
+	payload, ok := txn.Payload.(*PayloadWithdrawFromSideChain)
+	if !ok {
+		return nil
+	}
+	programHash, err := Uint168FromAddress(payload.GenesisBlockAddress)
+	if err != nil {
+		return err
+	}
+	for _, hash := range payload.SideChainTransactionHashes {
+		err := c.putWithdraw(&WithdrawIndex{
+			SideChainTxHash:    hash,
+			GenesisProgramHash: *programHash,
+			TxID:               txn.Hash(),
+			Height:             height,
+			Status:             WithdrawRolledBack,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getWithdrawTransitions returns the status of the side chain transactions
+// withdrawn by the transaction when it is moved into the status at the height.
+func getWithdrawTransitions(txn *Transaction, status WithdrawStatus, height uint32) []*WithdrawIndex {
+	payload, ok := txn.Payload.(*PayloadWithdrawFromSideChain)
+	if !ok {
+		return nil
+	}
+	programHash, err := Uint168FromAddress(payload.GenesisBlockAddress)
+	if err != nil {
+		return nil
+	}
+	transitions := make([]*WithdrawIndex, 0, len(payload.SideChainTransactionHashes))
+	for _, hash := range payload.SideChainTransactionHashes {
+		// a withdrawal confirmed by another transaction is not dropped
+		if status == WithdrawDropped {
+			if index, err := DefaultLedger.Store.GetWithdraw(hash); err == nil &&
+				index.Status == WithdrawConfirmed {
+				continue
+			}
+		}
+		transitions = append(transitions, &WithdrawIndex{
+			SideChainTxHash:    hash,
+			GenesisProgramHash: *programHash,
+			TxID:               txn.Hash(),
+			Height:             height,
+			Status:             status,
+		})
+	}
+	return transitions
+}
+
+// notifyWithdrawTransitions publishes the status changes of the withdrawals
+// made by the transactions in order.
+func notifyWithdrawTransitions(txs []*Transaction, status WithdrawStatus, height uint32) {
+	var transitions []*WithdrawIndex
+	for _, txn := range txs {
+		transitions = append(transitions, getWithdrawTransitions(txn, status, height)...)
+	}
+	if len(transitions) > 0 {
+		DefaultLedger.Blockchain.BCEvents.NotifySync(events.EventWithdrawStatusChanged, transitions)
+	}
+}
+
+// GetWithdraw returns the on chain state of the withdrawal of the side chain
+// transaction.
+func (c *ChainStore) GetWithdraw(sidechainTxHash Uint256) (*WithdrawIndex, error) {
+	data, err := c.Get(getWithdrawKey(sidechainTxHash))
+	if err != nil {
+		return nil, err
+	}
+	index := &WithdrawIndex{SideChainTxHash: sidechainTxHash}
+	if err := index.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// GetWithdraws returns the withdrawals of the side chain with the genesis
+// address program hash in the order they are included on chain, after the
+// withdrawal of the side chain transaction start if it is not nil. At most
+// count withdrawals are returned, or all of them if count is 0.
+func (c *ChainStore) GetWithdraws(programHash Uint168, start *Uint256, count int) ([]*WithdrawIndex, error) {
+	prefix := getWithdrawAddressPrefix(programHash)
+	var iter *heightIndexIterator
+	if start != nil {
+		index, err := c.GetWithdraw(*start)
+		if err != nil || index.GenesisProgramHash != programHash {
+			return nil, errors.New("start withdraw not found")
+		}
+		iter = c.newHeightIndexIteratorAfter(prefix, index.Height, *start)
+	} else {
+		iter = c.newHeightIndexIterator(prefix, 0, math.MaxUint32)
+	}
+	defer iter.Release()
+
+	withdraws := make([]*WithdrawIndex, 0)
+	for (count == 0 || len(withdraws) < count) && iter.Next() {
+		index, err := c.GetWithdraw(iter.Hash())
+		if err != nil {
+			return nil, errors.New("withdraw index not found")
+		}
+		withdraws = append(withdraws, index)
+	}
+	return withdraws, nil
+}
@@ -12,6 +12,31 @@ import (
 
 // NewMerkleBlock returns a new *MerkleBlock
 func NewMerkleBlock(block *core.Block, filter *Filter) (*msg.MerkleBlock, []uint32) {
+	// Find and keep track of any transactions that match the filter.
+	var matchedIndexes []uint32
+	hashes, flags := buildPartialMerkleTree(block, func(index int, tx *core.Transaction) bool {
+		if filter.MatchTxAndUpdate(tx) {
+			matchedIndexes = append(matchedIndexes, uint32(index))
+			return true
+		}
+		return false
+	})
+
+	// Create and return the merkle block.
+	merkleBlock := &msg.MerkleBlock{
+		Header:       &block.Header,
+		Transactions: uint32(len(block.Transactions)),
+		Hashes:       hashes,
+		Flags:        flags,
+	}
+	return merkleBlock, matchedIndexes
+}
+
+// buildPartialMerkleTree builds the depth-first partial merkle tree of the
+// transactions of the block keeping the ones matched, and returns the hashes
+// and the flag bits of the tree.
+func buildPartialMerkleTree(block *core.Block,
+	match func(index int, tx *core.Transaction) bool) ([]*common.Uint256, []byte) {
 	NumTx := uint32(len(block.Transactions))
 	mBlock := MBlock{
 		NumTx:       NumTx,
@@ -19,12 +44,9 @@ func NewMerkleBlock(block *core.Block, filter *Filter) (*msg.MerkleBlock, []uint
 		MatchedBits: make([]byte, 0, NumTx),
 	}
 
-	// Find and keep track of any transactions that match the filter.
-	var matchedIndexes []uint32
 	for index, tx := range block.Transactions {
-		if filter.MatchTxAndUpdate(tx) {
+		if match(index, tx) {
 			mBlock.MatchedBits = append(mBlock.MatchedBits, 0x01)
-			matchedIndexes = append(matchedIndexes, uint32(index))
 		} else {
 			mBlock.MatchedBits = append(mBlock.MatchedBits, 0x00)
 		}
@@ -41,21 +63,15 @@ func NewMerkleBlock(block *core.Block, filter *Filter) (*msg.MerkleBlock, []uint
 	// Build the depth-first partial merkle tree.
 	mBlock.TraverseAndBuild(height, 0)
 
-	// Create and return the merkle block.
-	merkleBlock := &msg.MerkleBlock{
-		Header:       &block.Header,
-		Transactions: mBlock.NumTx,
-		Hashes:       make([]*common.Uint256, 0, len(mBlock.FinalHashes)),
-		Flags:        make([]byte, (len(mBlock.Bits)+7)/8),
-	}
+	hashes := make([]*common.Uint256, 0, len(mBlock.FinalHashes))
 	for _, hash := range mBlock.FinalHashes {
-		merkleBlock.Hashes = append(merkleBlock.Hashes, hash)
+		hashes = append(hashes, hash)
 	}
+	flags := make([]byte, (len(mBlock.Bits)+7)/8)
 	for i := uint32(0); i < uint32(len(mBlock.Bits)); i++ {
-		merkleBlock.Flags[i/8] |= mBlock.Bits[i] << (i % 8)
+		flags[i/8] |= mBlock.Bits[i] << (i % 8)
 	}
-
-	return merkleBlock, matchedIndexes
+	return hashes, flags
 }
 
 type merkleNode struct {
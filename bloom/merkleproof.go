@@ -1,9 +1,14 @@
 package bloom
 
 import (
+	"errors"
+	"fmt"
 	"io"
 
+	"github.com/wuyazero/Elastos.ELA/core"
+
 	"github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/p2p/msg"
 )
 
 type MerkleProof struct {
@@ -31,6 +36,9 @@ func (p *MerkleProof) Deserialize(r io.Reader) error {
 		&p.Height,
 		&p.Transactions,
 	)
+	if err != nil {
+		return err
+	}
 
 	hashes, err := common.ReadUint32(r)
 	if err != nil {
@@ -40,3 +48,57 @@ func (p *MerkleProof) Deserialize(r io.Reader) error {
 	p.Hashes = make([]*common.Uint256, hashes)
 	return common.ReadElements(r, &p.Hashes, &p.Flags)
 }
+
+// NewMerkleProof returns the partial merkle tree proving the transaction is
+// included in the block, in the same form as a merkle block matching only
+// the transaction.
+func NewMerkleProof(block *core.Block, txId common.Uint256) (*MerkleProof, error) {
+	found := false
+	hashes, flags := buildPartialMerkleTree(block, func(index int, tx *core.Transaction) bool {
+		if tx.Hash() == txId {
+			found = true
+			return true
+		}
+		return false
+	})
+	if !found {
+		return nil, errors.New("transaction not found in block")
+	}
+
+	return &MerkleProof{
+		BlockHash:    block.Hash(),
+		Height:       block.Header.Height,
+		Transactions: uint32(len(block.Transactions)),
+		Hashes:       hashes,
+		Flags:        flags,
+	}, nil
+}
+
+// VerifyMerkleProof checks the proof shows the transaction is included in the
+// block of the header. The header is expected to come from the verifier's own
+// header chain, so the proof can be checked without trusting its source.
+func VerifyMerkleProof(proof *MerkleProof, header *core.Header, txId common.Uint256) error {
+	if proof.BlockHash != header.Hash() {
+		return errors.New("proof block hash not match with header")
+	}
+	if proof.Height != header.Height {
+		return fmt.Errorf("proof height %d not match with header height %d",
+			proof.Height, header.Height)
+	}
+
+	matched, err := CheckMerkleBlock(msg.MerkleBlock{
+		Header:       header,
+		Transactions: proof.Transactions,
+		Hashes:       proof.Hashes,
+		Flags:        proof.Flags,
+	})
+	if err != nil {
+		return err
+	}
+	for _, hash := range matched {
+		if *hash == txId {
+			return nil
+		}
+	}
+	return errors.New("transaction not matched in proof")
+}
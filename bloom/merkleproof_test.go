@@ -0,0 +1,82 @@
+package bloom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
+	"github.com/wuyazero/Elastos.ELA/core"
+)
+
+func TestVerifyMerkleProof(t *testing.T) {
+	for txs := 1; txs < 1<<6; txs++ {
+		block := &core.Block{Header: core.Header{Height: uint32(txs)}}
+		hashes := make([]common.Uint256, 0, txs)
+		for i := 0; i < txs; i++ {
+			tx := &core.Transaction{
+				TxType:  core.Record,
+				Payload: &core.PayloadRecord{RecordType: "test", RecordData: randHash().Bytes()},
+			}
+			block.Transactions = append(block.Transactions, tx)
+			hashes = append(hashes, tx.Hash())
+		}
+		merkleRoot, err := crypto.ComputeRoot(hashes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.Header.MerkleRoot = merkleRoot
+
+		for _, hash := range hashes {
+			proof, err := NewMerkleProof(block, hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the proof is sent to the side chain serialized
+			buf := new(bytes.Buffer)
+			if err := proof.Serialize(buf); err != nil {
+				t.Fatal(err)
+			}
+			var received MerkleProof
+			if err := received.Deserialize(buf); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := VerifyMerkleProof(&received, &block.Header, hash); err != nil {
+				t.Fatalf("verify proof failed with txs: %d, %s", txs, err)
+			}
+			if err := VerifyMerkleProof(&received, &block.Header, *randHash()); err == nil {
+				t.Fatalf("verified a transaction not in proof with txs: %d", txs)
+			}
+		}
+	}
+
+	block := &core.Block{Transactions: []*core.Transaction{{
+		TxType:  core.Record,
+		Payload: &core.PayloadRecord{RecordType: "test", RecordData: randHash().Bytes()},
+	}}}
+	txId := block.Transactions[0].Hash()
+	block.Header.MerkleRoot = txId
+	proof, err := NewMerkleProof(block, txId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a header from another chain or with another merkle root is rejected
+	header := block.Header
+	header.Height++
+	if err := VerifyMerkleProof(proof, &header, txId); err == nil {
+		t.Error("verified the proof against another header")
+	}
+	header.MerkleRoot = *randHash()
+	proof.BlockHash = header.Hash()
+	proof.Height = header.Height
+	if err := VerifyMerkleProof(proof, &header, txId); err == nil {
+		t.Error("verified the proof against a wrong merkle root")
+	}
+
+	if _, err := NewMerkleProof(block, *randHash()); err == nil {
+		t.Error("created the proof of a transaction not in block")
+	}
+}
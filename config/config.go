@@ -23,37 +23,102 @@ var (
 	Parameters configParams
 	Version    string
 	mainNet    = &ChainParams{
-		Name:               "MainNet",
-		PowLimit:           new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)),
-		PowLimitBits:       0x1f0008ff,
-		TargetTimePerBlock: time.Minute * 2,
-		TargetTimespan:     time.Minute * 2 * 720,
-		AdjustmentFactor:   int64(4),
-		MaxOrphanBlocks:    10000,
-		MinMemoryNodes:     20160,
-		CoinbaseLockTime:   100,
+		Name:                             "MainNet",
+		PowLimit:                         new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)),
+		PowLimitBits:                     0x1f0008ff,
+		TargetTimePerBlock:               time.Minute * 2,
+		TargetTimespan:                   time.Minute * 2 * 720,
+		AdjustmentFactor:                 int64(4),
+		MaxOrphanBlocks:                  10000,
+		MinMemoryNodes:                   20160,
+		CoinbaseLockTime:                 100,
+		LockTimeActivationHeight:         200000,
+		RelativeLockTimeActivationHeight: 200000,
+		AssetActivationHeight:            200000,
+		HTLCActivationHeight:             200000,
+		FoundationRewardPercent:          30,
+		MinerRewardPercent:               35,
+		RewardSplitActivationHeight:      200000,
+		GenesisSupply:                    3300 * 10000 * 100000000,
+		SubsidySchedule:                  InflationSchedule,
+		InflationPercentPerYear:          4,
+		ProducerDeposit:                  5000 * 100000000,
+		ProducerActivationHeight:         200000,
+		MaxElectedProducers:              36,
+		MaxVoteCandidates:                36,
+		MaxArbitrators:                   16,
+		SideChainActivationHeight:        200000,
+		TxVersionActivationHeight:        200000,
+		Arbiters: []string{
+			"0248df6705a909432be041e0baa25b8f648741018f70d1911f2ed28778db4b8fe4",
+			"02771faf0f4d4235744b30972d5f2c470993920846c761e4d08889ecfdc061cddf",
+			"0342196610e57d75ba3afa26e030092020aec56822104e465cba1d8f69f8d83c8e",
+			"02fa3e0d14e0e93ca41c3c0f008679e417cf2adb6375dd4bbbee9ed8e8db606a56",
+			"03ab3ecd1148b018d480224520917c6c3663a3631f198e3b25cf4c9c76786b7850",
+		},
 	}
 	testNet = &ChainParams{
-		Name:               "TestNet",
-		PowLimit:           new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)),
-		PowLimitBits:       0x1e1da5ff,
-		TargetTimePerBlock: time.Second * 10,
-		TargetTimespan:     time.Second * 10 * 10,
-		AdjustmentFactor:   int64(4),
-		MaxOrphanBlocks:    10000,
-		MinMemoryNodes:     20160,
-		CoinbaseLockTime:   100,
+		Name:                             "TestNet",
+		PowLimit:                         new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)),
+		PowLimitBits:                     0x1e1da5ff,
+		TargetTimePerBlock:               time.Second * 10,
+		TargetTimespan:                   time.Second * 10 * 10,
+		AdjustmentFactor:                 int64(4),
+		MaxOrphanBlocks:                  10000,
+		MinMemoryNodes:                   20160,
+		CoinbaseLockTime:                 100,
+		LockTimeActivationHeight:         150000,
+		RelativeLockTimeActivationHeight: 150000,
+		AssetActivationHeight:            150000,
+		HTLCActivationHeight:             150000,
+		FoundationRewardPercent:          30,
+		MinerRewardPercent:               35,
+		RewardSplitActivationHeight:      150000,
+		GenesisSupply:                    3300 * 10000 * 100000000,
+		SubsidySchedule:                  InflationSchedule,
+		InflationPercentPerYear:          4,
+		ProducerDeposit:                  5000 * 100000000,
+		ProducerActivationHeight:         150000,
+		MaxElectedProducers:              36,
+		MaxVoteCandidates:                36,
+		MaxArbitrators:                   16,
+		SideChainActivationHeight:        150000,
+		TxVersionActivationHeight:        150000,
+		Arbiters: []string{
+			"03e333657c788a20577c0288559bd489ee65514748d18cb1dc7560ae4ce3d45613",
+			"02dd22722c3b3a284929e4859b07e6a706595066ddd2a0b38e5837403718fb047c",
+			"03e4473b918b499e4112d281d805fc8d8ae7ac0a71ff938cba78006bf12dd90a85",
+			"03dd66833d28bac530ca80af0efbfc2ec43b4b87504a41ab4946702254e7f48961",
+			"02c8a87c076112a1b344633184673cfb0bb6bce1aca28c78986a7b1047d257a448",
+		},
 	}
 	regNet = &ChainParams{
-		Name:               "RegNet",
-		PowLimit:           new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)),
-		PowLimitBits:       0x207fffff,
-		TargetTimePerBlock: time.Second * 1,
-		TargetTimespan:     time.Second * 1 * 10,
-		AdjustmentFactor:   int64(4),
-		MaxOrphanBlocks:    10000,
-		MinMemoryNodes:     20160,
-		CoinbaseLockTime:   100,
+		Name:                             "RegNet",
+		PowLimit:                         new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)),
+		PowLimitBits:                     0x207fffff,
+		TargetTimePerBlock:               time.Second * 1,
+		TargetTimespan:                   time.Second * 1 * 10,
+		AdjustmentFactor:                 int64(4),
+		MaxOrphanBlocks:                  10000,
+		MinMemoryNodes:                   20160,
+		CoinbaseLockTime:                 100,
+		LockTimeActivationHeight:         0,
+		RelativeLockTimeActivationHeight: 0,
+		AssetActivationHeight:            0,
+		HTLCActivationHeight:             0,
+		FoundationRewardPercent:          30,
+		MinerRewardPercent:               35,
+		RewardSplitActivationHeight:      0,
+		GenesisSupply:                    3300 * 10000 * 100000000,
+		SubsidySchedule:                  InflationSchedule,
+		InflationPercentPerYear:          4,
+		ProducerDeposit:                  1 * 100000000,
+		ProducerActivationHeight:         0,
+		MaxElectedProducers:              36,
+		MaxVoteCandidates:                36,
+		MaxArbitrators:                   16,
+		SideChainActivationHeight:        0,
+		TxVersionActivationHeight:        0,
 	}
 )
 
@@ -94,6 +159,7 @@ type Configuration struct {
 	MaxPerLogSize       int64            `json:"MaxPerLogSize"`
 	MaxTxsInBlock       int              `json:"MaxTransactionInBlock"`
 	MaxBlockSize        int              `json:"MaxBlockSize"`
+	TxPoolExpiry        uint32           `json:"TxPoolExpiry"`
 	PowConfiguration    PowConfiguration `json:"PowConfiguration"`
 	Arbiters            []string         `json:"Arbiters"`
 }
@@ -112,8 +178,76 @@ type ChainParams struct {
 	MaxOrphanBlocks    int
 	MinMemoryNodes     uint32
 	CoinbaseLockTime   uint32
+	// LockTimeActivationHeight is the height since which lock times above
+	// the threshold are Unix timestamps and only MaxUint32 sequence is final
+	LockTimeActivationHeight uint32
+	// RelativeLockTimeActivationHeight is the height since which input
+	// sequences are interpreted as relative lock times
+	RelativeLockTimeActivationHeight uint32
+	// AssetActivationHeight is the height since which RegisterAsset
+	// transactions issue user assets identified by the payload, the assets
+	// registered before are identified by the transaction hash and issue
+	// nothing
+	AssetActivationHeight uint32
+	// HTLCActivationHeight is the height since which the outputs can be paid
+	// to and the inputs can be redeemed by hash time-locked contracts
+	HTLCActivationHeight uint32
+	// FoundationRewardPercent and MinerRewardPercent are the shares of the
+	// coinbase reward in percent rounded down, the remainder goes to the
+	// delegates
+	FoundationRewardPercent int64
+	MinerRewardPercent      int64
+	// RewardSplitActivationHeight is the height since which the coinbase must
+	// pay each share of the reward exactly
+	RewardSplitActivationHeight uint32
+	// GenesisSupply is the amount issued by the genesis block
+	GenesisSupply common.Fixed64
+	// SubsidySchedule decides the subsidy of the blocks after the genesis
+	SubsidySchedule SubsidySchedule
+	// InflationPercentPerYear is the amount issued in a year in percent of the
+	// supply at the beginning of the year, for the inflation schedule
+	InflationPercentPerYear int64
+	// InitialSubsidy is the subsidy of the first era, and the subsidy is halved
+	// every SubsidyHalvingInterval blocks, for the halving schedule
+	InitialSubsidy         common.Fixed64
+	SubsidyHalvingInterval uint32
+	// ProducerDeposit is the least amount a block producer locks as deposit
+	// until the producer is canceled
+	ProducerDeposit common.Fixed64
+	// ProducerActivationHeight is the height since which the producers can be
+	// registered, canceled and voted for
+	ProducerActivationHeight uint32
+	// MaxElectedProducers is the number of the producers with the most votes
+	// elected to share the delegate reward
+	MaxElectedProducers int
+	// MaxVoteCandidates is the most candidates a vote can vote for
+	MaxVoteCandidates int
+	// Arbiters are the public keys of the arbitrators since the genesis block,
+	// the arbitrators configured by the Arbiters of the config file are used
+	// if it is empty
+	Arbiters []string
+	// MaxArbitrators is the most arbitrators an UpdateArbitrators transaction
+	// can set
+	MaxArbitrators int
+	// SideChainActivationHeight is the height since which the cross chain
+	// transfers and the withdrawals must be of a registered side chain
+	SideChainActivationHeight uint32
+	// TxVersionActivationHeight is the height since which transactions of
+	// versions other than the default one are accepted
+	TxVersionActivationHeight uint32
 }
 
+// SubsidySchedule is the way the subsidy of a block is calculated.
+type SubsidySchedule byte
+
+const (
+	// InflationSchedule issues a percent of the supply every year, the
+	// subsidy of a year is compounded on the supply of the previous years.
+	InflationSchedule SubsidySchedule = iota
+	// HalvingSchedule halves the subsidy every era.
+	HalvingSchedule
+)
+
 type configParams struct {
 	*Configuration
 	ChainParam *ChainParams
@@ -145,14 +279,19 @@ func init() {
 	}
 }
 
+// GetArbitrators returns the arbitrators since the genesis block, which are
+// changed on chain by the UpdateArbitrators transactions.
 func (config *Configuration) GetArbitrators() ([][]byte, error) {
-	//todo finish this when arbitrator election scenario is done
-	if len(config.Arbiters) == 0 {
+	arbiters := Parameters.ChainParam.Arbiters
+	if len(arbiters) == 0 {
+		arbiters = config.Arbiters
+	}
+	if len(arbiters) == 0 {
 		return nil, errors.New("arbiters not configured")
 	}
 
 	var arbitersByte [][]byte
-	for _, arbiter := range config.Arbiters {
+	for _, arbiter := range arbiters {
 		arbiterByte, err := common.HexStringToBytes(arbiter)
 		if err != nil {
 			return nil, err
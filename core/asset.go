@@ -34,6 +34,11 @@ type Asset struct {
 	Precision   byte
 	AssetType   AssetType
 	RecordType  AssetRecordType
+
+	// MaxSupply is the maximum amount of the asset can be issued, zero means
+	// unlimited. It is not a part of the asset serialization, but serialized
+	// by PayloadRegisterAsset since version 0x01.
+	MaxSupply common.Fixed64
 }
 
 // Serialize is the implement of SignableData interface.
@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"io"
 
 	. "github.com/wuyazero/Elastos.ELA.Utility/common"
@@ -18,31 +19,36 @@ const (
 	Confirmations  AttributeUsage = 0x92
 )
 
+// attributeUsages are the names of the valid attribute usages.
+var attributeUsages = map[AttributeUsage]string{
+	Nonce:          "Nonce",
+	Script:         "Script",
+	Memo:           "Memo",
+	Description:    "Description",
+	DescriptionUrl: "DescriptionUrl",
+	Confirmations:  "Confirmations",
+}
+
+// RegisterAttribute registers a valid attribute usage with its name. It is
+// meant to be called by init functions, and panics if the usage is already
+// registered.
+func RegisterAttribute(usage AttributeUsage, name string) {
+	if _, ok := attributeUsages[usage]; ok {
+		panic(fmt.Sprintf("core: RegisterAttribute called twice for usage 0x%02x", byte(usage)))
+	}
+	attributeUsages[usage] = name
+}
+
 func (it AttributeUsage) Name() string {
-	switch it {
-	case Nonce:
-		return "Nonce"
-	case Script:
-		return "Script"
-	case Memo:
-		return "Memo"
-	case Description:
-		return "Description"
-	case DescriptionUrl:
-		return "DescriptionUrl"
-	case Confirmations:
-		return "Confirmations"
-	default:
-		return "Unknown"
+	if name, ok := attributeUsages[it]; ok {
+		return name
 	}
+	return "Unknown"
 }
 
 func IsValidAttributeType(usage AttributeUsage) bool {
-	switch usage {
-	case Nonce, Script, Memo, Description, DescriptionUrl, Confirmations:
-		return true
-	}
-	return false
+	_, ok := attributeUsages[usage]
+	return ok
 }
 
 type Attribute struct {
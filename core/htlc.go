@@ -0,0 +1,148 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// HTLC is the script type of a hash time-locked contract program code.
+	HTLC = 0xb0
+	// PrefixHTLC is the program hash prefix of a hash time-locked contract.
+	PrefixHTLC = 0x1c
+
+	// HTLCHashLength is the length of the SHA256 hash of the preimage.
+	HTLCHashLength = sha256.Size
+	// HTLCMaxPreimageLength is the maximum length of the preimage, the
+	// preimage is pushed by a single length byte.
+	HTLCMaxPreimageLength = 75
+	// HTLCScriptLength is the length of a hash time-locked contract code:
+	// hash, recipient public key, sender public key, refund height and type.
+	HTLCScriptLength = 1 + HTLCHashLength + 2*(crypto.PublicKeyScriptLength-1) + 1 + 4 + 1
+)
+
+// HTLCScript is the parsed hash time-locked contract program code. The
+// outputs locked by the contract can be claimed by the recipient with the
+// preimage of Hash, or refunded to the sender since RefundHeight.
+type HTLCScript struct {
+	Hash         Uint256
+	Recipient    *crypto.PublicKey
+	Sender       *crypto.PublicKey
+	RefundHeight uint32
+}
+
+// CreateHTLCRedeemScript creates the hash time-locked contract program code.
+func CreateHTLCRedeemScript(hash Uint256, recipient, sender *crypto.PublicKey, refundHeight uint32) ([]byte, error) {
+	if recipient == nil || sender == nil {
+		return nil, errors.New("[CreateHTLCRedeemScript], public key is nil")
+	}
+	recipientKey, err := recipient.EncodePoint(true)
+	if err != nil {
+		return nil, err
+	}
+	senderKey, err := sender.EncodePoint(true)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteByte(byte(len(hash)))
+	buf.Write(hash[:])
+	buf.WriteByte(byte(len(recipientKey)))
+	buf.Write(recipientKey)
+	buf.WriteByte(byte(len(senderKey)))
+	buf.Write(senderKey)
+	buf.WriteByte(4)
+	WriteUint32(buf, refundHeight)
+	buf.WriteByte(HTLC)
+	return buf.Bytes(), nil
+}
+
+// ParseHTLCScript parses the hash time-locked contract program code.
+func ParseHTLCScript(code []byte) (*HTLCScript, error) {
+	if !IsHTLCScript(code) {
+		return nil, errors.New("[ParseHTLCScript], invalid HTLC script")
+	}
+
+	r := bytes.NewReader(code)
+	script := new(HTLCScript)
+	if _, err := r.ReadByte(); err != nil {
+		return nil, err
+	}
+	if err := script.Hash.Deserialize(r); err != nil {
+		return nil, err
+	}
+	var err error
+	if script.Recipient, err = readHTLCPublicKey(r); err != nil {
+		return nil, err
+	}
+	if script.Sender, err = readHTLCPublicKey(r); err != nil {
+		return nil, err
+	}
+	if _, err := r.ReadByte(); err != nil {
+		return nil, err
+	}
+	if script.RefundHeight, err = ReadUint32(r); err != nil {
+		return nil, err
+	}
+	return script, nil
+}
+
+func readHTLCPublicKey(r *bytes.Reader) (*crypto.PublicKey, error) {
+	if _, err := r.ReadByte(); err != nil {
+		return nil, err
+	}
+	key := make([]byte, crypto.PublicKeyScriptLength-2)
+	if _, err := r.Read(key); err != nil {
+		return nil, err
+	}
+	return crypto.DecodePoint(key)
+}
+
+// IsHTLCScript checks if the code is formed as a hash time-locked contract.
+func IsHTLCScript(code []byte) bool {
+	if len(code) != HTLCScriptLength || code[len(code)-1] != HTLC {
+		return false
+	}
+	keyLength := byte(crypto.PublicKeyScriptLength - 2)
+	return code[0] == HTLCHashLength &&
+		code[1+HTLCHashLength] == keyLength &&
+		code[2+HTLCHashLength+int(keyLength)] == keyLength &&
+		code[3+HTLCHashLength+2*int(keyLength)] == 4
+}
+
+// GetProgramHash returns the program hash of the code, hash time-locked
+// contracts are supported besides the script types of crypto.ToProgramHash.
+func GetProgramHash(code []byte) (*Uint168, error) {
+	if len(code) < 1 || code[len(code)-1] != HTLC {
+		return crypto.ToProgramHash(code)
+	}
+	if !IsHTLCScript(code) {
+		return nil, errors.New("[GetProgramHash], invalid HTLC script")
+	}
+
+	sum := sha256.Sum256(code)
+	md160 := ripemd160.New()
+	md160.Write(sum[:])
+	return Uint168FromBytes(append([]byte{PrefixHTLC}, md160.Sum(nil)...))
+}
+
+// CreateHTLCAddress returns the address of the hash time-locked contract which
+// can be claimed by the recipient with the preimage of hash, or refunded to
+// the sender since the refund height.
+func CreateHTLCAddress(hash Uint256, recipient, sender *crypto.PublicKey, refundHeight uint32) (string, error) {
+	code, err := CreateHTLCRedeemScript(hash, recipient, sender, refundHeight)
+	if err != nil {
+		return "", err
+	}
+	programHash, err := GetProgramHash(code)
+	if err != nil {
+		return "", err
+	}
+	return programHash.ToAddress()
+}
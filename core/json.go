@@ -0,0 +1,814 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+// The JSON encoding of the core types is round-trippable with the binary
+// serialization. Hashes are encoded as reversed hex strings like the RPC
+// interfaces do, program hashes are encoded as hex strings along with their
+// addresses, byte slices are encoded as hex strings and amounts are encoded as
+// decimal strings.
+
+func toReversedString(hash Uint256) string {
+	return BytesToHexString(BytesReverse(hash[:]))
+}
+
+func fromReversedString(reversed string) (Uint256, error) {
+	buf, err := HexStringToBytes(reversed)
+	if err != nil {
+		return Uint256{}, err
+	}
+	hash, err := Uint256FromBytes(BytesReverse(buf))
+	if err != nil {
+		return Uint256{}, err
+	}
+	return *hash, nil
+}
+
+// fromProgramHash returns the hex string and the address of the program
+// hash, the address is empty if it can not be decoded to the program hash.
+func fromProgramHash(programHash Uint168) (string, string) {
+	address, err := programHash.ToAddress()
+	if err != nil {
+		return BytesToHexString(programHash[:]), ""
+	}
+	if decoded, err := Uint168FromAddress(address); err != nil || *decoded != programHash {
+		return BytesToHexString(programHash[:]), ""
+	}
+	return BytesToHexString(programHash[:]), address
+}
+
+// toProgramHash decodes the program hash from the hex string, or from the
+// address if the hex string is empty.
+func toProgramHash(hexString, address string) (Uint168, error) {
+	if hexString == "" {
+		programHash, err := Uint168FromAddress(address)
+		if err != nil {
+			return Uint168{}, err
+		}
+		return *programHash, nil
+	}
+	buf, err := HexStringToBytes(hexString)
+	if err != nil {
+		return Uint168{}, err
+	}
+	programHash, err := Uint168FromBytes(buf)
+	if err != nil {
+		return Uint168{}, err
+	}
+	return *programHash, nil
+}
+
+func fromHexString(s string) ([]byte, error) {
+	if s == "" {
+		return []byte{}, nil
+	}
+	return HexStringToBytes(s)
+}
+
+// parseFixed64 parses the decimal string of Fixed64.String without the
+// precision loss of a float.
+func parseFixed64(s string) (Fixed64, error) {
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+	parts := strings.SplitN(s, ".", 2)
+	if parts[0] == "" || strings.HasPrefix(parts[0], "+") {
+		return 0, fmt.Errorf("invalid amount %s", s)
+	}
+	integer, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || integer > uint64(math.MaxInt64)/1e8 {
+		return 0, fmt.Errorf("invalid amount %s", s)
+	}
+	var fraction uint64
+	if len(parts) == 2 {
+		if len(parts[1]) == 0 || len(parts[1]) > 8 || strings.HasPrefix(parts[1], "+") {
+			return 0, fmt.Errorf("invalid amount %s", s)
+		}
+		fraction, err = strconv.ParseUint(parts[1]+strings.Repeat("0", 8-len(parts[1])), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %s", s)
+		}
+	}
+	value := int64(integer*1e8 + fraction)
+	if value < 0 {
+		return 0, fmt.Errorf("invalid amount %s", s)
+	}
+	if negative {
+		value = -value
+	}
+	return Fixed64(value), nil
+}
+
+type inputJSON struct {
+	TxID     string `json:"txid"`
+	VOut     uint16 `json:"vout"`
+	Sequence uint32 `json:"sequence"`
+}
+
+func (i Input) MarshalJSON() ([]byte, error) {
+	return json.Marshal(inputJSON{
+		TxID:     toReversedString(i.Previous.TxID),
+		VOut:     i.Previous.Index,
+		Sequence: i.Sequence,
+	})
+}
+
+func (i *Input) UnmarshalJSON(data []byte) error {
+	var obj inputJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	txID, err := fromReversedString(obj.TxID)
+	if err != nil {
+		return errors.New("[Input], invalid txid " + obj.TxID)
+	}
+	i.Previous = OutPoint{TxID: txID, Index: obj.VOut}
+	i.Sequence = obj.Sequence
+	return nil
+}
+
+type outputJSON struct {
+	AssetID     string `json:"assetid"`
+	Value       string `json:"value"`
+	OutputLock  uint32 `json:"outputlock"`
+	Address     string `json:"address"`
+	ProgramHash string `json:"programhash"`
+}
+
+func (o Output) MarshalJSON() ([]byte, error) {
+	programHash, address := fromProgramHash(o.ProgramHash)
+	return json.Marshal(outputJSON{
+		AssetID:     toReversedString(o.AssetID),
+		Value:       o.Value.String(),
+		OutputLock:  o.OutputLock,
+		Address:     address,
+		ProgramHash: programHash,
+	})
+}
+
+func (o *Output) UnmarshalJSON(data []byte) error {
+	var obj outputJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if o.AssetID, err = fromReversedString(obj.AssetID); err != nil {
+		return errors.New("[Output], invalid assetid " + obj.AssetID)
+	}
+	if o.Value, err = parseFixed64(obj.Value); err != nil {
+		return errors.New("[Output], invalid value " + obj.Value)
+	}
+	if o.ProgramHash, err = toProgramHash(obj.ProgramHash, obj.Address); err != nil {
+		return errors.New("[Output], invalid address " + obj.Address)
+	}
+	o.OutputLock = obj.OutputLock
+	return nil
+}
+
+type attributeJSON struct {
+	Usage AttributeUsage `json:"usage"`
+	Data  string         `json:"data"`
+}
+
+func (attr Attribute) MarshalJSON() ([]byte, error) {
+	return json.Marshal(attributeJSON{
+		Usage: attr.Usage,
+		Data:  BytesToHexString(attr.Data),
+	})
+}
+
+func (attr *Attribute) UnmarshalJSON(data []byte) error {
+	var obj attributeJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if attr.Data, err = fromHexString(obj.Data); err != nil {
+		return errors.New("[Attribute], invalid data " + obj.Data)
+	}
+	attr.Usage = obj.Usage
+	return nil
+}
+
+type programJSON struct {
+	Code      string `json:"code"`
+	Parameter string `json:"parameter"`
+}
+
+func (p Program) MarshalJSON() ([]byte, error) {
+	return json.Marshal(programJSON{
+		Code:      BytesToHexString(p.Code),
+		Parameter: BytesToHexString(p.Parameter),
+	})
+}
+
+func (p *Program) UnmarshalJSON(data []byte) error {
+	var obj programJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if p.Code, err = fromHexString(obj.Code); err != nil {
+		return errors.New("[Program], invalid code " + obj.Code)
+	}
+	if p.Parameter, err = fromHexString(obj.Parameter); err != nil {
+		return errors.New("[Program], invalid parameter " + obj.Parameter)
+	}
+	return nil
+}
+
+type transactionJSON struct {
+	TxID           string             `json:"txid,omitempty"`
+	Version        TransactionVersion `json:"version"`
+	TxType         TransactionType    `json:"type"`
+	PayloadVersion byte               `json:"payloadversion"`
+	Payload        json.RawMessage    `json:"payload"`
+	Attributes     []*Attribute       `json:"attributes"`
+	Inputs         []*Input           `json:"vin"`
+	Outputs        []*Output          `json:"vout"`
+	LockTime       uint32             `json:"locktime"`
+	Programs       []*Program         `json:"programs"`
+}
+
+func (tx Transaction) MarshalJSON() ([]byte, error) {
+	if tx.Payload == nil {
+		return nil, errors.New("[Transaction], payload is nil")
+	}
+	payload, err := json.Marshal(tx.Payload)
+	if err != nil {
+		return nil, err
+	}
+	obj := transactionJSON{
+		TxID:           toReversedString(tx.Hash()),
+		Version:        tx.Version,
+		TxType:         tx.TxType,
+		PayloadVersion: tx.PayloadVersion,
+		Payload:        payload,
+		Attributes:     tx.Attributes,
+		Inputs:         tx.Inputs,
+		Outputs:        tx.Outputs,
+		LockTime:       tx.LockTime,
+		Programs:       tx.Programs,
+	}
+	if obj.Attributes == nil {
+		obj.Attributes = []*Attribute{}
+	}
+	if obj.Inputs == nil {
+		obj.Inputs = []*Input{}
+	}
+	if obj.Outputs == nil {
+		obj.Outputs = []*Output{}
+	}
+	if obj.Programs == nil {
+		obj.Programs = []*Program{}
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON decodes the transaction, the txid is ignored as it is
+// computed from the transaction content.
+func (tx *Transaction) UnmarshalJSON(data []byte) error {
+	var obj transactionJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	payload, err := GetPayload(obj.TxType)
+	if err != nil {
+		return err
+	}
+	if len(obj.Payload) > 0 && !bytes.Equal(obj.Payload, []byte("null")) {
+		if err := json.Unmarshal(obj.Payload, payload); err != nil {
+			return err
+		}
+	}
+	*tx = Transaction{
+		Version:        obj.Version,
+		TxType:         obj.TxType,
+		PayloadVersion: obj.PayloadVersion,
+		Payload:        payload,
+		Attributes:     obj.Attributes,
+		Inputs:         obj.Inputs,
+		Outputs:        obj.Outputs,
+		LockTime:       obj.LockTime,
+		Programs:       obj.Programs,
+	}
+	return nil
+}
+
+type headerJSON struct {
+	Hash       string `json:"hash,omitempty"`
+	Version    uint32 `json:"version"`
+	Previous   string `json:"previousblockhash"`
+	MerkleRoot string `json:"merkleroot"`
+	Timestamp  uint32 `json:"time"`
+	Bits       uint32 `json:"bits"`
+	Nonce      uint32 `json:"nonce"`
+	Height     uint32 `json:"height"`
+	AuxPow     string `json:"auxpow"`
+}
+
+func (header *Header) toJSON() (*headerJSON, error) {
+	buf := new(bytes.Buffer)
+	if err := header.AuxPow.Serialize(buf); err != nil {
+		return nil, err
+	}
+	return &headerJSON{
+		Hash:       toReversedString(header.Hash()),
+		Version:    header.Version,
+		Previous:   toReversedString(header.Previous),
+		MerkleRoot: toReversedString(header.MerkleRoot),
+		Timestamp:  header.Timestamp,
+		Bits:       header.Bits,
+		Nonce:      header.Nonce,
+		Height:     header.Height,
+		AuxPow:     BytesToHexString(buf.Bytes()),
+	}, nil
+}
+
+func (header *Header) fromJSON(obj *headerJSON) error {
+	var err error
+	if header.Previous, err = fromReversedString(obj.Previous); err != nil {
+		return errors.New("[Header], invalid previousblockhash " + obj.Previous)
+	}
+	if header.MerkleRoot, err = fromReversedString(obj.MerkleRoot); err != nil {
+		return errors.New("[Header], invalid merkleroot " + obj.MerkleRoot)
+	}
+	auxPow, err := HexStringToBytes(obj.AuxPow)
+	if err != nil {
+		return errors.New("[Header], invalid auxpow")
+	}
+	if err := header.AuxPow.Deserialize(bytes.NewReader(auxPow)); err != nil {
+		return errors.New("[Header], invalid auxpow")
+	}
+	header.Version = obj.Version
+	header.Timestamp = obj.Timestamp
+	header.Bits = obj.Bits
+	header.Nonce = obj.Nonce
+	header.Height = obj.Height
+	return nil
+}
+
+func (header Header) MarshalJSON() ([]byte, error) {
+	obj, err := header.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON decodes the header, the hash is ignored as it is computed
+// from the header content.
+func (header *Header) UnmarshalJSON(data []byte) error {
+	var obj headerJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	return header.fromJSON(&obj)
+}
+
+type blockJSON struct {
+	headerJSON
+	Transactions []*Transaction `json:"tx"`
+}
+
+func (b Block) MarshalJSON() ([]byte, error) {
+	header, err := b.Header.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	obj := blockJSON{headerJSON: *header, Transactions: b.Transactions}
+	if obj.Transactions == nil {
+		obj.Transactions = []*Transaction{}
+	}
+	return json.Marshal(obj)
+}
+
+func (b *Block) UnmarshalJSON(data []byte) error {
+	var obj blockJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	if err := b.Header.fromJSON(&obj.headerJSON); err != nil {
+		return err
+	}
+	b.Transactions = obj.Transactions
+	return nil
+}
+
+type coinBaseJSON struct {
+	CoinbaseData string `json:"coinbasedata"`
+}
+
+func (a *PayloadCoinBase) MarshalJSON() ([]byte, error) {
+	return json.Marshal(coinBaseJSON{CoinbaseData: BytesToHexString(a.CoinbaseData)})
+}
+
+func (a *PayloadCoinBase) UnmarshalJSON(data []byte) error {
+	var obj coinBaseJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if a.CoinbaseData, err = fromHexString(obj.CoinbaseData); err != nil {
+		return errors.New("[PayloadCoinBase], invalid coinbasedata")
+	}
+	return nil
+}
+
+type assetJSON struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Precision   byte            `json:"precision"`
+	AssetType   AssetType       `json:"assettype"`
+	RecordType  AssetRecordType `json:"recordtype"`
+	MaxSupply   string          `json:"maxsupply"`
+}
+
+type registerAssetJSON struct {
+	Asset          assetJSON `json:"asset"`
+	Amount         string    `json:"amount"`
+	Controller     string    `json:"controller"`
+	ControllerHash string    `json:"controllerhash"`
+}
+
+func (a *PayloadRegisterAsset) MarshalJSON() ([]byte, error) {
+	controllerHash, controller := fromProgramHash(a.Controller)
+	return json.Marshal(registerAssetJSON{
+		Asset: assetJSON{
+			Name:        a.Asset.Name,
+			Description: a.Asset.Description,
+			Precision:   a.Asset.Precision,
+			AssetType:   a.Asset.AssetType,
+			RecordType:  a.Asset.RecordType,
+			MaxSupply:   a.Asset.MaxSupply.String(),
+		},
+		Amount:         a.Amount.String(),
+		Controller:     controller,
+		ControllerHash: controllerHash,
+	})
+}
+
+func (a *PayloadRegisterAsset) UnmarshalJSON(data []byte) error {
+	var obj registerAssetJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if a.Asset.MaxSupply, err = parseFixed64(obj.Asset.MaxSupply); err != nil {
+		return errors.New("[PayloadRegisterAsset], invalid maxsupply " + obj.Asset.MaxSupply)
+	}
+	if a.Amount, err = parseFixed64(obj.Amount); err != nil {
+		return errors.New("[PayloadRegisterAsset], invalid amount " + obj.Amount)
+	}
+	if a.Controller, err = toProgramHash(obj.ControllerHash, obj.Controller); err != nil {
+		return errors.New("[PayloadRegisterAsset], invalid controller " + obj.Controller)
+	}
+	a.Asset.Name = obj.Asset.Name
+	a.Asset.Description = obj.Asset.Description
+	a.Asset.Precision = obj.Asset.Precision
+	a.Asset.AssetType = obj.Asset.AssetType
+	a.Asset.RecordType = obj.Asset.RecordType
+	return nil
+}
+
+func (a *PayloadTransferAsset) MarshalJSON() ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+func (a *PayloadTransferAsset) UnmarshalJSON(data []byte) error {
+	var obj struct{}
+	return json.Unmarshal(data, &obj)
+}
+
+type recordJSON struct {
+	RecordType string `json:"recordtype"`
+	RecordData string `json:"recorddata"`
+}
+
+func (a *PayloadRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(recordJSON{
+		RecordType: a.RecordType,
+		RecordData: BytesToHexString(a.RecordData),
+	})
+}
+
+func (a *PayloadRecord) UnmarshalJSON(data []byte) error {
+	var obj recordJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if a.RecordData, err = fromHexString(obj.RecordData); err != nil {
+		return errors.New("[PayloadRecord], invalid recorddata")
+	}
+	a.RecordType = obj.RecordType
+	return nil
+}
+
+type sideChainPowJSON struct {
+	SideBlockHash   string `json:"sideblockhash"`
+	SideGenesisHash string `json:"sidegenesishash"`
+	BlockHeight     uint32 `json:"blockheight"`
+	SignedData      string `json:"signeddata"`
+}
+
+func (a *PayloadSideChainPow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sideChainPowJSON{
+		SideBlockHash:   toReversedString(a.SideBlockHash),
+		SideGenesisHash: toReversedString(a.SideGenesisHash),
+		BlockHeight:     a.BlockHeight,
+		SignedData:      BytesToHexString(a.SignedData),
+	})
+}
+
+func (a *PayloadSideChainPow) UnmarshalJSON(data []byte) error {
+	var obj sideChainPowJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if a.SideBlockHash, err = fromReversedString(obj.SideBlockHash); err != nil {
+		return errors.New("[PayloadSideChainPow], invalid sideblockhash " + obj.SideBlockHash)
+	}
+	if a.SideGenesisHash, err = fromReversedString(obj.SideGenesisHash); err != nil {
+		return errors.New("[PayloadSideChainPow], invalid sidegenesishash " + obj.SideGenesisHash)
+	}
+	if a.SignedData, err = fromHexString(obj.SignedData); err != nil {
+		return errors.New("[PayloadSideChainPow], invalid signeddata")
+	}
+	a.BlockHeight = obj.BlockHeight
+	return nil
+}
+
+type withdrawFromSideChainJSON struct {
+	BlockHeight                uint32   `json:"blockheight"`
+	GenesisBlockAddress        string   `json:"genesisblockaddress"`
+	SideChainTransactionHashes []string `json:"sidechaintransactionhashes"`
+}
+
+func (t *PayloadWithdrawFromSideChain) MarshalJSON() ([]byte, error) {
+	obj := withdrawFromSideChainJSON{
+		BlockHeight:                t.BlockHeight,
+		GenesisBlockAddress:        t.GenesisBlockAddress,
+		SideChainTransactionHashes: make([]string, 0, len(t.SideChainTransactionHashes)),
+	}
+	for _, hash := range t.SideChainTransactionHashes {
+		obj.SideChainTransactionHashes = append(obj.SideChainTransactionHashes, toReversedString(hash))
+	}
+	return json.Marshal(obj)
+}
+
+func (t *PayloadWithdrawFromSideChain) UnmarshalJSON(data []byte) error {
+	var obj withdrawFromSideChainJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	t.BlockHeight = obj.BlockHeight
+	t.GenesisBlockAddress = obj.GenesisBlockAddress
+	t.SideChainTransactionHashes = nil
+	for _, str := range obj.SideChainTransactionHashes {
+		hash, err := fromReversedString(str)
+		if err != nil {
+			return errors.New("[PayloadWithdrawFromSideChain], invalid side chain transaction hash " + str)
+		}
+		t.SideChainTransactionHashes = append(t.SideChainTransactionHashes, hash)
+	}
+	return nil
+}
+
+type transferCrossChainAssetJSON struct {
+	CrossChainAddresses []string `json:"crosschainaddresses"`
+	OutputIndexes       []uint64 `json:"outputindexes"`
+	CrossChainAmounts   []string `json:"crosschainamounts"`
+}
+
+func (a *PayloadTransferCrossChainAsset) MarshalJSON() ([]byte, error) {
+	obj := transferCrossChainAssetJSON{
+		CrossChainAddresses: a.CrossChainAddresses,
+		OutputIndexes:       a.OutputIndexes,
+		CrossChainAmounts:   make([]string, 0, len(a.CrossChainAmounts)),
+	}
+	if obj.CrossChainAddresses == nil {
+		obj.CrossChainAddresses = []string{}
+	}
+	if obj.OutputIndexes == nil {
+		obj.OutputIndexes = []uint64{}
+	}
+	for _, amount := range a.CrossChainAmounts {
+		obj.CrossChainAmounts = append(obj.CrossChainAmounts, amount.String())
+	}
+	return json.Marshal(obj)
+}
+
+func (a *PayloadTransferCrossChainAsset) UnmarshalJSON(data []byte) error {
+	var obj transferCrossChainAssetJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	a.CrossChainAddresses = obj.CrossChainAddresses
+	a.OutputIndexes = obj.OutputIndexes
+	a.CrossChainAmounts = nil
+	for _, str := range obj.CrossChainAmounts {
+		amount, err := parseFixed64(str)
+		if err != nil {
+			return errors.New("[PayloadTransferCrossChainAsset], invalid cross chain amount " + str)
+		}
+		a.CrossChainAmounts = append(a.CrossChainAmounts, amount)
+	}
+	return nil
+}
+
+type assetSupplyJSON struct {
+	AssetID string `json:"assetid"`
+	Amount  string `json:"amount"`
+}
+
+func (a *PayloadMintAsset) MarshalJSON() ([]byte, error) {
+	return json.Marshal(assetSupplyJSON{
+		AssetID: toReversedString(a.AssetID),
+		Amount:  a.Amount.String(),
+	})
+}
+
+func (a *PayloadMintAsset) UnmarshalJSON(data []byte) error {
+	var obj assetSupplyJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if a.AssetID, err = fromReversedString(obj.AssetID); err != nil {
+		return errors.New("[PayloadMintAsset], invalid assetid " + obj.AssetID)
+	}
+	if a.Amount, err = parseFixed64(obj.Amount); err != nil {
+		return errors.New("[PayloadMintAsset], invalid amount " + obj.Amount)
+	}
+	return nil
+}
+
+func (a *PayloadBurnAsset) MarshalJSON() ([]byte, error) {
+	return json.Marshal(assetSupplyJSON{
+		AssetID: toReversedString(a.AssetID),
+		Amount:  a.Amount.String(),
+	})
+}
+
+func (a *PayloadBurnAsset) UnmarshalJSON(data []byte) error {
+	var obj assetSupplyJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if a.AssetID, err = fromReversedString(obj.AssetID); err != nil {
+		return errors.New("[PayloadBurnAsset], invalid assetid " + obj.AssetID)
+	}
+	if a.Amount, err = parseFixed64(obj.Amount); err != nil {
+		return errors.New("[PayloadBurnAsset], invalid amount " + obj.Amount)
+	}
+	return nil
+}
+
+type registerProducerJSON struct {
+	PublicKey string `json:"publickey"`
+	NickName  string `json:"nickname"`
+	Url       string `json:"url"`
+}
+
+func (a *PayloadRegisterProducer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(registerProducerJSON{
+		PublicKey: BytesToHexString(a.PublicKey),
+		NickName:  a.NickName,
+		Url:       a.Url,
+	})
+}
+
+func (a *PayloadRegisterProducer) UnmarshalJSON(data []byte) error {
+	var obj registerProducerJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if a.PublicKey, err = fromHexString(obj.PublicKey); err != nil {
+		return errors.New("[PayloadRegisterProducer], invalid publickey " + obj.PublicKey)
+	}
+	a.NickName = obj.NickName
+	a.Url = obj.Url
+	return nil
+}
+
+type cancelProducerJSON struct {
+	PublicKey string `json:"publickey"`
+}
+
+func (a *PayloadCancelProducer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cancelProducerJSON{PublicKey: BytesToHexString(a.PublicKey)})
+}
+
+func (a *PayloadCancelProducer) UnmarshalJSON(data []byte) error {
+	var obj cancelProducerJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if a.PublicKey, err = fromHexString(obj.PublicKey); err != nil {
+		return errors.New("[PayloadCancelProducer], invalid publickey " + obj.PublicKey)
+	}
+	return nil
+}
+
+type voteProducerJSON struct {
+	Candidates []string `json:"candidates"`
+}
+
+func (a *PayloadVoteProducer) MarshalJSON() ([]byte, error) {
+	obj := voteProducerJSON{Candidates: make([]string, 0, len(a.Candidates))}
+	for _, candidate := range a.Candidates {
+		obj.Candidates = append(obj.Candidates, BytesToHexString(candidate))
+	}
+	return json.Marshal(obj)
+}
+
+func (a *PayloadVoteProducer) UnmarshalJSON(data []byte) error {
+	var obj voteProducerJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	a.Candidates = make([][]byte, 0, len(obj.Candidates))
+	for _, str := range obj.Candidates {
+		candidate, err := fromHexString(str)
+		if err != nil {
+			return errors.New("[PayloadVoteProducer], invalid candidate " + str)
+		}
+		a.Candidates = append(a.Candidates, candidate)
+	}
+	return nil
+}
+
+type updateArbitratorsJSON struct {
+	Arbitrators []string `json:"arbitrators"`
+}
+
+func (a *PayloadUpdateArbitrators) MarshalJSON() ([]byte, error) {
+	obj := updateArbitratorsJSON{Arbitrators: make([]string, 0, len(a.Arbitrators))}
+	for _, arbitrator := range a.Arbitrators {
+		obj.Arbitrators = append(obj.Arbitrators, BytesToHexString(arbitrator))
+	}
+	return json.Marshal(obj)
+}
+
+func (a *PayloadUpdateArbitrators) UnmarshalJSON(data []byte) error {
+	var obj updateArbitratorsJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	a.Arbitrators = make([][]byte, 0, len(obj.Arbitrators))
+	for _, str := range obj.Arbitrators {
+		arbitrator, err := fromHexString(str)
+		if err != nil {
+			return errors.New("[PayloadUpdateArbitrators], invalid arbitrator " + str)
+		}
+		a.Arbitrators = append(a.Arbitrators, arbitrator)
+	}
+	return nil
+}
+
+type registerSideChainJSON struct {
+	GenesisHash             string `json:"genesishash"`
+	GenesisAddress          string `json:"genesisaddress"`
+	Name                    string `json:"name"`
+	MinArbitratorSignatures uint32 `json:"minarbitratorsignatures"`
+}
+
+func (a *PayloadRegisterSideChain) MarshalJSON() ([]byte, error) {
+	return json.Marshal(registerSideChainJSON{
+		GenesisHash:             toReversedString(a.GenesisHash),
+		GenesisAddress:          a.GenesisAddress,
+		Name:                    a.Name,
+		MinArbitratorSignatures: a.MinArbitratorSignatures,
+	})
+}
+
+func (a *PayloadRegisterSideChain) UnmarshalJSON(data []byte) error {
+	var obj registerSideChainJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var err error
+	if a.GenesisHash, err = fromReversedString(obj.GenesisHash); err != nil {
+		return errors.New("[PayloadRegisterSideChain], invalid genesishash " + obj.GenesisHash)
+	}
+	a.GenesisAddress = obj.GenesisAddress
+	a.Name = obj.Name
+	a.MinArbitratorSignatures = obj.MinArbitratorSignatures
+	return nil
+}
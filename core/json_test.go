@@ -0,0 +1,296 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+func randomBytes(r *rand.Rand, max int) []byte {
+	buf := make([]byte, r.Intn(max+1))
+	r.Read(buf)
+	return buf
+}
+
+func randomString(r *rand.Rand, max int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	buf := make([]byte, r.Intn(max+1))
+	for i := range buf {
+		buf[i] = letters[r.Intn(len(letters))]
+	}
+	return string(buf)
+}
+
+func randomHash(r *rand.Rand) common.Uint256 {
+	var hash common.Uint256
+	r.Read(hash[:])
+	return hash
+}
+
+func randomProgramHash(r *rand.Rand) common.Uint168 {
+	var programHash common.Uint168
+	r.Read(programHash[:])
+	switch r.Intn(3) {
+	case 0:
+		programHash[0] = common.PrefixStandard
+	case 1:
+		programHash[0] = 0
+	}
+	return programHash
+}
+
+func randomAmount(r *rand.Rand) common.Fixed64 {
+	return common.Fixed64(r.Int63() - r.Int63n(1e8))
+}
+
+func randomPayload(r *rand.Rand, txType TransactionType) Payload {
+	switch txType {
+	case CoinBase:
+		return &PayloadCoinBase{CoinbaseData: randomBytes(r, 64)}
+	case RegisterAsset:
+		return &PayloadRegisterAsset{
+			Asset: Asset{
+				Name:        randomString(r, 16),
+				Description: randomString(r, 64),
+				Precision:   byte(r.Intn(MaxPrecision + 1)),
+				AssetType:   AssetType(r.Intn(2)),
+				RecordType:  AssetRecordType(r.Intn(2)),
+				MaxSupply:   randomAmount(r),
+			},
+			Amount:     randomAmount(r),
+			Controller: randomProgramHash(r),
+		}
+	case TransferAsset:
+		return new(PayloadTransferAsset)
+	case Record:
+		return &PayloadRecord{RecordType: randomString(r, 16), RecordData: randomBytes(r, 64)}
+	case SideChainPow:
+		return &PayloadSideChainPow{
+			SideBlockHash:   randomHash(r),
+			SideGenesisHash: randomHash(r),
+			BlockHeight:     r.Uint32(),
+			SignedData:      randomBytes(r, 64),
+		}
+	case WithdrawFromSideChain:
+		payload := &PayloadWithdrawFromSideChain{
+			BlockHeight:         r.Uint32(),
+			GenesisBlockAddress: randomString(r, 34),
+		}
+		for i := r.Intn(4); i > 0; i-- {
+			payload.SideChainTransactionHashes = append(payload.SideChainTransactionHashes, randomHash(r))
+		}
+		return payload
+	case TransferCrossChainAsset:
+		payload := new(PayloadTransferCrossChainAsset)
+		for i := r.Intn(4); i > 0; i-- {
+			payload.CrossChainAddresses = append(payload.CrossChainAddresses, randomString(r, 34))
+			payload.OutputIndexes = append(payload.OutputIndexes, r.Uint64())
+			payload.CrossChainAmounts = append(payload.CrossChainAmounts, randomAmount(r))
+		}
+		return payload
+	case MintAsset:
+		return &PayloadMintAsset{AssetID: randomHash(r), Amount: randomAmount(r)}
+	case BurnAsset:
+		return &PayloadBurnAsset{AssetID: randomHash(r), Amount: randomAmount(r)}
+	case RegisterProducer:
+		return &PayloadRegisterProducer{
+			PublicKey: randomBytes(r, 33),
+			NickName:  randomString(r, 16),
+			Url:       randomString(r, 64),
+		}
+	case CancelProducer:
+		return &PayloadCancelProducer{PublicKey: randomBytes(r, 33)}
+	case VoteProducer:
+		payload := new(PayloadVoteProducer)
+		for i := r.Intn(4); i > 0; i-- {
+			payload.Candidates = append(payload.Candidates, randomBytes(r, 33))
+		}
+		return payload
+	case UpdateArbitrators:
+		payload := new(PayloadUpdateArbitrators)
+		for i := r.Intn(4); i > 0; i-- {
+			payload.Arbitrators = append(payload.Arbitrators, randomBytes(r, 33))
+		}
+		return payload
+	case RegisterSideChain:
+		return &PayloadRegisterSideChain{
+			GenesisHash:             randomHash(r),
+			GenesisAddress:          randomString(r, 34),
+			Name:                    randomString(r, 16),
+			MinArbitratorSignatures: r.Uint32(),
+		}
+	}
+	return nil
+}
+
+var randomTxTypes = []TransactionType{CoinBase, RegisterAsset, TransferAsset, Record, SideChainPow,
+	WithdrawFromSideChain, TransferCrossChainAsset, MintAsset, BurnAsset, RegisterProducer, CancelProducer,
+	VoteProducer, UpdateArbitrators, RegisterSideChain}
+
+var randomUsages = []AttributeUsage{Nonce, Script, Memo, Description, DescriptionUrl, Confirmations}
+
+func randomTransaction(r *rand.Rand) *Transaction {
+	txType := randomTxTypes[r.Intn(len(randomTxTypes))]
+	txn := &Transaction{
+		Version:        []TransactionVersion{TxVersionDefault, TxVersion01}[r.Intn(2)],
+		TxType:         txType,
+		PayloadVersion: byte(r.Intn(2)),
+		Payload:        randomPayload(r, txType),
+		LockTime:       r.Uint32(),
+	}
+	for i := r.Intn(3); i > 0; i-- {
+		attr := NewAttribute(randomUsages[r.Intn(len(randomUsages))], randomBytes(r, 32))
+		txn.Attributes = append(txn.Attributes, &attr)
+	}
+	for i := r.Intn(4); i > 0; i-- {
+		txn.Inputs = append(txn.Inputs, &Input{
+			Previous: *NewOutPoint(randomHash(r), uint16(r.Intn(65536))),
+			Sequence: r.Uint32(),
+		})
+	}
+	for i := r.Intn(4); i > 0; i-- {
+		txn.Outputs = append(txn.Outputs, &Output{
+			AssetID:     randomHash(r),
+			Value:       randomAmount(r),
+			OutputLock:  r.Uint32(),
+			ProgramHash: randomProgramHash(r),
+		})
+	}
+	for i := r.Intn(3); i > 0; i-- {
+		txn.Programs = append(txn.Programs, &Program{Code: randomBytes(r, 64), Parameter: randomBytes(r, 128)})
+	}
+	return txn
+}
+
+func serialize(t *testing.T, s interface {
+	Serialize(w io.Writer) error
+}) []byte {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, s.Serialize(buf))
+	return buf.Bytes()
+}
+
+func TestTransaction_JSONRoundTrip(t *testing.T) {
+	f := func(seed int64) bool {
+		txn := randomTransaction(rand.New(rand.NewSource(seed)))
+		data, err := json.Marshal(txn)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		var decoded Transaction
+		if !assert.NoError(t, json.Unmarshal(data, &decoded)) {
+			return false
+		}
+		again, err := json.Marshal(&decoded)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		return assert.Equal(t, serialize(t, txn), serialize(t, &decoded)) &&
+			assert.Equal(t, string(data), string(again)) &&
+			assert.Equal(t, txn.Hash(), decoded.Hash())
+	}
+	assert.NoError(t, quick.Check(f, &quick.Config{MaxCount: 500}))
+}
+
+func TestBlock_JSONRoundTrip(t *testing.T) {
+	f := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		block := &Block{
+			Header: Header{
+				Version:    r.Uint32(),
+				Previous:   randomHash(r),
+				MerkleRoot: randomHash(r),
+				Timestamp:  r.Uint32(),
+				Bits:       r.Uint32(),
+				Nonce:      r.Uint32(),
+				Height:     r.Uint32(),
+			},
+		}
+		for i := r.Intn(4); i > 0; i-- {
+			block.Transactions = append(block.Transactions, randomTransaction(r))
+		}
+
+		data, err := json.Marshal(block)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		var decoded Block
+		if !assert.NoError(t, json.Unmarshal(data, &decoded)) {
+			return false
+		}
+		if !assert.Equal(t, serialize(t, block), serialize(t, &decoded)) {
+			return false
+		}
+
+		// the header alone
+		data, err = json.Marshal(&block.Header)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		var header Header
+		if !assert.NoError(t, json.Unmarshal(data, &header)) {
+			return false
+		}
+		return assert.Equal(t, block.Hash(), header.Hash()) &&
+			assert.Equal(t, serialize(t, &block.Header), serialize(t, &header))
+	}
+	assert.NoError(t, quick.Check(f, &quick.Config{MaxCount: 100}))
+}
+
+func TestTransaction_UnmarshalJSON(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	programHash := randomProgramHash(r)
+	programHash[0] = common.PrefixStandard
+	address, err := programHash.ToAddress()
+	assert.NoError(t, err)
+	txID := randomHash(r)
+
+	// the program hash is decoded from the address, and the hashes are in
+	// reversed order
+	data := `{"type":2,"payloadversion":0,"payload":{},
+		"attributes":[{"usage":0,"data":"0102"}],
+		"vin":[{"txid":"` + toReversedString(txID) + `","vout":1,"sequence":4294967295}],
+		"vout":[{"assetid":"` + toReversedString(randomHash(r)) + `","value":"1.5","outputlock":0,"address":"` + address + `"}],
+		"locktime":0,"programs":[]}`
+	var txn Transaction
+	assert.NoError(t, json.Unmarshal([]byte(data), &txn))
+	assert.Equal(t, TransferAsset, txn.TxType)
+	assert.Equal(t, txID, txn.Inputs[0].Previous.TxID)
+	assert.Equal(t, uint16(1), txn.Inputs[0].Previous.Index)
+	assert.Equal(t, common.Fixed64(150000000), txn.Outputs[0].Value)
+	assert.Equal(t, programHash, txn.Outputs[0].ProgramHash)
+	assert.Equal(t, []byte{1, 2}, txn.Attributes[0].Data)
+
+	// invalid transaction type
+	assert.Error(t, json.Unmarshal([]byte(`{"type":255,"payload":{}}`), &txn))
+}
+
+func TestParseFixed64(t *testing.T) {
+	valid := map[string]common.Fixed64{
+		"0":                   0,
+		"1":                   100000000,
+		"1.5":                 150000000,
+		"0.00000001":          1,
+		"-2.10000000":         -210000000,
+		"92233720368.5477580": 9223372036854775800,
+	}
+	for s, expected := range valid {
+		value, err := parseFixed64(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, value, s)
+		parsed, err := parseFixed64(value.String())
+		assert.NoError(t, err, s)
+		assert.Equal(t, value, parsed, s)
+	}
+
+	for _, s := range []string{"", "-", ".5", "1.", "1.000000001", "+1", "1.+5", "abc", "92233720369"} {
+		_, err := parseFixed64(s)
+		assert.Error(t, err, s)
+	}
+}
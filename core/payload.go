@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"io"
 )
 
@@ -17,25 +18,58 @@ type Payload interface {
 	Deserialize(r io.Reader, version byte) error
 }
 
+// payloadType is the registered payload of a transaction type.
+type payloadType struct {
+	name       string
+	newPayload func() Payload
+}
+
+// payloadTypes are the transaction types can be deserialized.
+var payloadTypes = map[TransactionType]payloadType{
+	CoinBase:                {"CoinBase", func() Payload { return new(PayloadCoinBase) }},
+	RegisterAsset:           {"RegisterAsset", func() Payload { return new(PayloadRegisterAsset) }},
+	TransferAsset:           {"TransferAsset", func() Payload { return new(PayloadTransferAsset) }},
+	Record:                  {"Record", func() Payload { return new(PayloadRecord) }},
+	SideChainPow:            {"SideChainPow", func() Payload { return new(PayloadSideChainPow) }},
+	WithdrawFromSideChain:   {"WithdrawFromSideChain", func() Payload { return new(PayloadWithdrawFromSideChain) }},
+	TransferCrossChainAsset: {"TransferCrossChainAsset", func() Payload { return new(PayloadTransferCrossChainAsset) }},
+	MintAsset:               {"MintAsset", func() Payload { return new(PayloadMintAsset) }},
+	BurnAsset:               {"BurnAsset", func() Payload { return new(PayloadBurnAsset) }},
+	RegisterProducer:        {"RegisterProducer", func() Payload { return new(PayloadRegisterProducer) }},
+	CancelProducer:          {"CancelProducer", func() Payload { return new(PayloadCancelProducer) }},
+	VoteProducer:            {"VoteProducer", func() Payload { return new(PayloadVoteProducer) }},
+	UpdateArbitrators:       {"UpdateArbitrators", func() Payload { return new(PayloadUpdateArbitrators) }},
+	RegisterSideChain:       {"RegisterSideChain", func() Payload { return new(PayloadRegisterSideChain) }},
+}
+
+// RegisterPayload registers the name and the payload factory of a transaction
+// type, so transactions of the type can be deserialized. It is meant to be
+// called by init functions, and panics if the type is already registered or
+// is the reserved TxVersionMarker.
+func RegisterPayload(txType TransactionType, name string, newPayload func() Payload) {
+	if newPayload == nil {
+		panic("core: RegisterPayload factory is nil")
+	}
+	if txType == TxVersionMarker {
+		panic("core: RegisterPayload called with the reserved transaction version marker")
+	}
+	if _, ok := payloadTypes[txType]; ok {
+		panic(fmt.Sprintf("core: RegisterPayload called twice for transaction type %d", txType))
+	}
+	payloadTypes[txType] = payloadType{name: name, newPayload: newPayload}
+}
+
+// IsRegisteredTxType checks if the payload of the transaction type is
+// registered.
+func IsRegisteredTxType(txType TransactionType) bool {
+	_, ok := payloadTypes[txType]
+	return ok
+}
+
 func GetPayload(txType TransactionType) (Payload, error) {
-	var p Payload
-	switch txType {
-	case CoinBase:
-		p = new(PayloadCoinBase)
-	case RegisterAsset:
-		p = new(PayloadRegisterAsset)
-	case TransferAsset:
-		p = new(PayloadTransferAsset)
-	case Record:
-		p = new(PayloadRecord)
-	case SideChainPow:
-		p = new(PayloadSideChainPow)
-	case WithdrawFromSideChain:
-		p = new(PayloadWithdrawFromSideChain)
-	case TransferCrossChainAsset:
-		p = new(PayloadTransferCrossChainAsset)
-	default:
+	t, ok := payloadTypes[txType]
+	if !ok {
 		return nil, errors.New("[Transaction], invalid transaction type.")
 	}
-	return p, nil
+	return t.newPayload(), nil
 }
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterPayload(t *testing.T) {
+	const testTxType TransactionType = 0xfe
+	const testUsage AttributeUsage = 0xfe
+
+	// 1. Unregistered types are rejected
+	_, err := GetPayload(testTxType)
+	assert.Error(t, err)
+	assert.False(t, IsRegisteredTxType(testTxType))
+	assert.Equal(t, "Unknown", testTxType.Name())
+	assert.False(t, IsValidAttributeType(testUsage))
+
+	// 2. Register the payload and the attribute
+	RegisterPayload(testTxType, "Test", func() Payload { return new(PayloadRecord) })
+	RegisterAttribute(testUsage, "Test")
+	defer unregisterPayload(testTxType)
+	defer unregisterAttribute(testUsage)
+	assert.True(t, IsRegisteredTxType(testTxType))
+	assert.Equal(t, "Test", testTxType.Name())
+	assert.Equal(t, "Test", testUsage.Name())
+
+	// 3. Transactions of the registered type can be deserialized
+	attr := NewAttribute(testUsage, []byte{1, 2, 3})
+	txn := &Transaction{
+		TxType:     testTxType,
+		Payload:    &PayloadRecord{RecordType: "test", RecordData: []byte{1}},
+		Attributes: []*Attribute{&attr},
+	}
+	buf := new(bytes.Buffer)
+	assert.NoError(t, txn.Serialize(buf))
+	var decoded Transaction
+	assert.NoError(t, decoded.Deserialize(buf))
+	assert.Equal(t, txn.Payload, decoded.Payload)
+	assert.Equal(t, testUsage, decoded.Attributes[0].Usage)
+
+	// 4. Registering a type twice panics
+	assert.Panics(t, func() {
+		RegisterPayload(testTxType, "Test", func() Payload { return new(PayloadRecord) })
+	})
+	assert.Panics(t, func() { RegisterAttribute(testUsage, "Test") })
+	assert.Panics(t, func() {
+		RegisterPayload(CoinBase, "CoinBase", func() Payload { return new(PayloadCoinBase) })
+	})
+}
+
+// unregisterPayload removes the payload registered by a test, so the test can
+// be run again.
+func unregisterPayload(txType TransactionType) {
+	delete(payloadTypes, txType)
+}
+
+// unregisterAttribute removes the attribute usage registered by a test.
+func unregisterAttribute(usage AttributeUsage) {
+	delete(attributeUsages, usage)
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+const BurnAssetPayloadVersion byte = 0x00
+
+type PayloadBurnAsset struct {
+	AssetID common.Uint256
+	Amount  common.Fixed64
+}
+
+func (a *PayloadBurnAsset) Data(version byte) []byte {
+	buf := new(bytes.Buffer)
+	if err := a.Serialize(buf, version); err != nil {
+		return []byte{0}
+	}
+
+	return buf.Bytes()
+}
+
+func (a *PayloadBurnAsset) Serialize(w io.Writer, version byte) error {
+	if err := a.AssetID.Serialize(w); err != nil {
+		return errors.New("[PayloadBurnAsset], AssetID serialize failed.")
+	}
+	if err := a.Amount.Serialize(w); err != nil {
+		return errors.New("[PayloadBurnAsset], Amount serialize failed.")
+	}
+	return nil
+}
+
+func (a *PayloadBurnAsset) Deserialize(r io.Reader, version byte) error {
+	if err := a.AssetID.Deserialize(r); err != nil {
+		return errors.New("[PayloadBurnAsset], AssetID deserialize failed.")
+	}
+	if err := a.Amount.Deserialize(r); err != nil {
+		return errors.New("[PayloadBurnAsset], Amount deserialize failed.")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+const CancelProducerPayloadVersion byte = 0x00
+
+// PayloadCancelProducer cancels a block producer, the transaction must be
+// signed by the producer and the deposit is unlocked once it is confirmed.
+type PayloadCancelProducer struct {
+	PublicKey []byte
+}
+
+func (a *PayloadCancelProducer) Data(version byte) []byte {
+	buf := new(bytes.Buffer)
+	if err := a.Serialize(buf, version); err != nil {
+		return []byte{0}
+	}
+	return buf.Bytes()
+}
+
+func (a *PayloadCancelProducer) Serialize(w io.Writer, version byte) error {
+	if err := common.WriteVarBytes(w, a.PublicKey); err != nil {
+		return errors.New("[PayloadCancelProducer], PublicKey serialize failed.")
+	}
+	return nil
+}
+
+func (a *PayloadCancelProducer) Deserialize(r io.Reader, version byte) error {
+	var err error
+	if a.PublicKey, err = common.ReadVarBytes(r); err != nil {
+		return errors.New("[PayloadCancelProducer], PublicKey deserialize failed.")
+	}
+	return nil
+}
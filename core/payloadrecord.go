@@ -1,6 +1,7 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"io"
 
@@ -15,8 +16,11 @@ type PayloadRecord struct {
 }
 
 func (a *PayloadRecord) Data(version byte) []byte {
-	//TODO: implement RegisterRecord.Data()
-	return []byte{0}
+	buf := new(bytes.Buffer)
+	if err := a.Serialize(buf, version); err != nil {
+		return []byte{0}
+	}
+	return buf.Bytes()
 }
 
 // Serialize is the implement of SignableData interface.
@@ -1,12 +1,15 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"io"
 
 	. "github.com/wuyazero/Elastos.ELA.Utility/common"
 )
 
+const RegisterAssetPayloadVersion byte = 0x01
+
 type PayloadRegisterAsset struct {
 	Asset      Asset
 	Amount     Fixed64
@@ -14,9 +17,18 @@ type PayloadRegisterAsset struct {
 }
 
 func (a *PayloadRegisterAsset) Data(version byte) []byte {
-	//TODO: implement RegisterAsset.Data()
-	return []byte{0}
+	buf := new(bytes.Buffer)
+	if err := a.Serialize(buf, version); err != nil {
+		return []byte{0}
+	}
+	return buf.Bytes()
+}
 
+// AssetID returns the ID of the asset registered by this payload. The ID is
+// derived from the payload instead of the transaction hash, so the outputs of
+// the registering transaction are able to refer to the new asset.
+func (a *PayloadRegisterAsset) AssetID() Uint256 {
+	return Uint256(Sha256D(a.Data(RegisterAssetPayloadVersion)))
 }
 
 func (a *PayloadRegisterAsset) Serialize(w io.Writer, version byte) error {
@@ -32,6 +44,12 @@ func (a *PayloadRegisterAsset) Serialize(w io.Writer, version byte) error {
 	if err != nil {
 		return errors.New("[RegisterAsset], Controller Serialize failed.")
 	}
+	if version >= RegisterAssetPayloadVersion {
+		err = a.Asset.MaxSupply.Serialize(w)
+		if err != nil {
+			return errors.New("[RegisterAsset], MaxSupply Serialize failed.")
+		}
+	}
 	return nil
 }
 
@@ -53,5 +71,13 @@ func (a *PayloadRegisterAsset) Deserialize(r io.Reader, version byte) error {
 	if err != nil {
 		return errors.New("[RegisterAsset], Ammount Deserialize failed.")
 	}
+
+	//MaxSupply
+	if version >= RegisterAssetPayloadVersion {
+		err = a.Asset.MaxSupply.Deserialize(r)
+		if err != nil {
+			return errors.New("[RegisterAsset], MaxSupply Deserialize failed.")
+		}
+	}
 	return nil
 }
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+const RegisterProducerPayloadVersion byte = 0x00
+
+// PayloadRegisterProducer registers a block producer, the first output of the
+// transaction is the deposit of the producer and the transaction must be
+// signed by the producer.
+type PayloadRegisterProducer struct {
+	PublicKey []byte
+	NickName  string
+	Url       string
+}
+
+func (a *PayloadRegisterProducer) Data(version byte) []byte {
+	buf := new(bytes.Buffer)
+	if err := a.Serialize(buf, version); err != nil {
+		return []byte{0}
+	}
+	return buf.Bytes()
+}
+
+func (a *PayloadRegisterProducer) Serialize(w io.Writer, version byte) error {
+	if err := common.WriteVarBytes(w, a.PublicKey); err != nil {
+		return errors.New("[PayloadRegisterProducer], PublicKey serialize failed.")
+	}
+	if err := common.WriteVarString(w, a.NickName); err != nil {
+		return errors.New("[PayloadRegisterProducer], NickName serialize failed.")
+	}
+	if err := common.WriteVarString(w, a.Url); err != nil {
+		return errors.New("[PayloadRegisterProducer], Url serialize failed.")
+	}
+	return nil
+}
+
+func (a *PayloadRegisterProducer) Deserialize(r io.Reader, version byte) error {
+	var err error
+	if a.PublicKey, err = common.ReadVarBytes(r); err != nil {
+		return errors.New("[PayloadRegisterProducer], PublicKey deserialize failed.")
+	}
+	if a.NickName, err = common.ReadVarString(r); err != nil {
+		return errors.New("[PayloadRegisterProducer], NickName deserialize failed.")
+	}
+	if a.Url, err = common.ReadVarString(r); err != nil {
+		return errors.New("[PayloadRegisterProducer], Url deserialize failed.")
+	}
+	return nil
+}
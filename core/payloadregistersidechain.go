@@ -0,0 +1,64 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+const RegisterSideChainPayloadVersion byte = 0x00
+
+// PayloadRegisterSideChain registers a side chain, so assets can be
+// transferred to and withdrawn from the genesis address of the side chain. It
+// must be signed by the majority of the current arbitrators.
+type PayloadRegisterSideChain struct {
+	GenesisHash    common.Uint256
+	GenesisAddress string
+	Name           string
+	// MinArbitratorSignatures is the least count of the arbitrator signatures
+	// on a withdrawal from the side chain.
+	MinArbitratorSignatures uint32
+}
+
+func (a *PayloadRegisterSideChain) Data(version byte) []byte {
+	buf := new(bytes.Buffer)
+	if err := a.Serialize(buf, version); err != nil {
+		return []byte{0}
+	}
+	return buf.Bytes()
+}
+
+func (a *PayloadRegisterSideChain) Serialize(w io.Writer, version byte) error {
+	if err := a.GenesisHash.Serialize(w); err != nil {
+		return errors.New("[PayloadRegisterSideChain], GenesisHash serialize failed.")
+	}
+	if err := common.WriteVarString(w, a.GenesisAddress); err != nil {
+		return errors.New("[PayloadRegisterSideChain], GenesisAddress serialize failed.")
+	}
+	if err := common.WriteVarString(w, a.Name); err != nil {
+		return errors.New("[PayloadRegisterSideChain], Name serialize failed.")
+	}
+	if err := common.WriteUint32(w, a.MinArbitratorSignatures); err != nil {
+		return errors.New("[PayloadRegisterSideChain], MinArbitratorSignatures serialize failed.")
+	}
+	return nil
+}
+
+func (a *PayloadRegisterSideChain) Deserialize(r io.Reader, version byte) error {
+	var err error
+	if err = a.GenesisHash.Deserialize(r); err != nil {
+		return errors.New("[PayloadRegisterSideChain], GenesisHash deserialize failed.")
+	}
+	if a.GenesisAddress, err = common.ReadVarString(r); err != nil {
+		return errors.New("[PayloadRegisterSideChain], GenesisAddress deserialize failed.")
+	}
+	if a.Name, err = common.ReadVarString(r); err != nil {
+		return errors.New("[PayloadRegisterSideChain], Name deserialize failed.")
+	}
+	if a.MinArbitratorSignatures, err = common.ReadUint32(r); err != nil {
+		return errors.New("[PayloadRegisterSideChain], MinArbitratorSignatures deserialize failed.")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+const UpdateArbitratorsPayloadVersion byte = 0x00
+
+// PayloadUpdateArbitrators replaces the arbitrators with the public keys from
+// the block the transaction is included in, it must be signed by the majority
+// of the current arbitrators.
+type PayloadUpdateArbitrators struct {
+	Arbitrators [][]byte
+}
+
+func (a *PayloadUpdateArbitrators) Data(version byte) []byte {
+	buf := new(bytes.Buffer)
+	if err := a.Serialize(buf, version); err != nil {
+		return []byte{0}
+	}
+	return buf.Bytes()
+}
+
+func (a *PayloadUpdateArbitrators) Serialize(w io.Writer, version byte) error {
+	if err := common.WriteVarUint(w, uint64(len(a.Arbitrators))); err != nil {
+		return errors.New("[PayloadUpdateArbitrators], Arbitrators count serialize failed.")
+	}
+	for _, arbitrator := range a.Arbitrators {
+		if err := common.WriteVarBytes(w, arbitrator); err != nil {
+			return errors.New("[PayloadUpdateArbitrators], Arbitrator serialize failed.")
+		}
+	}
+	return nil
+}
+
+func (a *PayloadUpdateArbitrators) Deserialize(r io.Reader, version byte) error {
+	count, err := common.ReadVarUint(r, 0)
+	if err != nil {
+		return errors.New("[PayloadUpdateArbitrators], Arbitrators count deserialize failed.")
+	}
+	a.Arbitrators = make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		arbitrator, err := common.ReadVarBytes(r)
+		if err != nil {
+			return errors.New("[PayloadUpdateArbitrators], Arbitrator deserialize failed.")
+		}
+		a.Arbitrators = append(a.Arbitrators, arbitrator)
+	}
+	return nil
+}
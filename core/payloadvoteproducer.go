@@ -0,0 +1,53 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+const VoteProducerPayloadVersion byte = 0x00
+
+// PayloadVoteProducer votes for block producers, each output of the
+// transaction votes its value for every candidate until it is spent.
+type PayloadVoteProducer struct {
+	Candidates [][]byte
+}
+
+func (a *PayloadVoteProducer) Data(version byte) []byte {
+	buf := new(bytes.Buffer)
+	if err := a.Serialize(buf, version); err != nil {
+		return []byte{0}
+	}
+	return buf.Bytes()
+}
+
+func (a *PayloadVoteProducer) Serialize(w io.Writer, version byte) error {
+	if err := common.WriteVarUint(w, uint64(len(a.Candidates))); err != nil {
+		return errors.New("[PayloadVoteProducer], Candidates count serialize failed.")
+	}
+	for _, candidate := range a.Candidates {
+		if err := common.WriteVarBytes(w, candidate); err != nil {
+			return errors.New("[PayloadVoteProducer], Candidate serialize failed.")
+		}
+	}
+	return nil
+}
+
+func (a *PayloadVoteProducer) Deserialize(r io.Reader, version byte) error {
+	count, err := common.ReadVarUint(r, 0)
+	if err != nil {
+		return errors.New("[PayloadVoteProducer], Candidates count deserialize failed.")
+	}
+	a.Candidates = make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		candidate, err := common.ReadVarBytes(r)
+		if err != nil {
+			return errors.New("[PayloadVoteProducer], Candidate deserialize failed.")
+		}
+		a.Candidates = append(a.Candidates, candidate)
+	}
+	return nil
+}
@@ -23,38 +23,56 @@ const (
 	RechargeToSideChain     TransactionType = 0x06
 	WithdrawFromSideChain   TransactionType = 0x07
 	TransferCrossChainAsset TransactionType = 0x08
+	MintAsset               TransactionType = 0x09
+	BurnAsset               TransactionType = 0x0a
+	RegisterProducer        TransactionType = 0x0b
+	CancelProducer          TransactionType = 0x0c
+	VoteProducer            TransactionType = 0x0d
+	UpdateArbitrators       TransactionType = 0x0e
+	RegisterSideChain       TransactionType = 0x0f
 )
 
 func (self TransactionType) Name() string {
+	if t, ok := payloadTypes[self]; ok {
+		return t.name
+	}
 	switch self {
-	case CoinBase:
-		return "CoinBase"
-	case RegisterAsset:
-		return "RegisterAsset"
-	case TransferAsset:
-		return "TransferAsset"
-	case Record:
-		return "Record"
 	case Deploy:
 		return "Deploy"
-	case SideChainPow:
-		return "SideChainPow"
 	case RechargeToSideChain:
 		return "RechargeToSideChain"
-	case WithdrawFromSideChain:
-		return "WithdrawFromSideChain"
-	case TransferCrossChainAsset:
-		return "TransferCrossChainAsset"
 	default:
 		return "Unknown"
 	}
 }
 
+//the version of the transaction serialization format
+type TransactionVersion byte
+
+const (
+	// TxVersionDefault is the version of the transactions serialized before
+	// the version was introduced, the version is not serialized so these
+	// transactions keep their format and hash.
+	TxVersionDefault TransactionVersion = 0x00
+	// TxVersion01 serializes the version ahead of the transaction type.
+	TxVersion01 TransactionVersion = 0x01
+
+	// TxVersionLatest is the latest version known.
+	TxVersionLatest = TxVersion01
+
+	// TxVersionMarker is the first byte of a serialized transaction of a
+	// version other than the default one, followed by the version. It is
+	// reserved from the transaction types so the transactions of the default
+	// version, which start with the type, still parse.
+	TxVersionMarker = 0xff
+)
+
 const (
 	InvalidTransactionSize = -1
 )
 
 type Transaction struct {
+	Version        TransactionVersion
 	TxType         TransactionType
 	PayloadVersion byte
 	Payload        Payload
@@ -73,6 +91,7 @@ func (tx *Transaction) String() string {
 	hash := tx.Hash()
 	return fmt.Sprint("Transaction: {\n\t",
 		"Hash: ", hash.String(), "\n\t",
+		"Version: ", tx.Version, "\n\t",
 		"TxType: ", tx.TxType.Name(), "\n\t",
 		"PayloadVersion: ", tx.PayloadVersion, "\n\t",
 		"Payload: ", BytesToHexString(tx.Payload.Data(tx.PayloadVersion)), "\n\t",
@@ -103,6 +122,10 @@ func (tx *Transaction) Serialize(w io.Writer) error {
 
 //Serialize the Transaction data without contracts
 func (tx *Transaction) SerializeUnsigned(w io.Writer) error {
+	//Version
+	if tx.Version != TxVersionDefault {
+		w.Write([]byte{TxVersionMarker, byte(tx.Version)})
+	}
 	//txType
 	w.Write([]byte{byte(tx.TxType)})
 	//PayloadVersion
@@ -176,6 +199,23 @@ func (tx *Transaction) DeserializeUnsigned(r io.Reader) error {
 	if err != nil {
 		return err
 	}
+	// the transactions of the default version start with the type
+	tx.Version = TxVersionDefault
+	if txType[0] == TxVersionMarker {
+		var version = make([]byte, 1)
+		_, err = r.Read(version)
+		if err != nil {
+			return err
+		}
+		tx.Version = TransactionVersion(version[0])
+		if tx.Version == TxVersionDefault {
+			return errors.New("default transaction version must not be serialized")
+		}
+		_, err = r.Read(txType)
+		if err != nil {
+			return err
+		}
+	}
 	tx.TxType = TransactionType(txType[0])
 
 	var payloadVersion = make([]byte, 1)
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// historical transactions serialized before the transaction version was
+// introduced, with their txids
+var historicalTransactions = []struct {
+	txType TransactionType
+	hex    string
+	txid   string
+}{
+	{
+		txType: CoinBase,
+		hex: "000403454c4101000847cfc35085f3aec0010000000000000000000000000000000000000000000000000000000000000000" +
+			"ffffffffffff02b037db964a231458d2d6ffd5ea18944c4f90e63d547c5d3b9874df66a4ead0a3b54afb0800000000000000" +
+			"00129e9cf1c5f336fcf3a6c954444ed482c5d916e506b037db964a231458d2d6ffd5ea18944c4f90e63d547c5d3b9874df66" +
+			"a4ead0a3a803f5140000000000000000129e9cf1c5f336fcf3a6c954444ed482c5d916e5061027000000",
+		txid: "2ef48abb76a8a121788f49b122cfeacbe9d4da83097474f30dafb29bd14746d6",
+	},
+	{
+		txType: TransferAsset,
+		hex: "020000016c3a8d6db4d3b4ccad1712a29c5e90e2e7bc26c603995fc18a37c85a5420ad445600ffffffff02b037db964a2314" +
+			"58d2d6ffd5ea18944c4f90e63d547c5d3b9874df66a4ead0a3047823a7170100000000000021190ff3b12919c17f232db554" +
+			"318322a6b43ba372b037db964a231458d2d6ffd5ea18944c4f90e63d547c5d3b9874df66a4ead0a300b864d9450000000000" +
+			"000021fa402bfaecabefacb6379c08edb5224fd95e25f700000000014140c72db63b7fdf90b8bf34e91f0a6394e25d1340f1" +
+			"78a1776bdc344fecf8ced8e4db627fb9ffa7068c51d3d15b92a749ffa407e2593833ec836d4cdaae1062abe52321035e1529" +
+			"938d1a36bef97806557bdb4faec8c83a8fc557c1afb287b07bd923c589ac",
+		txid: "eff50a31f1975d57f1c9bed8c7772e26df9014e7f62dd5091fbbcdfa5321c250",
+	},
+}
+
+func TestTransaction_HistoricalVectors(t *testing.T) {
+	for _, v := range historicalTransactions {
+		data, err := hex.DecodeString(v.hex)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// the historical transactions parse as the default version
+		var txn Transaction
+		if !assert.NoError(t, txn.Deserialize(bytes.NewReader(data))) {
+			return
+		}
+		assert.Equal(t, TxVersionDefault, txn.Version)
+		assert.Equal(t, v.txType, txn.TxType)
+		assert.Equal(t, v.txid, toReversedString(txn.Hash()))
+
+		// and serialize to the same bytes
+		buf := new(bytes.Buffer)
+		assert.NoError(t, txn.Serialize(buf))
+		assert.Equal(t, data, buf.Bytes())
+	}
+}
+
+func TestTransaction_Version(t *testing.T) {
+	data, err := hex.DecodeString(historicalTransactions[1].hex)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var legacy Transaction
+	if !assert.NoError(t, legacy.Deserialize(bytes.NewReader(data))) {
+		return
+	}
+
+	// 1. The version is serialized ahead of the type behind the marker
+	txn := legacy
+	txn.hash = nil
+	txn.Version = TxVersion01
+	buf := new(bytes.Buffer)
+	assert.NoError(t, txn.Serialize(buf))
+	assert.Equal(t, append([]byte{TxVersionMarker, byte(TxVersion01)}, data...), buf.Bytes())
+	assert.NotEqual(t, legacy.Hash(), txn.Hash())
+
+	var decoded Transaction
+	assert.NoError(t, decoded.Deserialize(bytes.NewReader(buf.Bytes())))
+	assert.Equal(t, TxVersion01, decoded.Version)
+	assert.Equal(t, TransferAsset, decoded.TxType)
+	assert.Equal(t, txn.Hash(), decoded.Hash())
+
+	// 2. The default version must not be serialized behind the marker, so a
+	// transaction has a single encoding
+	encoded := append([]byte{TxVersionMarker, byte(TxVersionDefault)}, data...)
+	assert.Error(t, new(Transaction).Deserialize(bytes.NewReader(encoded)))
+
+	// 3. The marker is reserved from the transaction types
+	assert.Panics(t, func() {
+		RegisterPayload(TxVersionMarker, "Test", func() Payload { return new(PayloadRecord) })
+	})
+}
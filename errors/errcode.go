@@ -22,6 +22,13 @@ const (
 	ErrIneffectiveCoinbase   ErrCode = 45018
 	ErrUTXOLocked            ErrCode = 45019
 	ErrSideChainPowConsensus ErrCode = 45020
+	ErrRegisterAsset         ErrCode = 45021
+	ErrAssetSupply           ErrCode = 45022
+	ErrProducer              ErrCode = 45023
+	ErrVoteProducer          ErrCode = 45024
+	ErrUpdateArbitrators     ErrCode = 45025
+	ErrSideChain             ErrCode = 45026
+	ErrTransactionVersion    ErrCode = 45027
 
 	SessionExpired       ErrCode = 41001
 	IllegalDataFormat    ErrCode = 41003
@@ -31,6 +38,7 @@ const (
 	InvalidToken         ErrCode = 42003
 	InvalidTransaction   ErrCode = 43001
 	InvalidAsset         ErrCode = 43002
+	InvalidBlock         ErrCode = 43003
 	UnknownTransaction   ErrCode = 44001
 	UnknownAsset         ErrCode = 44002
 	UnknownBlock         ErrCode = 44003
@@ -48,12 +56,20 @@ var ErrMap = map[ErrCode]string{
 	InvalidToken:             "Verify token error",
 	InvalidTransaction:       "Invalid transaction",
 	InvalidAsset:             "Invalid asset",
+	InvalidBlock:             "Invalid block",
 	UnknownTransaction:       "Unknown Transaction",
 	UnknownAsset:             "Unknown asset",
 	UnknownBlock:             "Unknown Block",
 	InternalError:            "Internal error",
 	ErrUTXOLocked:            "Error utxo locked",
 	ErrSideChainPowConsensus: "Error sidechain pow consensus",
+	ErrRegisterAsset:         "Error register asset",
+	ErrAssetSupply:           "Error asset supply",
+	ErrProducer:              "Error producer",
+	ErrVoteProducer:          "Error vote producer",
+	ErrUpdateArbitrators:     "Error update arbitrators",
+	ErrSideChain:             "Error side chain",
+	ErrTransactionVersion:    "Error transaction version",
 	ErrInvalidInput:          "INTERNAL ERROR, ErrInvalidInput",
 	ErrInvalidOutput:         "INTERNAL ERROR, ErrInvalidOutput",
 	ErrAssetPrecision:        "INTERNAL ERROR, ErrAssetPrecision",
@@ -12,13 +12,15 @@ type Subscriber chan interface{}
 type EventType int16
 
 const (
-	EventSaveBlock               EventType = 0
-	EventReplyTx                 EventType = 1
-	EventBlockPersistCompleted   EventType = 2
-	EventNewInventory            EventType = 3
-	EventNodeDisconnect          EventType = 4
-	EventRollbackTransaction     EventType = 5
-	EventNewTransactionPutInPool EventType = 6
+	EventSaveBlock                 EventType = 0
+	EventReplyTx                   EventType = 1
+	EventBlockPersistCompleted     EventType = 2
+	EventNewInventory              EventType = 3
+	EventNodeDisconnect            EventType = 4
+	EventRollbackTransaction       EventType = 5
+	EventNewTransactionPutInPool   EventType = 6
+	EventRemoveTransactionFromPool EventType = 7
+	EventWithdrawStatusChanged     EventType = 8
 )
 
 type Event struct {
@@ -47,6 +49,18 @@ func (e *Event) Subscribe(eventType EventType, eventFunc EventFunc) Subscriber {
 	return sub
 }
 
+//  removes a subscriber from Event.
+func (e *Event) UnSubscribe(eventType EventType, subscriber Subscriber) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	subEvent, ok := e.subscribers[eventType]
+	if !ok {
+		return
+	}
+	delete(subEvent, subscriber)
+}
+
 //Notify subscribers that Subscribe specified event
 func (e *Event) Notify(eventType EventType, value interface{}) (err error) {
 	e.m.RLock()
@@ -63,3 +77,21 @@ func (e *Event) Notify(eventType EventType, value interface{}) (err error) {
 	}
 	return
 }
+
+//NotifySync calls the subscribers in the caller goroutine, so they are notified
+//in the order the events happen, the subscribers must return quickly
+func (e *Event) NotifySync(eventType EventType, value interface{}) (err error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+
+	subs, ok := e.subscribers[eventType]
+	if !ok {
+		err = errors.New("No event type.")
+		return
+	}
+
+	for _, event := range subs {
+		event(value)
+	}
+	return
+}
@@ -44,7 +44,6 @@ type PowService struct {
 	discreteMining bool
 
 	blockPersistCompletedSubscriber events.Subscriber
-	RollbackTransactionSubscriber   events.Subscriber
 
 	wg   sync.WaitGroup
 	quit chan struct{}
@@ -67,8 +66,6 @@ func (pow *PowService) CreateCoinbaseTx(nextBlockHeight uint32, minerAddr string
 	if err != nil {
 		return nil, err
 	}
-	// TODO: calculate the current delegate by votes
-	delegateProgramHash := FoundationAddress
 
 	pd := &PayloadCoinBase{
 		CoinbaseData: []byte(config.Parameters.PowConfiguration.MinerInfo),
@@ -95,11 +92,6 @@ func (pow *PowService) CreateCoinbaseTx(nextBlockHeight uint32, minerAddr string
 			Value:       0,
 			ProgramHash: *minerProgramHash,
 		},
-		{
-			AssetID:     DefaultLedger.Blockchain.AssetID,
-			Value:       0,
-			ProgramHash: delegateProgramHash,
-		},
 	}
 
 	nonce := make([]byte, 8)
@@ -150,6 +142,8 @@ func (pow *PowService) GenerateBlock(minerAddr string) (*Block, error) {
 	}
 	sort.Sort(txsByFeeDesc)
 
+	medianTime := CalcPastMedianTime(DefaultLedger.Blockchain.BestChain)
+
 	for _, tx := range txsByFeeDesc {
 		totalTxsSize = totalTxsSize + tx.GetSize()
 		if totalTxsSize > config.Parameters.MaxBlockSize {
@@ -159,7 +153,11 @@ func (pow *PowService) GenerateBlock(minerAddr string) (*Block, error) {
 			break
 		}
 
-		if !IsFinalizedTransaction(tx, nextBlockHeight) {
+		if !IsFinalizedTransaction(tx, nextBlockHeight, medianTime) {
+			continue
+		}
+		if err := CheckTransactionSequenceLock(tx, nextBlockHeight, medianTime); err != nil {
+			log.Warn("check transaction sequence lock failed, locked transaction:", tx.Hash().String())
 			continue
 		}
 		if errCode := CheckTransactionContext(tx); errCode != Success {
@@ -175,15 +173,26 @@ func (pow *PowService) GenerateBlock(minerAddr string) (*Block, error) {
 		txCount++
 	}
 
-	blockReward := RewardAmountPerBlock
+	blockReward := CalcBlockSubsidy(nextBlockHeight, config.Parameters.ChainParam)
 	totalReward := totalTxFee + blockReward
 
 	// PoW miners and DPoS are each equally allocated 35%. The remaining 30% goes to the Cyber Republic fund
-	rewardFoundation := common.Fixed64(float64(totalReward) * 0.3)
-	rewardMiner := common.Fixed64(float64(totalReward) * 0.35)
-	msgBlock.Transactions[0].Outputs[0].Value = rewardFoundation
-	msgBlock.Transactions[0].Outputs[1].Value = rewardMiner
-	msgBlock.Transactions[0].Outputs[2].Value = common.Fixed64(totalReward) - rewardFoundation - rewardMiner
+	rewards := CalcCoinbaseRewards(totalReward, config.Parameters.ChainParam)
+	msgBlock.Transactions[0].Outputs[0].Value = rewards.Foundation
+	msgBlock.Transactions[0].Outputs[1].Value = rewards.Miner
+
+	// The delegate reward is shared by the elected producers
+	delegates, err := CalcDelegateRewards(rewards.Delegate, GetElectedProducers())
+	if err != nil {
+		return nil, err
+	}
+	for _, delegate := range delegates {
+		msgBlock.Transactions[0].Outputs = append(msgBlock.Transactions[0].Outputs, &Output{
+			AssetID:     DefaultLedger.Blockchain.AssetID,
+			Value:       delegate.Amount,
+			ProgramHash: delegate.ProgramHash,
+		})
+	}
 
 	txHash := make([]common.Uint256, 0, len(msgBlock.Transactions))
 	for _, tx := range msgBlock.Transactions {
@@ -313,28 +322,11 @@ func (pow *PowService) Halt() {
 	pow.Started = false
 }
 
-func (pow *PowService) RollbackTransaction(v interface{}) {
-	if block, ok := v.(*Block); ok {
-		for _, tx := range block.Transactions[1:] {
-			err := node.LocalNode.MaybeAcceptTransaction(tx)
-			if err == nil {
-				node.LocalNode.RemoveTransaction(tx)
-			} else {
-				log.Error(err)
-			}
-		}
-	}
-}
-
 func (pow *PowService) BlockPersistCompleted(v interface{}) {
 	log.Debug()
 	if block, ok := v.(*Block); ok {
 		log.Infof("persist block: %x", block.Hash())
-		err := node.LocalNode.CleanSubmittedTransactions(block)
-		if err != nil {
-			log.Warn(err)
-		}
-		node.LocalNode.SetHeight(uint64(DefaultLedger.Blockchain.GetBestHeight()))
+		node.LocalNode.SetHeight(uint64(block.Header.Height))
 	}
 }
 
@@ -347,7 +339,6 @@ func NewPowService() *PowService {
 	}
 
 	pow.blockPersistCompletedSubscriber = DefaultLedger.Blockchain.BCEvents.Subscribe(events.EventBlockPersistCompleted, pow.BlockPersistCompleted)
-	pow.RollbackTransactionSubscriber = DefaultLedger.Blockchain.BCEvents.Subscribe(events.EventRollbackTransaction, pow.RollbackTransaction)
 
 	log.Trace("pow Service Init succeed")
 	return pow
@@ -4,6 +4,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/wuyazero/Elastos.ELA/blockchain"
 	"github.com/wuyazero/Elastos.ELA/bloom"
 	"github.com/wuyazero/Elastos.ELA/core"
 	"github.com/wuyazero/Elastos.ELA/errors"
@@ -54,8 +55,14 @@ type Noder interface {
 	RemoveFromHandshakeQueue(node Noder)
 	GetConnectionCount() (uint, uint)
 	GetTransactionPool(bool) map[common.Uint256]*core.Transaction
+	GetTxPoolEntry(hash common.Uint256) *blockchain.TxPoolEntry
+	GetTxPoolEntries() []*blockchain.TxPoolEntry
+	GetTxPoolInfo() *blockchain.TxPoolInfo
 	AppendToTxnPool(*core.Transaction) errors.ErrCode
+	CheckTransaction(*core.Transaction) errors.ErrCode
 	IsDuplicateSidechainTx(sidechainTxHash common.Uint256) bool
+	GetWithdrawTransaction(sidechainTxHash common.Uint256) *core.Transaction
+	EstimateSmartFee(target uint32) (*blockchain.FeeEstimate, error)
 	ExistedID(id common.Uint256) bool
 	RequireNeighbourList()
 	UpdateInfo(t time.Time, version uint32, services uint64,
@@ -79,7 +86,6 @@ type Noder interface {
 	WaitForSyncFinish()
 	CleanSubmittedTransactions(block *core.Block) error
 	MaybeAcceptTransaction(txn *core.Transaction) error
-	RemoveTransaction(txn *core.Transaction)
 
 	UpdateLastActive()
 	SetHeight(height uint64)
@@ -3,6 +3,7 @@ package servers
 import (
 	"github.com/wuyazero/Elastos.ELA.Utility/common"
 	. "github.com/wuyazero/Elastos.ELA/core"
+	"github.com/wuyazero/Elastos.ELA/errors"
 )
 
 const TlsPort = 443
@@ -120,6 +121,11 @@ type RegisterAssetInfo struct {
 	Controller string
 }
 
+type AssetSupplyInfo struct {
+	AssetID string
+	Amount  string
+}
+
 type SideChainPowInfo struct {
 	BlockHeight     uint32
 	SideBlockHash   string
@@ -139,6 +145,31 @@ type WithdrawFromSideChainInfo struct {
 	SideChainTransactionHashes []string
 }
 
+type RegisterProducerInfo struct {
+	PublicKey string
+	NickName  string
+	Url       string
+}
+
+type CancelProducerInfo struct {
+	PublicKey string
+}
+
+type VoteProducerInfo struct {
+	Candidates []string
+}
+
+type UpdateArbitratorsInfo struct {
+	Arbitrators []string
+}
+
+type RegisterSideChainInfo struct {
+	GenesisHash             string
+	GenesisAddress          string
+	Name                    string
+	MinArbitratorSignatures uint32
+}
+
 type UTXOInfo struct {
 	AssetId       string `json:"assetid"`
 	Txid          string `json:"txid"`
@@ -148,3 +179,160 @@ type UTXOInfo struct {
 	Confirmations uint32 `json:"confirmations"`
 	OutputLock    uint32 `json:"outputlock"`
 }
+
+type FeeEstimateInfo struct {
+	FeeRate    string  `json:"feerate"`
+	Blocks     uint32  `json:"blocks"`
+	Confidence float64 `json:"confidence"`
+}
+
+type MempoolAcceptInfo struct {
+	TxID    string         `json:"txid"`
+	Allowed bool           `json:"allowed"`
+	ErrCode errors.ErrCode `json:"errcode"`
+	Reason  string         `json:"reason"`
+	Fee     string         `json:"fee"`
+	FeeRate string         `json:"feerate"`
+}
+
+type ValidationInfo struct {
+	Valid   bool   `json:"valid"`
+	Stage   string `json:"stage,omitempty"`
+	ErrCode int    `json:"errcode,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type DecodedTransactionInfo struct {
+	*TransactionInfo
+	Fee        string         `json:"fee,omitempty"`
+	FeeRate    string         `json:"feerate,omitempty"`
+	Validation ValidationInfo `json:"validation"`
+}
+
+type DecodedBlockInfo struct {
+	BlockInfo
+	Validation ValidationInfo `json:"validation"`
+}
+
+type BlockSubsidyInfo struct {
+	Height     uint32 `json:"height"`
+	Subsidy    string `json:"subsidy"`
+	Foundation string `json:"foundation"`
+	Miner      string `json:"miner"`
+	Delegate   string `json:"delegate"`
+}
+
+type SupplyInfo struct {
+	Height        uint32 `json:"height"`
+	GenesisSupply string `json:"genesissupply"`
+	Supply        string `json:"supply"`
+}
+
+type RecordInfo struct {
+	RecordType string `json:"recordtype"`
+	RecordData string `json:"recorddata"`
+	DataHash   string `json:"datahash"`
+	TxID       string `json:"txid"`
+	BlockHash  string `json:"blockhash"`
+	Height     uint32 `json:"height"`
+	Proof      string `json:"proof"`
+}
+
+type ProducerInfo struct {
+	PublicKey      string `json:"publickey"`
+	NickName       string `json:"nickname"`
+	Url            string `json:"url"`
+	Address        string `json:"address"`
+	Deposit        string `json:"deposit"`
+	DepositAmount  string `json:"depositamount"`
+	RegisterHeight uint32 `json:"registerheight"`
+	CancelHeight   uint32 `json:"cancelheight"`
+	Votes          string `json:"votes"`
+	Active         bool   `json:"active"`
+	Elected        bool   `json:"elected"`
+}
+
+type VotesInfo struct {
+	PublicKey string `json:"publickey"`
+	Votes     string `json:"votes"`
+	Rank      int    `json:"rank"`
+	Elected   bool   `json:"elected"`
+}
+
+type SideChainInfo struct {
+	Name                    string `json:"name"`
+	GenesisHash             string `json:"genesishash"`
+	GenesisAddress          string `json:"genesisaddress"`
+	MinArbitratorSignatures uint32 `json:"minarbitratorsignatures"`
+	RegisterHeight          uint32 `json:"registerheight"`
+	Deposited               string `json:"deposited"`
+	Withdrawn               string `json:"withdrawn"`
+	Locked                  string `json:"locked"`
+}
+
+type SideChainPowAnchorInfo struct {
+	SideGenesisHash string `json:"sidegenesishash"`
+	SideBlockHash   string `json:"sideblockhash"`
+	SideBlockHeight uint32 `json:"sideblockheight"`
+	TxID            string `json:"txid"`
+	BlockHash       string `json:"blockhash"`
+	Height          uint32 `json:"height"`
+}
+
+type CrossChainProofInfo struct {
+	TxID        string                       `json:"txid"`
+	Transaction *TransactionInfo             `json:"transaction"`
+	Payload     *TransferCrossChainAssetInfo `json:"payload"`
+	BlockHash   string                       `json:"blockhash"`
+	Height      uint32                       `json:"height"`
+	Proof       string                       `json:"proof"`
+}
+
+type WithdrawStatusInfo struct {
+	SideChainTxHash string `json:"sidechaintxhash"`
+	Status          string `json:"status"`
+	GenesisAddress  string `json:"genesisaddress,omitempty"`
+	TxID            string `json:"txid,omitempty"`
+	Height          uint32 `json:"height,omitempty"`
+	Confirmations   uint32 `json:"confirmations,omitempty"`
+}
+
+type MempoolInfo struct {
+	Size   int    `json:"size"`
+	Bytes  int    `json:"bytes"`
+	Usage  int    `json:"usage"`
+	MinFee string `json:"minfee"`
+}
+
+type MempoolEntryInfo struct {
+	TxID                       string          `json:"txid"`
+	TxType                     TransactionType `json:"type"`
+	Size                       int             `json:"size"`
+	Fee                        string          `json:"fee"`
+	FeeRate                    string          `json:"feerate"`
+	Time                       int64           `json:"time"`
+	Height                     uint32          `json:"height"`
+	CrossChain                 string          `json:"crosschain"`
+	CrossChainAddresses        []string        `json:"crosschainaddresses,omitempty"`
+	SideChainTransactionHashes []string        `json:"sidechaintransactionhashes,omitempty"`
+}
+
+type AssetInfo struct {
+	AssetID     string          `json:"assetid"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Precision   byte            `json:"precision"`
+	AssetType   AssetType       `json:"assettype"`
+	RecordType  AssetRecordType `json:"recordtype"`
+	Controller  string          `json:"controller,omitempty"`
+	MaxSupply   string          `json:"maxsupply,omitempty"`
+	Issued      string          `json:"issued,omitempty"`
+	Burned      string          `json:"burned,omitempty"`
+	Circulating string          `json:"circulating,omitempty"`
+}
+
+type AssetBalanceInfo struct {
+	AssetID   string `json:"assetid"`
+	AssetName string `json:"assetname"`
+	Balance   string `json:"balance"`
+}
@@ -37,17 +37,44 @@ func StartRPCServer() {
 	mainMux["getblockhash"] = GetBlockHash
 	mainMux["getconnectioncount"] = GetConnectionCount
 	mainMux["getrawmempool"] = GetTransactionPool
+	mainMux["getmempoolinfo"] = GetMempoolInfo
+	mainMux["getmempoolentry"] = GetMempoolEntry
 	mainMux["getrawtransaction"] = GetRawTransaction
 	mainMux["getneighbors"] = GetNeighbors
 	mainMux["getnodestate"] = GetNodeState
 	mainMux["sendrawtransaction"] = SendRawTransaction
+	mainMux["testmempoolaccept"] = TestMempoolAccept
+	mainMux["createrawtransaction"] = CreateRawTransaction
+	mainMux["decoderawtransaction"] = DecodeRawTransaction
+	mainMux["decodeblock"] = DecodeBlock
+	mainMux["createpst"] = CreatePST
+	mainMux["combinepst"] = CombinePST
+	mainMux["finalizepst"] = FinalizePST
 	mainMux["getarbitratorgroupbyheight"] = GetArbitratorGroupByHeight
 	mainMux["getbestblockhash"] = GetBestBlockHash
 	mainMux["getblockcount"] = GetBlockCount
 	mainMux["getblockbyheight"] = GetBlockByHeight
+	mainMux["getblocksubsidy"] = GetBlockSubsidy
+	mainMux["getsupply"] = GetSupply
 	mainMux["getexistwithdrawtransactions"] = GetExistWithdrawTransactions
 	mainMux["listunspent"] = ListUnspent
 	mainMux["getreceivedbyaddress"] = GetReceivedByAddress
+	mainMux["estimatesmartfee"] = EstimateSmartFee
+	mainMux["listassets"] = ListAssets
+	mainMux["getasset"] = GetAssetByHash
+	mainMux["getassetinfo"] = GetAssetInfo
+	mainMux["getassetbalances"] = GetAssetBalances
+	mainMux["getbalancebyasset"] = GetBalanceByAsset
+	mainMux["getrecords"] = GetRecords
+	mainMux["verifyrecord"] = VerifyRecord
+	mainMux["listproducers"] = ListProducers
+	mainMux["getvotes"] = GetVotes
+	mainMux["listsidechains"] = ListSideChains
+	mainMux["getsidechaininfo"] = GetSideChainInfo
+	mainMux["getsidechainpow"] = GetSideChainPow
+	mainMux["getcrosschainproof"] = GetCrossChainProof
+	mainMux["getwithdrawstatus"] = GetWithdrawStatusByHashes
+	mainMux["listwithdraws"] = ListWithdraws
 	// aux interfaces
 	mainMux["help"] = AuxHelp
 	mainMux["submitauxblock"] = SubmitAuxBlock
@@ -182,6 +209,52 @@ func convertParams(method string, params []interface{}) Params {
 		return FromArray(params, "addresses")
 	case "getreceivedbyaddress":
 		return FromArray(params, "address")
+	case "getrawmempool":
+		return FromArray(params, "verbose", "type", "start", "count")
+	case "getmempoolentry":
+		return FromArray(params, "txid")
+	case "testmempoolaccept":
+		return FromArray(params, "rawtxs")
+	case "createrawtransaction":
+		return FromArray(params, "transaction")
+	case "decoderawtransaction":
+		return FromArray(params, "data")
+	case "decodeblock":
+		return FromArray(params, "data")
+	case "createpst":
+		return FromArray(params, "data")
+	case "combinepst":
+		return FromArray(params, "psts")
+	case "finalizepst":
+		return FromArray(params, "pst")
+	case "estimatesmartfee":
+		return FromArray(params, "target")
+	case "getasset", "getassetinfo":
+		return FromArray(params, "hash")
+	case "getassetbalances":
+		return FromArray(params, "addr")
+	case "getbalancebyasset":
+		return FromArray(params, "addr", "assetid")
+	case "getblocksubsidy":
+		return FromArray(params, "height")
+	case "getsupply":
+		return FromArray(params, "height")
+	case "getrecords":
+		return FromArray(params, "type", "from", "to")
+	case "verifyrecord":
+		return FromArray(params, "hash")
+	case "getvotes":
+		return FromArray(params, "publickey")
+	case "getsidechaininfo":
+		return FromArray(params, "genesishash")
+	case "getsidechainpow":
+		return FromArray(params, "genesishash", "from", "to")
+	case "getcrosschainproof":
+		return FromArray(params, "txid")
+	case "getwithdrawstatus":
+		return FromArray(params, "hashes")
+	case "listwithdraws":
+		return FromArray(params, "genesisaddress", "start", "count")
 	default:
 		return Params{}
 	}
@@ -32,8 +32,15 @@ const (
 	Api_GetUTXObyAsset      = "/api/v1/asset/utxo/:addr/:assetid"
 	Api_GetUTXObyAddr       = "/api/v1/asset/utxos/:addr"
 	Api_SendRawTransaction  = "/api/v1/transaction"
+	Api_DecodeRawTx         = "/api/v1/transaction/decode"
+	Api_DecodeBlock         = "/api/v1/block/decode"
 	Api_GetTransactionPool  = "/api/v1/transactionpool"
 	Api_Restart             = "/api/v1/restart"
+	Api_EstimateSmartFee    = "/api/v1/fee/estimate/:target"
+	Api_GetMempoolInfo      = "/api/v1/transactionpool/info"
+	Api_GetMempoolEntry     = "/api/v1/transactionpool/entry/:hash"
+	Api_ListAssets          = "/api/v1/assets"
+	Api_GetAssetBalances    = "/api/v1/assets/balances/:addr"
 )
 
 type Action struct {
@@ -113,10 +120,17 @@ func (rt *restServer) initializeMethod() {
 		Api_GetBalanceByAddr:    {name: "getbalancebyaddr", handler: servers.GetBalanceByAddr},
 		Api_GetBalancebyAsset:   {name: "getbalancebyasset", handler: servers.GetBalanceByAsset},
 		Api_Restart:             {name: "restart", handler: rt.Restart},
+		Api_EstimateSmartFee:    {name: "estimatesmartfee", handler: servers.EstimateSmartFee},
+		Api_GetMempoolInfo:      {name: "getmempoolinfo", handler: servers.GetMempoolInfo},
+		Api_GetMempoolEntry:     {name: "getmempoolentry", handler: servers.GetMempoolEntry},
+		Api_ListAssets:          {name: "listassets", handler: servers.ListAssets},
+		Api_GetAssetBalances:    {name: "getassetbalances", handler: servers.GetAssetBalances},
 	}
 
 	postMethodMap := map[string]Action{
 		Api_SendRawTransaction: {name: "sendrawtransaction", handler: servers.SendRawTransaction},
+		Api_DecodeRawTx:        {name: "decoderawtransaction", handler: servers.DecodeRawTransaction},
+		Api_DecodeBlock:        {name: "decodeblock", handler: servers.DecodeBlock},
 	}
 	rt.postMap = postMethodMap
 	rt.getMap = getMethodMap
@@ -124,7 +138,11 @@ func (rt *restServer) initializeMethod() {
 
 func (rt *restServer) getPath(url string) string {
 
-	if strings.Contains(url, strings.TrimRight(Api_GetblockTxsByHeight, ":height")) {
+	if strings.Contains(url, Api_DecodeRawTx) {
+		return Api_DecodeRawTx
+	} else if strings.Contains(url, Api_DecodeBlock) {
+		return Api_DecodeBlock
+	} else if strings.Contains(url, strings.TrimRight(Api_GetblockTxsByHeight, ":height")) {
 		return Api_GetblockTxsByHeight
 	} else if strings.Contains(url, strings.TrimRight(Api_Getblockbyheight, ":height")) {
 		return Api_Getblockbyheight
@@ -144,6 +162,12 @@ func (rt *restServer) getPath(url string) string {
 		return Api_GetUTXObyAsset
 	} else if strings.Contains(url, strings.TrimRight(Api_Getasset, ":hash")) {
 		return Api_Getasset
+	} else if strings.Contains(url, strings.TrimRight(Api_GetMempoolEntry, ":hash")) {
+		return Api_GetMempoolEntry
+	} else if strings.Contains(url, strings.TrimRight(Api_EstimateSmartFee, ":target")) {
+		return Api_EstimateSmartFee
+	} else if strings.Contains(url, strings.TrimRight(Api_GetAssetBalances, ":addr")) {
+		return Api_GetAssetBalances
 	}
 	return url
 }
@@ -192,8 +216,25 @@ func (rt *restServer) getParams(r *http.Request, url string, req map[string]inte
 
 	case Api_Restart:
 
+	case Api_GetMempoolInfo:
+
+	case Api_GetMempoolEntry:
+		req["txid"] = getParam(r, "hash")
+
+	case Api_EstimateSmartFee:
+		req["target"] = getParam(r, "target")
+
+	case Api_ListAssets:
+
+	case Api_GetAssetBalances:
+		req["addr"] = getParam(r, "addr")
+
 	case Api_SendRawTransaction:
 
+	case Api_DecodeRawTx:
+
+	case Api_DecodeBlock:
+
 	}
 	return req
 }
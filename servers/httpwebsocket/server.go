@@ -25,10 +25,13 @@ import (
 var instance *WebSocketServer
 
 var (
-	PushBlockFlag    = true
-	PushRawBlockFlag = true
-	PushBlockTxsFlag = true
-	PushNewTxsFlag   = true
+	PushBlockFlag          = true
+	PushRawBlockFlag       = true
+	PushBlockTxsFlag       = true
+	PushNewTxsFlag         = true
+	PushRemovedTxsFlag     = true
+	PushSideChainPowFlag   = true
+	PushWithdrawStatusFlag = true
 )
 
 type Handler func(Params) map[string]interface{}
@@ -41,16 +44,26 @@ type WebSocketServer struct {
 
 	SessionList *SessionList
 	ActionMap   map[string]Handler
+
+	// withdrawMutex guards the withdraw status changes waiting to be pushed,
+	// they are pushed by one goroutine in the order they are published
+	withdrawMutex       sync.Mutex
+	withdrawTransitions [][]*chain.WithdrawIndex
+	withdrawSignal      chan struct{}
 }
 
 func StartServer() {
 	chain.DefaultLedger.Blockchain.BCEvents.Subscribe(events.EventBlockPersistCompleted, SendBlock2WSclient)
 	chain.DefaultLedger.Blockchain.BCEvents.Subscribe(events.EventNewTransactionPutInPool, SendTransaction2WSclient)
+	chain.DefaultLedger.Blockchain.BCEvents.Subscribe(events.EventRemoveTransactionFromPool, SendRemovedTransaction2WSclient)
+	chain.DefaultLedger.Blockchain.BCEvents.Subscribe(events.EventWithdrawStatusChanged, SendWithdrawStatus2WSclient)
 
 	instance = &WebSocketServer{
-		Upgrader:    websocket.Upgrader{},
-		SessionList: &SessionList{OnlineList: make(map[string]*Session)},
+		Upgrader:       websocket.Upgrader{},
+		SessionList:    &SessionList{OnlineList: make(map[string]*Session)},
+		withdrawSignal: make(chan struct{}, 1),
 	}
+	go instance.pushWithdrawTransitions()
 	instance.Start()
 
 }
@@ -242,6 +255,14 @@ func SendTransaction2WSclient(v interface{}) {
 	}
 }
 
+func SendRemovedTransaction2WSclient(v interface{}) {
+	if PushRemovedTxsFlag {
+		go func() {
+			instance.PushResult("sendremovedtransaction", v)
+		}()
+	}
+}
+
 func SendBlock2WSclient(v interface{}) {
 	//if PushBlockFlag {
 	//	go func() {
@@ -258,6 +279,42 @@ func SendBlock2WSclient(v interface{}) {
 			instance.PushResult("sendblocktransactions", v)
 		}()
 	}
+	if PushSideChainPowFlag {
+		if block, ok := v.(*Block); ok && len(GetSideChainPowAnchors(block)) > 0 {
+			go func() {
+				instance.PushResult("sendsidechainpow", v)
+			}()
+		}
+	}
+}
+
+// SendWithdrawStatus2WSclient queues the withdraw status changes published by
+// the transaction pool, it is called synchronously so it must not block.
+func SendWithdrawStatus2WSclient(v interface{}) {
+	transitions, ok := v.([]*chain.WithdrawIndex)
+	if !PushWithdrawStatusFlag || !ok || instance == nil {
+		return
+	}
+	instance.withdrawMutex.Lock()
+	instance.withdrawTransitions = append(instance.withdrawTransitions, transitions)
+	instance.withdrawMutex.Unlock()
+	select {
+	case instance.withdrawSignal <- struct{}{}:
+	default:
+	}
+}
+
+func (server *WebSocketServer) pushWithdrawTransitions() {
+	for range server.withdrawSignal {
+		server.withdrawMutex.Lock()
+		queue := server.withdrawTransitions
+		server.withdrawTransitions = nil
+		server.withdrawMutex.Unlock()
+
+		for _, transitions := range queue {
+			server.PushResult("sendwithdrawstatus", GetWithdrawTransitions(transitions))
+		}
+	}
 }
 
 func (server *WebSocketServer) PushResult(action string, v interface{}) {
@@ -277,7 +334,13 @@ func (server *WebSocketServer) PushResult(action string, v interface{}) {
 		if block, ok := v.(*Block); ok {
 			result = GetBlockTransactions(block)
 		}
-	case "sendnewtransaction":
+	case "sendsidechainpow":
+		if block, ok := v.(*Block); ok {
+			result = GetSideChainPowAnchors(block)
+		}
+	case "sendwithdrawstatus":
+		result = v
+	case "sendnewtransaction", "sendremovedtransaction":
 		if tx, ok := v.(*Transaction); ok {
 			result = GetTransactionInfo(nil, tx)
 		}
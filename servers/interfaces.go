@@ -5,16 +5,19 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 
 	aux "github.com/wuyazero/Elastos.ELA/auxpow"
 	chain "github.com/wuyazero/Elastos.ELA/blockchain"
+	"github.com/wuyazero/Elastos.ELA/bloom"
 	"github.com/wuyazero/Elastos.ELA/config"
 	. "github.com/wuyazero/Elastos.ELA/core"
 	. "github.com/wuyazero/Elastos.ELA/errors"
 	"github.com/wuyazero/Elastos.ELA/log"
 	"github.com/wuyazero/Elastos.ELA/pow"
 	. "github.com/wuyazero/Elastos.ELA/protocol"
+	"github.com/wuyazero/Elastos.ELA/txbuilder"
 
 	. "github.com/wuyazero/Elastos.ELA.Utility/common"
 	"github.com/wuyazero/Elastos.ELA.Utility/p2p"
@@ -22,6 +25,9 @@ import (
 
 const (
 	AUXBLOCK_GENERATED_INTERVAL_SECONDS = 60
+	// MAX_SIDECHAINPOW_RANGE is the most side block heights getsidechainpow
+	// returns the anchored blocks of
+	MAX_SIDECHAINPOW_RANGE = 1000
 )
 
 var ServerNode Noder
@@ -87,7 +93,7 @@ func GetTransactionInfo(header *Header, tx *Transaction) *TransactionInfo {
 		Hash:           txHashStr,
 		Size:           size,
 		VSize:          size,
-		Version:        0x00,
+		Version:        uint32(tx.Version),
 		LockTime:       tx.LockTime,
 		Inputs:         inputs,
 		Outputs:        outputs,
@@ -97,7 +103,7 @@ func GetTransactionInfo(header *Header, tx *Transaction) *TransactionInfo {
 		BlockTime:      blockTime,
 		TxType:         tx.TxType,
 		PayloadVersion: tx.PayloadVersion,
-		Payload:        getPayloadInfo(tx.Payload),
+		Payload:        getPayloadInfo(tx.TxType, tx.Payload),
 		Attributes:     attributes,
 		Programs:       programs,
 	}
@@ -403,12 +409,143 @@ func GetConnectionCount(param Params) map[string]interface{} {
 	return ResponsePack(Success, count)
 }
 
+// GetTransactionPool returns all transactions in txnpool if no parameter is given.
+// With the verbose parameter, the entries are sorted by the time they are added
+// and can be filtered by transaction type and paginated by start and count.
 func GetTransactionPool(param Params) map[string]interface{} {
-	txs := make([]*TransactionInfo, 0)
-	for _, t := range ServerNode.GetTransactionPool(false) {
-		txs = append(txs, GetTransactionInfo(nil, t))
+	verbose, ok := param.Bool("verbose")
+	if !ok {
+		txs := make([]*TransactionInfo, 0)
+		for _, t := range ServerNode.GetTransactionPool(false) {
+			txs = append(txs, GetTransactionInfo(nil, t))
+		}
+		return ResponsePack(Success, txs)
+	}
+
+	entries := ServerNode.GetTxPoolEntries()
+	if _, ok := param["type"]; ok {
+		txType, ok := param.Uint("type")
+		if !ok || txType > 0xff {
+			return ResponsePack(InvalidParams, "type must be a transaction type")
+		}
+		filtered := make([]*chain.TxPoolEntry, 0, len(entries))
+		for _, entry := range entries {
+			if entry.Tx.TxType == TransactionType(txType) {
+				filtered = append(filtered, entry)
+			}
+		}
+		entries = filtered
+	}
+
+	start, ok := param.Uint("start")
+	if _, exist := param["start"]; exist && !ok {
+		return ResponsePack(InvalidParams, "start must be a non-negative integer")
+	}
+	if int(start) > len(entries) {
+		start = uint32(len(entries))
+	}
+	end := len(entries)
+	if _, exist := param["count"]; exist {
+		count, ok := param.Uint("count")
+		if !ok {
+			return ResponsePack(InvalidParams, "count must be a non-negative integer")
+		}
+		if int(start)+int(count) < end {
+			end = int(start) + int(count)
+		}
+	}
+	entries = entries[start:end]
+
+	if !verbose {
+		txids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			txids = append(txids, ToReversedString(entry.Tx.Hash()))
+		}
+		return ResponsePack(Success, txids)
+	}
+
+	infos := make([]*MempoolEntryInfo, 0, len(entries))
+	for _, entry := range entries {
+		infos = append(infos, getMempoolEntryInfo(entry))
+	}
+	return ResponsePack(Success, infos)
+}
+
+func GetMempoolInfo(param Params) map[string]interface{} {
+	info := ServerNode.GetTxPoolInfo()
+	return ResponsePack(Success, MempoolInfo{
+		Size:   info.Count,
+		Bytes:  info.Bytes,
+		Usage:  info.Usage,
+		MinFee: info.MinFee.String(),
+	})
+}
+
+func GetMempoolEntry(param Params) map[string]interface{} {
+	str, ok := param.String("txid")
+	if !ok {
+		return ResponsePack(InvalidParams, "")
+	}
+	bys, err := FromReversedString(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "")
+	}
+	var hash Uint256
+	if err := hash.Deserialize(bytes.NewReader(bys)); err != nil {
+		return ResponsePack(InvalidTransaction, "")
+	}
+
+	entry := ServerNode.GetTxPoolEntry(hash)
+	if entry == nil {
+		return ResponsePack(UnknownTransaction, "transaction not in transaction pool")
+	}
+	return ResponsePack(Success, getMempoolEntryInfo(entry))
+}
+
+func getMempoolEntryInfo(entry *chain.TxPoolEntry) *MempoolEntryInfo {
+	info := &MempoolEntryInfo{
+		TxID:    ToReversedString(entry.Tx.Hash()),
+		TxType:  entry.Tx.TxType,
+		Size:    entry.Size,
+		Fee:     entry.Tx.Fee.String(),
+		FeeRate: entry.Tx.FeePerKB.String(),
+		Time:    entry.Time.Unix(),
+		Height:  entry.Height,
+	}
+
+	switch payload := entry.Tx.Payload.(type) {
+	case *PayloadTransferCrossChainAsset:
+		info.CrossChain = "transfer"
+		info.CrossChainAddresses = payload.CrossChainAddresses
+	case *PayloadWithdrawFromSideChain:
+		info.CrossChain = "withdraw"
+		for _, hash := range payload.SideChainTransactionHashes {
+			info.SideChainTransactionHashes = append(info.SideChainTransactionHashes,
+				ToReversedString(hash))
+		}
+	case *PayloadSideChainPow:
+		info.CrossChain = "sidechainpow"
+	default:
+		info.CrossChain = "none"
+	}
+	return info
+}
+
+func EstimateSmartFee(param Params) map[string]interface{} {
+	target, ok := param.Uint("target")
+	if !ok || target == 0 || target > chain.EstimateMaxTarget {
+		return ResponsePack(InvalidParams, fmt.Sprint("target must be an integer in 1-", chain.EstimateMaxTarget))
+	}
+
+	estimate, err := ServerNode.EstimateSmartFee(target)
+	if err != nil {
+		return ResponsePack(InternalError, err.Error())
 	}
-	return ResponsePack(Success, txs)
+	return ResponsePack(Success, FeeEstimateInfo{
+		FeeRate:    estimate.FeeRate.String(),
+		Blocks:     estimate.Blocks,
+		Confidence: estimate.Confidence,
+	})
 }
 
 func GetBlockInfo(block *Block, verbose bool) BlockInfo {
@@ -519,6 +656,247 @@ func SendRawTransaction(param Params) map[string]interface{} {
 	return ResponsePack(Success, ToReversedString(txn.Hash()))
 }
 
+func CreateRawTransaction(param Params) map[string]interface{} {
+	obj, ok := param["transaction"]
+	if !ok {
+		return ResponsePack(InvalidParams, "need a parameter named transaction")
+	}
+	var data []byte
+	if str, ok := obj.(string); ok {
+		data = []byte(str)
+	} else {
+		var err error
+		if data, err = json.Marshal(obj); err != nil {
+			return ResponsePack(InvalidParams, "invalid transaction")
+		}
+	}
+	var txn Transaction
+	if err := json.Unmarshal(data, &txn); err != nil {
+		return ResponsePack(InvalidTransaction, "invalid transaction: "+err.Error())
+	}
+	buf := new(bytes.Buffer)
+	if err := txn.Serialize(buf); err != nil {
+		return ResponsePack(InvalidTransaction, "transaction serialize error: "+err.Error())
+	}
+	return ResponsePack(Success, BytesToHexString(buf.Bytes()))
+}
+
+func getPST(str string) (*txbuilder.PartiallySignedTransaction, error) {
+	bys, err := HexStringToBytes(str)
+	if err != nil {
+		return nil, err
+	}
+	pst := new(txbuilder.PartiallySignedTransaction)
+	if err := pst.Deserialize(bytes.NewReader(bys)); err != nil {
+		return nil, err
+	}
+	return pst, nil
+}
+
+func getPSTHex(pst *txbuilder.PartiallySignedTransaction) string {
+	buf := new(bytes.Buffer)
+	pst.Serialize(buf)
+	return BytesToHexString(buf.Bytes())
+}
+
+func CreatePST(param Params) map[string]interface{} {
+	str, ok := param.String("data")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a string parameter named data")
+	}
+	bys, err := HexStringToBytes(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "hex string to bytes error")
+	}
+	var txn Transaction
+	if err := txn.Deserialize(bytes.NewReader(bys)); err != nil {
+		return ResponsePack(InvalidTransaction, "transaction deserialize error")
+	}
+	references, err := chain.DefaultLedger.Store.GetTxReference(&txn)
+	if err != nil {
+		return ResponsePack(UnknownTransaction, "referenced outputs not found")
+	}
+	pst, err := txbuilder.NewPartiallySignedTransaction(&txn, references)
+	if err != nil {
+		return ResponsePack(InvalidTransaction, err.Error())
+	}
+	return ResponsePack(Success, getPSTHex(pst))
+}
+
+func CombinePST(param Params) map[string]interface{} {
+	strs, ok := param.ArrayString("psts")
+	if !ok || len(strs) == 0 {
+		return ResponsePack(InvalidParams, "need an array of string parameter named psts")
+	}
+	psts := make([]*txbuilder.PartiallySignedTransaction, 0, len(strs))
+	for _, str := range strs {
+		pst, err := getPST(str)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid partially signed transaction: "+err.Error())
+		}
+		psts = append(psts, pst)
+	}
+	if err := psts[0].Combine(psts[1:]...); err != nil {
+		return ResponsePack(InvalidParams, err.Error())
+	}
+	return ResponsePack(Success, getPSTHex(psts[0]))
+}
+
+func FinalizePST(param Params) map[string]interface{} {
+	str, ok := param.String("pst")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a string parameter named pst")
+	}
+	pst, err := getPST(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid partially signed transaction: "+err.Error())
+	}
+	if err := pst.Finalize(); err != nil {
+		return ResponsePack(InvalidTransaction, err.Error())
+	}
+	txn, err := pst.Extract()
+	if err != nil {
+		return ResponsePack(InvalidTransaction, err.Error())
+	}
+	buf := new(bytes.Buffer)
+	txn.Serialize(buf)
+	return ResponsePack(Success, BytesToHexString(buf.Bytes()))
+}
+
+func TestMempoolAccept(param Params) map[string]interface{} {
+	rawTxs, ok := param.ArrayString("rawtxs")
+	if !ok || len(rawTxs) == 0 {
+		return ResponsePack(InvalidParams, "need an array of string parameter named rawtxs")
+	}
+
+	results := make([]MempoolAcceptInfo, 0, len(rawTxs))
+	for _, str := range rawTxs {
+		// a malformed transaction is rejected alone with no error code of the
+		// transaction checks, the others are still checked
+		bys, err := HexStringToBytes(str)
+		if err != nil {
+			results = append(results, MempoolAcceptInfo{
+				Reason: "hex string to bytes error",
+			})
+			continue
+		}
+		var txn Transaction
+		if err := txn.Deserialize(bytes.NewReader(bys)); err != nil {
+			results = append(results, MempoolAcceptInfo{
+				Reason: "transaction deserialize error: " + err.Error(),
+			})
+			continue
+		}
+
+		errCode := ServerNode.CheckTransaction(&txn)
+		fee, feeRate := chain.GetTxFeeRate(&txn)
+		results = append(results, MempoolAcceptInfo{
+			TxID:    ToReversedString(txn.Hash()),
+			Allowed: errCode == Success,
+			ErrCode: errCode,
+			Reason:  errCode.Message(),
+			Fee:     fee.String(),
+			FeeRate: feeRate.String(),
+		})
+	}
+	return ResponsePack(Success, results)
+}
+
+func DecodeRawTransaction(param Params) map[string]interface{} {
+	str, ok := param.String("data")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a string parameter named data")
+	}
+	bys, err := HexStringToBytes(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "hex string to bytes error")
+	}
+	var txn Transaction
+	if err := txn.Deserialize(bytes.NewReader(bys)); err != nil {
+		return ResponsePack(InvalidTransaction, "transaction deserialize error: "+err.Error())
+	}
+
+	info := DecodedTransactionInfo{TransactionInfo: GetTransactionInfo(nil, &txn)}
+	if !txn.IsCoinBaseTx() {
+		if feeMap, err := chain.GetTxFeeMap(&txn); err == nil {
+			fee := feeMap[chain.DefaultLedger.Blockchain.AssetID]
+			info.Fee = fee.String()
+			info.FeeRate = (fee * 1000 / Fixed64(txn.GetSize())).String()
+		}
+	}
+
+	// the validation stages are run in the order of the transaction pool,
+	// the pool stage only tests the transaction against the pool
+	info.Validation = ValidationInfo{Valid: true}
+	if errCode := chain.CheckTransactionSanity(chain.CheckTxOut, &txn); errCode != Success {
+		info.Validation = getValidationInfo("sanity", errCode, errCode.Message())
+	} else if errCode := chain.CheckTransactionContext(&txn); errCode != Success {
+		info.Validation = getValidationInfo("context", errCode, errCode.Message())
+	} else if errCode := ServerNode.CheckTransaction(&txn); errCode != Success {
+		info.Validation = getValidationInfo("pool", errCode, errCode.Message())
+	}
+	return ResponsePack(Success, info)
+}
+
+func DecodeBlock(param Params) map[string]interface{} {
+	str, ok := param.String("data")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a string parameter named data")
+	}
+	bys, err := HexStringToBytes(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "hex string to bytes error")
+	}
+	var block Block
+	if err := block.Deserialize(bytes.NewReader(bys)); err != nil {
+		return ResponsePack(InvalidBlock, "block deserialize error: "+err.Error())
+	}
+
+	info := DecodedBlockInfo{BlockInfo: GetBlockInfo(&block, true)}
+	hash := block.Hash()
+	if !chain.DefaultLedger.Store.IsBlockInStore(hash) {
+		// a block not in the chain has no confirmations
+		info.Confirmations = 0
+		info.NextBlockHash = ""
+		for i, tx := range block.Transactions {
+			txInfo := GetTransactionInfo(nil, tx)
+			txInfo.BlockHash = info.Hash
+			txInfo.Time = block.Header.Timestamp
+			txInfo.BlockTime = block.Header.Timestamp
+			info.Tx[i] = txInfo
+		}
+	}
+
+	info.Validation = ValidationInfo{Valid: true}
+	bc := chain.DefaultLedger.Blockchain
+	if err := chain.PowCheckBlockSanity(&block, config.Parameters.ChainParam.PowLimit, bc.TimeSource); err != nil {
+		info.Validation = getValidationInfo("sanity", InvalidBlock, err.Error())
+	} else if prevNode, ok := bc.LookupNodeInIndex(&block.Header.Previous); !ok && block.Header.Height != 0 {
+		info.Validation = getValidationInfo("context", InvalidBlock, "previous block not found")
+	} else if err := chain.PowCheckBlockContext(&block, prevNode, chain.DefaultLedger); err != nil {
+		info.Validation = getValidationInfo("context", InvalidBlock, err.Error())
+	} else if !chain.DefaultLedger.Store.IsBlockInStore(hash) {
+		// the transactions are checked against the ledger, so the block
+		// must extend the best chain
+		if block.Header.Previous != bc.CurrentBlockHash() {
+			info.Validation = getValidationInfo("transactions", InvalidBlock,
+				"block does not extend the best chain")
+		} else if err := chain.CheckBlockContext(&block); err != nil {
+			info.Validation = getValidationInfo("transactions", InvalidBlock, err.Error())
+		}
+	}
+	return ResponsePack(Success, info)
+}
+
+func getValidationInfo(stage string, errCode ErrCode, reason string) ValidationInfo {
+	return ValidationInfo{
+		Valid:   false,
+		Stage:   stage,
+		ErrCode: int(errCode),
+		Reason:  reason,
+	}
+}
+
 func GetBlockHeight(param Params) map[string]interface{} {
 	return ResponsePack(Success, chain.DefaultLedger.Blockchain.BlockHeight)
 }
@@ -532,6 +910,38 @@ func GetBlockCount(param Params) map[string]interface{} {
 	return ResponsePack(Success, chain.DefaultLedger.Blockchain.BlockHeight+1)
 }
 
+func GetBlockSubsidy(param Params) map[string]interface{} {
+	height, ok := param.Uint("height")
+	if !ok {
+		height = chain.DefaultLedger.Store.GetHeight()
+	}
+
+	params := config.Parameters.ChainParam
+	subsidy := chain.CalcBlockSubsidy(height, params)
+	rewards := chain.CalcCoinbaseRewards(subsidy, params)
+	return ResponsePack(Success, BlockSubsidyInfo{
+		Height:     height,
+		Subsidy:    subsidy.String(),
+		Foundation: rewards.Foundation.String(),
+		Miner:      rewards.Miner.String(),
+		Delegate:   rewards.Delegate.String(),
+	})
+}
+
+func GetSupply(param Params) map[string]interface{} {
+	height, ok := param.Uint("height")
+	if !ok {
+		height = chain.DefaultLedger.Store.GetHeight()
+	}
+
+	params := config.Parameters.ChainParam
+	return ResponsePack(Success, SupplyInfo{
+		Height:        height,
+		GenesisSupply: params.GenesisSupply.String(),
+		Supply:        chain.CalcSupply(height, params).String(),
+	})
+}
+
 func GetBlockHash(param Params) map[string]interface{} {
 	height, ok := param.Uint("height")
 	if !ok {
@@ -603,23 +1013,15 @@ func GetArbitratorGroupByHeight(param Params) map[string]interface{} {
 		return ResponsePack(InvalidParams, "height parameter should be a positive integer")
 	}
 
-	hash, err := chain.DefaultLedger.Store.GetBlockHash(uint32(height))
-	if err != nil {
+	if _, err := chain.DefaultLedger.Store.GetBlockHash(uint32(height)); err != nil {
 		return ResponsePack(UnknownBlock, "")
 	}
 
-	block, err := chain.DefaultLedger.Store.GetBlock(hash)
-	if err != nil {
-		return ResponsePack(InternalError, "")
-	}
-
-	arbitratorsBytes, err := config.Parameters.GetArbitrators()
+	arbitratorsBytes, index, err := chain.GetArbitratorGroup(height)
 	if err != nil {
 		return ResponsePack(InternalError, "")
 	}
 
-	index := int(block.Header.Height) % len(arbitratorsBytes)
-
 	var arbitrators []string
 	for _, data := range arbitratorsBytes {
 		arbitrators = append(arbitrators, BytesToHexString(data))
@@ -660,6 +1062,27 @@ func GetAssetByHash(param Params) map[string]interface{} {
 	return ResponsePack(Success, asset)
 }
 
+func GetAssetInfo(param Params) map[string]interface{} {
+	str, ok := param.String("hash")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a parameter named hash")
+	}
+	hashBytes, err := FromReversedString(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "")
+	}
+	var hash Uint256
+	err = hash.Deserialize(bytes.NewReader(hashBytes))
+	if err != nil {
+		return ResponsePack(InvalidAsset, "")
+	}
+	asset, err := chain.DefaultLedger.Store.GetAsset(hash)
+	if err != nil {
+		return ResponsePack(UnknownAsset, "")
+	}
+	return ResponsePack(Success, getAssetInfo(hash, asset))
+}
+
 func GetBalanceByAddr(param Params) map[string]interface{} {
 	str, ok := param.String("addr")
 	if !ok {
@@ -672,10 +1095,9 @@ func GetBalanceByAddr(param Params) map[string]interface{} {
 	}
 	unspends, err := chain.DefaultLedger.Store.GetUnspentsFromProgramHash(*programHash)
 	var balance Fixed64 = 0
-	for _, u := range unspends {
-		for _, v := range u {
-			balance = balance + v.Value
-		}
+	// balances of user issued assets are not summed up with ELA
+	for _, v := range unspends[chain.DefaultLedger.Blockchain.AssetID] {
+		balance = balance + v.Value
 	}
 	return ResponsePack(Success, balance.String())
 }
@@ -716,6 +1138,78 @@ func GetBalanceByAsset(param Params) map[string]interface{} {
 	return ResponsePack(Success, balance.String())
 }
 
+func getAssetInfo(assetID Uint256, asset *Asset) AssetInfo {
+	info := AssetInfo{
+		AssetID:     ToReversedString(assetID),
+		Name:        asset.Name,
+		Description: asset.Description,
+		Precision:   asset.Precision,
+		AssetType:   asset.AssetType,
+		RecordType:  asset.RecordType,
+	}
+	// the native asset has no supply state
+	if supply, err := chain.DefaultLedger.Store.GetAssetSupply(assetID); err == nil {
+		address, _ := supply.Controller.ToAddress()
+		info.Controller = address
+		info.MaxSupply = supply.MaxSupply.String()
+		info.Issued = supply.Issued.String()
+		info.Burned = supply.Burned.String()
+		info.Circulating = supply.Circulating().String()
+	}
+	return info
+}
+
+func ListAssets(param Params) map[string]interface{} {
+	assets := chain.DefaultLedger.Store.GetAssets()
+	result := make([]AssetInfo, 0, len(assets))
+	for assetID, asset := range assets {
+		result = append(result, getAssetInfo(assetID, asset))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].AssetID < result[j].AssetID
+	})
+	return ResponsePack(Success, result)
+}
+
+func GetAssetBalances(param Params) map[string]interface{} {
+	addr, ok := param.String("addr")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a parameter named addr")
+	}
+	programHash, err := Uint168FromAddress(addr)
+	if err != nil {
+		return ResponsePack(InvalidParams, "Invalid address: "+addr)
+	}
+	unspents, err := chain.DefaultLedger.Store.GetUnspentsFromProgramHash(*programHash)
+	if err != nil {
+		return ResponsePack(InvalidParams, "cannot get asset with program")
+	}
+
+	result := make([]AssetBalanceInfo, 0, len(unspents))
+	for assetID, utxos := range unspents {
+		if len(utxos) == 0 {
+			continue
+		}
+		asset, err := chain.DefaultLedger.Store.GetAsset(assetID)
+		if err != nil {
+			return ResponsePack(InternalError, "unknown asset "+ToReversedString(assetID))
+		}
+		var balance Fixed64
+		for _, utxo := range utxos {
+			balance += utxo.Value
+		}
+		result = append(result, AssetBalanceInfo{
+			AssetID:   ToReversedString(assetID),
+			AssetName: asset.Name,
+			Balance:   balance.String(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].AssetID < result[j].AssetID
+	})
+	return ResponsePack(Success, result)
+}
+
 func GetReceivedByAddress(param Params) map[string]interface{} {
 	address, ok := param.String("address")
 	if !ok {
@@ -927,43 +1421,484 @@ func GetExistWithdrawTransactions(param Params) map[string]interface{} {
 	return ResponsePack(Success, resultTxHashes)
 }
 
-func getPayloadInfo(p Payload) PayloadInfo {
-	switch object := p.(type) {
-	case *PayloadCoinBase:
-		obj := new(CoinbaseInfo)
-		obj.CoinbaseData = string(object.CoinbaseData)
-		return obj
-	case *PayloadRegisterAsset:
-		obj := new(RegisterAssetInfo)
-		obj.Asset = object.Asset
-		obj.Amount = object.Amount.String()
-		obj.Controller = BytesToHexString(BytesReverse(object.Controller.Bytes()))
-		return obj
-	case *PayloadSideChainPow:
-		obj := new(SideChainPowInfo)
-		obj.BlockHeight = object.BlockHeight
-		obj.SideBlockHash = object.SideBlockHash.String()
-		obj.SideGenesisHash = object.SideGenesisHash.String()
-		obj.SignedData = BytesToHexString(object.SignedData)
-		return obj
-	case *PayloadWithdrawFromSideChain:
-		obj := new(WithdrawFromSideChainInfo)
-		obj.BlockHeight = object.BlockHeight
-		obj.GenesisBlockAddress = object.GenesisBlockAddress
-		for _, hash := range object.SideChainTransactionHashes {
-			obj.SideChainTransactionHashes = append(obj.SideChainTransactionHashes, hash.String())
+// GetWithdrawStatus returns the status of the withdrawal of the side chain
+// transaction, a withdrawal in the best chain is reported confirmed even if a
+// transaction in pool holds it again.
+func GetWithdrawStatus(hash Uint256) WithdrawStatusInfo {
+	info := WithdrawStatusInfo{
+		SideChainTxHash: hash.String(),
+		Status:          chain.WithdrawUnseen.String(),
+	}
+	index, err := chain.DefaultLedger.Store.GetWithdraw(hash)
+	if err == nil && index.Status == chain.WithdrawConfirmed {
+		return getWithdrawIndexInfo(index)
+	}
+	if txn := ServerNode.GetWithdrawTransaction(hash); txn != nil {
+		if payload, ok := txn.Payload.(*PayloadWithdrawFromSideChain); ok {
+			info.Status = chain.WithdrawPooled.String()
+			info.GenesisAddress = payload.GenesisBlockAddress
+			info.TxID = ToReversedString(txn.Hash())
+			return info
 		}
-		return obj
-	case *PayloadTransferCrossChainAsset:
-		obj := new(TransferCrossChainAssetInfo)
-		obj.CrossChainAddresses = object.CrossChainAddresses
-		obj.OutputIndexes = object.OutputIndexes
-		obj.CrossChainAmounts = object.CrossChainAmounts
-		return obj
-	case *PayloadTransferAsset:
-	case *PayloadRecord:
-	}
-	return nil
+	}
+	if err == nil {
+		return getWithdrawIndexInfo(index)
+	}
+	return info
+}
+
+func getWithdrawIndexInfo(index *chain.WithdrawIndex) WithdrawStatusInfo {
+	address, _ := index.GenesisProgramHash.ToAddress()
+	info := WithdrawStatusInfo{
+		SideChainTxHash: index.SideChainTxHash.String(),
+		Status:          index.Status.String(),
+		GenesisAddress:  address,
+		TxID:            ToReversedString(index.TxID),
+		Height:          index.Height,
+	}
+	if index.Status == chain.WithdrawConfirmed {
+		info.Confirmations = chain.DefaultLedger.Store.GetHeight() - index.Height + 1
+	}
+	return info
+}
+
+// GetWithdrawTransitions returns the status of the side chain transactions
+// published by the transaction pool when the withdrawals change status.
+func GetWithdrawTransitions(transitions []*chain.WithdrawIndex) []WithdrawStatusInfo {
+	infos := make([]WithdrawStatusInfo, 0, len(transitions))
+	for _, transition := range transitions {
+		infos = append(infos, getWithdrawIndexInfo(transition))
+	}
+	return infos
+}
+
+func GetWithdrawStatusByHashes(param Params) map[string]interface{} {
+	hashes, ok := param.ArrayString("hashes")
+	if !ok {
+		return ResponsePack(InvalidParams, "hashes not found")
+	}
+	result := make([]WithdrawStatusInfo, 0, len(hashes))
+	for _, str := range hashes {
+		hashBytes, err := HexStringToBytes(str)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid side chain transaction hash")
+		}
+		hash, err := Uint256FromBytes(hashBytes)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid side chain transaction hash")
+		}
+		result = append(result, GetWithdrawStatus(*hash))
+	}
+	return ResponsePack(Success, result)
+}
+
+func ListWithdraws(param Params) map[string]interface{} {
+	address, ok := param.String("genesisaddress")
+	if !ok {
+		return ResponsePack(InvalidParams, "genesisaddress not found")
+	}
+	programHash, err := Uint168FromAddress(address)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid genesisaddress")
+	}
+
+	var start *Uint256
+	if str, ok := param.String("start"); ok {
+		hashBytes, err := FromReversedString(str)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid start")
+		}
+		start, err = Uint256FromBytes(hashBytes)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid start")
+		}
+	}
+	count, ok := param.Uint("count")
+	if _, exist := param["count"]; exist && !ok {
+		return ResponsePack(InvalidParams, "count must be a non-negative integer")
+	}
+
+	withdraws := make([]WithdrawStatusInfo, 0)
+	// the withdrawals included on chain are listed from the start key, unless
+	// the start withdrawal is only in the pool
+	inPool := false
+	if start != nil {
+		index, err := chain.DefaultLedger.Store.GetWithdraw(*start)
+		inPool = err != nil || index.GenesisProgramHash != *programHash
+	}
+	if !inPool {
+		indexes, err := chain.DefaultLedger.Store.GetWithdraws(*programHash, start, int(count))
+		if err != nil {
+			return ResponsePack(InternalError, err.Error())
+		}
+		for _, index := range indexes {
+			withdraws = append(withdraws, GetWithdrawStatus(index.SideChainTxHash))
+		}
+	}
+	if count > 0 && len(withdraws) >= int(count) {
+		return ResponsePack(Success, withdraws)
+	}
+
+	// withdrawals never included on chain follow in pool entry order
+	for _, entry := range ServerNode.GetTxPoolEntries() {
+		payload, ok := entry.Tx.Payload.(*PayloadWithdrawFromSideChain)
+		if !ok || payload.GenesisBlockAddress != address {
+			continue
+		}
+		for _, hash := range payload.SideChainTransactionHashes {
+			if inPool {
+				inPool = hash != *start
+				continue
+			}
+			if _, err := chain.DefaultLedger.Store.GetWithdraw(hash); err == nil {
+				continue
+			}
+			if count > 0 && len(withdraws) >= int(count) {
+				return ResponsePack(Success, withdraws)
+			}
+			withdraws = append(withdraws, GetWithdrawStatus(hash))
+		}
+	}
+	return ResponsePack(Success, withdraws)
+}
+
+func GetRecords(param Params) map[string]interface{} {
+	recordType, ok := param.String("type")
+	if !ok {
+		return ResponsePack(InvalidParams, "type not found")
+	}
+	from, ok := param.Uint("from")
+	if !ok {
+		from = 0
+	}
+	to, ok := param.Uint("to")
+	if !ok {
+		to = chain.DefaultLedger.Store.GetHeight()
+	}
+	if from > to {
+		return ResponsePack(InvalidParams, "from is greater than to")
+	}
+
+	records, err := chain.DefaultLedger.Store.GetRecordsByType(recordType, from, to)
+	if err != nil {
+		return ResponsePack(InternalError, err.Error())
+	}
+	recordInfos := make([]*RecordInfo, 0, len(records))
+	for _, record := range records {
+		info, err := getRecordInfo(record)
+		if err != nil {
+			return ResponsePack(InternalError, err.Error())
+		}
+		recordInfos = append(recordInfos, info)
+	}
+	return ResponsePack(Success, recordInfos)
+}
+
+func VerifyRecord(param Params) map[string]interface{} {
+	str, ok := param.String("hash")
+	if !ok {
+		return ResponsePack(InvalidParams, "hash not found")
+	}
+	hashBytes, err := HexStringToBytes(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid hash")
+	}
+	dataHash, err := Uint256FromBytes(hashBytes)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid hash")
+	}
+
+	records, err := chain.DefaultLedger.Store.GetRecordsByHash(*dataHash)
+	if err != nil {
+		return ResponsePack(InternalError, err.Error())
+	}
+	if len(records) == 0 {
+		return ResponsePack(UnknownTransaction, "record not found")
+	}
+	recordInfos := make([]*RecordInfo, 0, len(records))
+	for _, record := range records {
+		info, err := getRecordInfo(record)
+		if err != nil {
+			return ResponsePack(InternalError, err.Error())
+		}
+		recordInfos = append(recordInfos, info)
+	}
+	return ResponsePack(Success, recordInfos)
+}
+
+func ListProducers(param Params) map[string]interface{} {
+	elected := make(map[string]struct{})
+	for _, p := range chain.GetElectedProducers() {
+		elected[string(p.PublicKey)] = struct{}{}
+	}
+
+	producers := chain.DefaultLedger.Store.GetProducers()
+	result := make([]ProducerInfo, 0, len(producers))
+	for _, p := range producers {
+		var address string
+		if programHash, err := chain.GetProducerProgramHash(p.PublicKey); err == nil {
+			address, _ = programHash.ToAddress()
+		}
+		_, isElected := elected[string(p.PublicKey)]
+		result = append(result, ProducerInfo{
+			PublicKey:      BytesToHexString(p.PublicKey),
+			NickName:       p.NickName,
+			Url:            p.Url,
+			Address:        address,
+			Deposit:        fmt.Sprintf("%s:%d", ToReversedString(p.Deposit.TxID), p.Deposit.Index),
+			DepositAmount:  p.DepositAmount.String(),
+			RegisterHeight: p.RegisterHeight,
+			CancelHeight:   p.CancelHeight,
+			Votes:          p.Votes.String(),
+			Active:         p.IsActive(),
+			Elected:        isElected,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RegisterHeight < result[j].RegisterHeight ||
+			result[i].RegisterHeight == result[j].RegisterHeight && result[i].PublicKey < result[j].PublicKey
+	})
+	return ResponsePack(Success, result)
+}
+
+func GetVotes(param Params) map[string]interface{} {
+	str, ok := param.String("publickey")
+	if !ok {
+		return ResponsePack(InvalidParams, "publickey not found")
+	}
+	publicKey, err := HexStringToBytes(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid publickey")
+	}
+	producer, err := chain.DefaultLedger.Store.GetProducer(publicKey)
+	if err != nil {
+		return ResponsePack(UnknownTransaction, "producer not found")
+	}
+
+	info := VotesInfo{PublicKey: str, Votes: producer.Votes.String()}
+	for i, p := range chain.GetElectedProducers() {
+		if bytes.Equal(p.PublicKey, publicKey) {
+			info.Rank = i + 1
+			info.Elected = true
+			break
+		}
+	}
+	return ResponsePack(Success, info)
+}
+
+func getSideChainInfo(s *chain.SideChain) SideChainInfo {
+	return SideChainInfo{
+		Name:                    s.Name,
+		GenesisHash:             ToReversedString(s.GenesisHash),
+		GenesisAddress:          s.GenesisAddress,
+		MinArbitratorSignatures: s.MinArbitratorSignatures,
+		RegisterHeight:          s.RegisterHeight,
+		Deposited:               s.Deposited.String(),
+		Withdrawn:               s.Withdrawn.String(),
+		Locked:                  s.Locked().String(),
+	}
+}
+
+func ListSideChains(param Params) map[string]interface{} {
+	sideChains := chain.DefaultLedger.Store.GetSideChains()
+	result := make([]SideChainInfo, 0, len(sideChains))
+	for _, s := range sideChains {
+		result = append(result, getSideChainInfo(s))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RegisterHeight < result[j].RegisterHeight ||
+			result[i].RegisterHeight == result[j].RegisterHeight && result[i].GenesisHash < result[j].GenesisHash
+	})
+	return ResponsePack(Success, result)
+}
+
+func GetSideChainInfo(param Params) map[string]interface{} {
+	var sideChain *chain.SideChain
+	if str, ok := param.String("genesishash"); ok {
+		hashBytes, err := FromReversedString(str)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid genesishash")
+		}
+		genesisHash, err := Uint256FromBytes(hashBytes)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid genesishash")
+		}
+		sideChain, err = chain.DefaultLedger.Store.GetSideChain(*genesisHash)
+		if err != nil {
+			return ResponsePack(UnknownTransaction, "side chain not found")
+		}
+	} else if address, ok := param.String("genesisaddress"); ok {
+		programHash, err := Uint168FromAddress(address)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid genesisaddress")
+		}
+		sideChain, err = chain.DefaultLedger.Store.GetSideChainByProgramHash(*programHash)
+		if err != nil {
+			return ResponsePack(UnknownTransaction, "side chain not found")
+		}
+	} else {
+		return ResponsePack(InvalidParams, "genesishash or genesisaddress not found")
+	}
+	return ResponsePack(Success, getSideChainInfo(sideChain))
+}
+
+func GetSideChainPow(param Params) map[string]interface{} {
+	str, ok := param.String("genesishash")
+	if !ok {
+		return ResponsePack(InvalidParams, "genesishash not found")
+	}
+	hashBytes, err := FromReversedString(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid genesishash")
+	}
+	genesisHash, err := Uint256FromBytes(hashBytes)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid genesishash")
+	}
+	from, ok := param.Uint("from")
+	if !ok {
+		return ResponsePack(InvalidParams, "from not found")
+	}
+	to, ok := param.Uint("to")
+	if !ok {
+		to = from
+	}
+	if from > to {
+		return ResponsePack(InvalidParams, "from is greater than to")
+	}
+	if to-from >= MAX_SIDECHAINPOW_RANGE {
+		return ResponsePack(InvalidParams, fmt.Sprintf("range is greater than %d", MAX_SIDECHAINPOW_RANGE))
+	}
+
+	anchors, err := chain.DefaultLedger.Store.GetSideChainPows(*genesisHash, from, to)
+	if err != nil {
+		return ResponsePack(InternalError, err.Error())
+	}
+	result := make([]SideChainPowAnchorInfo, 0, len(anchors))
+	for _, anchor := range anchors {
+		blockHash, err := chain.DefaultLedger.Store.GetBlockHash(anchor.Height)
+		if err != nil {
+			return ResponsePack(UnknownBlock, "")
+		}
+		result = append(result, SideChainPowAnchorInfo{
+			SideGenesisHash: ToReversedString(anchor.SideGenesisHash),
+			SideBlockHash:   ToReversedString(anchor.SideBlockHash),
+			SideBlockHeight: anchor.SideBlockHeight,
+			TxID:            ToReversedString(anchor.TxID),
+			BlockHash:       ToReversedString(blockHash),
+			Height:          anchor.Height,
+		})
+	}
+	return ResponsePack(Success, result)
+}
+
+func GetCrossChainProof(param Params) map[string]interface{} {
+	str, ok := param.String("txid")
+	if !ok {
+		return ResponsePack(InvalidParams, "txid not found")
+	}
+	hashBytes, err := FromReversedString(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid txid")
+	}
+	txId, err := Uint256FromBytes(hashBytes)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid txid")
+	}
+
+	txn, height, err := chain.DefaultLedger.Store.GetTransaction(*txId)
+	if err != nil {
+		return ResponsePack(UnknownTransaction, "")
+	}
+	if txn.TxType != TransferCrossChainAsset {
+		return ResponsePack(InvalidParams, "not a transfer cross chain asset transaction")
+	}
+	blockHash, err := chain.DefaultLedger.Store.GetBlockHash(height)
+	if err != nil {
+		return ResponsePack(UnknownBlock, "")
+	}
+	block, err := chain.DefaultLedger.Store.GetBlock(blockHash)
+	if err != nil {
+		return ResponsePack(UnknownBlock, "")
+	}
+
+	proof, err := bloom.NewMerkleProof(block, *txId)
+	if err != nil {
+		return ResponsePack(InternalError, err.Error())
+	}
+	buf := new(bytes.Buffer)
+	if err := proof.Serialize(buf); err != nil {
+		return ResponsePack(InternalError, err.Error())
+	}
+
+	payload, _ := getPayloadInfo(txn.TxType, txn.Payload).(*TransferCrossChainAssetInfo)
+	return ResponsePack(Success, CrossChainProofInfo{
+		TxID:        ToReversedString(*txId),
+		Transaction: GetTransactionInfo(&block.Header, txn),
+		Payload:     payload,
+		BlockHash:   ToReversedString(blockHash),
+		Height:      height,
+		Proof:       BytesToHexString(buf.Bytes()),
+	})
+}
+
+// GetSideChainPowAnchors returns the side chain blocks anchored by the block.
+func GetSideChainPowAnchors(block *Block) []SideChainPowAnchorInfo {
+	anchors := make([]SideChainPowAnchorInfo, 0)
+	blockHash := block.Hash()
+	for _, txn := range block.Transactions {
+		payload, ok := txn.Payload.(*PayloadSideChainPow)
+		if !ok {
+			continue
+		}
+		anchors = append(anchors, SideChainPowAnchorInfo{
+			SideGenesisHash: ToReversedString(payload.SideGenesisHash),
+			SideBlockHash:   ToReversedString(payload.SideBlockHash),
+			SideBlockHeight: payload.BlockHeight,
+			TxID:            ToReversedString(txn.Hash()),
+			BlockHash:       ToReversedString(blockHash),
+			Height:          block.Header.Height,
+		})
+	}
+	return anchors
+}
+
+// getRecordInfo returns the record with the proof of its transaction included
+// in the block.
+func getRecordInfo(record *chain.RecordIndex) (*RecordInfo, error) {
+	txn, _, err := chain.DefaultLedger.Store.GetTransaction(record.TxID)
+	if err != nil {
+		return nil, err
+	}
+	payload, ok := txn.Payload.(*PayloadRecord)
+	if !ok {
+		return nil, fmt.Errorf("transaction %s is not a record", ToReversedString(record.TxID))
+	}
+	blockHash, err := chain.DefaultLedger.Store.GetBlockHash(record.Height)
+	if err != nil {
+		return nil, err
+	}
+	block, err := chain.DefaultLedger.Store.GetBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := bloom.NewMerkleProof(block, record.TxID)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err := proof.Serialize(buf); err != nil {
+		return nil, err
+	}
+	return &RecordInfo{
+		RecordType: record.RecordType,
+		RecordData: BytesToHexString(payload.RecordData),
+		DataHash:   BytesToHexString(record.DataHash.Bytes()),
+		TxID:       ToReversedString(record.TxID),
+		BlockHash:  ToReversedString(blockHash),
+		Height:     record.Height,
+		Proof:      BytesToHexString(buf.Bytes()),
+	}, nil
 }
 
 func VerifyAndSendTx(txn *Transaction) ErrCode {
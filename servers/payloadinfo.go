@@ -0,0 +1,184 @@
+package servers
+
+import (
+	"fmt"
+
+	. "github.com/wuyazero/Elastos.ELA/core"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+// PayloadInfoFunc converts the payload of a transaction to its info shown by
+// the RPC servers.
+type PayloadInfoFunc func(payload Payload) PayloadInfo
+
+var payloadInfoFuncs = make(map[TransactionType]PayloadInfoFunc)
+
+// RegisterPayloadInfo registers the payload info converter of a transaction
+// type, the payload of a type without a converter is shown as null. It is
+// meant to be called by init functions, and panics if the type is already
+// registered.
+func RegisterPayloadInfo(txType TransactionType, f PayloadInfoFunc) {
+	if _, ok := payloadInfoFuncs[txType]; ok {
+		panic(fmt.Sprintf("servers: RegisterPayloadInfo called twice for transaction type %d", txType))
+	}
+	payloadInfoFuncs[txType] = f
+}
+
+func getPayloadInfo(txType TransactionType, p Payload) PayloadInfo {
+	f, ok := payloadInfoFuncs[txType]
+	if !ok || p == nil {
+		return nil
+	}
+	return f(p)
+}
+
+func init() {
+	RegisterPayloadInfo(CoinBase, getCoinbaseInfo)
+	RegisterPayloadInfo(RegisterAsset, getRegisterAssetInfo)
+	RegisterPayloadInfo(SideChainPow, getSideChainPowInfo)
+	RegisterPayloadInfo(WithdrawFromSideChain, getWithdrawFromSideChainInfo)
+	RegisterPayloadInfo(TransferCrossChainAsset, getTransferCrossChainAssetInfo)
+	RegisterPayloadInfo(MintAsset, getAssetSupplyInfo)
+	RegisterPayloadInfo(BurnAsset, getAssetSupplyInfo)
+	RegisterPayloadInfo(RegisterProducer, getRegisterProducerInfo)
+	RegisterPayloadInfo(CancelProducer, getCancelProducerInfo)
+	RegisterPayloadInfo(VoteProducer, getVoteProducerInfo)
+	RegisterPayloadInfo(UpdateArbitrators, getUpdateArbitratorsInfo)
+	RegisterPayloadInfo(RegisterSideChain, getRegisterSideChainInfo)
+}
+
+func getCoinbaseInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadCoinBase)
+	if !ok {
+		return nil
+	}
+	obj := new(CoinbaseInfo)
+	obj.CoinbaseData = string(object.CoinbaseData)
+	return obj
+}
+
+func getRegisterAssetInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadRegisterAsset)
+	if !ok {
+		return nil
+	}
+	obj := new(RegisterAssetInfo)
+	obj.Asset = object.Asset
+	obj.Amount = object.Amount.String()
+	obj.Controller = BytesToHexString(BytesReverse(object.Controller.Bytes()))
+	return obj
+}
+
+func getSideChainPowInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadSideChainPow)
+	if !ok {
+		return nil
+	}
+	obj := new(SideChainPowInfo)
+	obj.BlockHeight = object.BlockHeight
+	obj.SideBlockHash = object.SideBlockHash.String()
+	obj.SideGenesisHash = object.SideGenesisHash.String()
+	obj.SignedData = BytesToHexString(object.SignedData)
+	return obj
+}
+
+func getWithdrawFromSideChainInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadWithdrawFromSideChain)
+	if !ok {
+		return nil
+	}
+	obj := new(WithdrawFromSideChainInfo)
+	obj.BlockHeight = object.BlockHeight
+	obj.GenesisBlockAddress = object.GenesisBlockAddress
+	for _, hash := range object.SideChainTransactionHashes {
+		obj.SideChainTransactionHashes = append(obj.SideChainTransactionHashes, hash.String())
+	}
+	return obj
+}
+
+func getTransferCrossChainAssetInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadTransferCrossChainAsset)
+	if !ok {
+		return nil
+	}
+	obj := new(TransferCrossChainAssetInfo)
+	obj.CrossChainAddresses = object.CrossChainAddresses
+	obj.OutputIndexes = object.OutputIndexes
+	obj.CrossChainAmounts = object.CrossChainAmounts
+	return obj
+}
+
+func getAssetSupplyInfo(p Payload) PayloadInfo {
+	obj := new(AssetSupplyInfo)
+	switch object := p.(type) {
+	case *PayloadMintAsset:
+		obj.AssetID = ToReversedString(object.AssetID)
+		obj.Amount = object.Amount.String()
+	case *PayloadBurnAsset:
+		obj.AssetID = ToReversedString(object.AssetID)
+		obj.Amount = object.Amount.String()
+	default:
+		return nil
+	}
+	return obj
+}
+
+func getRegisterProducerInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadRegisterProducer)
+	if !ok {
+		return nil
+	}
+	obj := new(RegisterProducerInfo)
+	obj.PublicKey = BytesToHexString(object.PublicKey)
+	obj.NickName = object.NickName
+	obj.Url = object.Url
+	return obj
+}
+
+func getCancelProducerInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadCancelProducer)
+	if !ok {
+		return nil
+	}
+	obj := new(CancelProducerInfo)
+	obj.PublicKey = BytesToHexString(object.PublicKey)
+	return obj
+}
+
+func getVoteProducerInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadVoteProducer)
+	if !ok {
+		return nil
+	}
+	obj := new(VoteProducerInfo)
+	for _, candidate := range object.Candidates {
+		obj.Candidates = append(obj.Candidates, BytesToHexString(candidate))
+	}
+	return obj
+}
+
+func getUpdateArbitratorsInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadUpdateArbitrators)
+	if !ok {
+		return nil
+	}
+	obj := new(UpdateArbitratorsInfo)
+	for _, arbitrator := range object.Arbitrators {
+		obj.Arbitrators = append(obj.Arbitrators, BytesToHexString(arbitrator))
+	}
+	return obj
+}
+
+func getRegisterSideChainInfo(p Payload) PayloadInfo {
+	object, ok := p.(*PayloadRegisterSideChain)
+	if !ok {
+		return nil
+	}
+	obj := new(RegisterSideChainInfo)
+	obj.GenesisHash = ToReversedString(object.GenesisHash)
+	obj.GenesisAddress = object.GenesisAddress
+	obj.Name = object.Name
+	obj.MinArbitratorSignatures = object.MinArbitratorSignatures
+	return obj
+}
@@ -0,0 +1,350 @@
+package txbuilder
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/wuyazero/Elastos.ELA/blockchain"
+	. "github.com/wuyazero/Elastos.ELA/core"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
+)
+
+const (
+	// DefaultDustThreshold is the value below which a change output is not
+	// worth to be created, the change is left to the fee instead.
+	DefaultDustThreshold Fixed64 = 1000
+
+	// inputSize is the serialized size of an input.
+	inputSize = 32 + 2 + 4
+	// outputSize is the serialized size of an output.
+	outputSize = 32 + 8 + 4 + 21
+	// nonceSize is the size of the Nonce attribute data.
+	nonceSize = 8
+	// countSlack is the extra size of the input, output and program counts
+	// when they grow over a single byte var int.
+	countSlack = 3 * (5 - 1)
+)
+
+// UnspentSource provides the unspent outputs of a program hash, it is
+// implemented by blockchain.IChainStore.
+type UnspentSource interface {
+	GetUnspentFromProgramHash(programHash Uint168, assetid Uint256) ([]*blockchain.UTXO, error)
+}
+
+// Builder builds a transaction spending the coins of the accounts added to it.
+// The builder spends a single asset and pays the fee with it, so the fee rate
+// and the minimum fee must be zero when the asset is not ELA.
+type Builder struct {
+	// Version is the version of the transaction, the default version keeps
+	// the format of the transactions before versions were introduced.
+	Version TransactionVersion
+	// TxType and Payload are the type and payload of the transaction, which
+	// are TransferAsset by default.
+	TxType         TransactionType
+	PayloadVersion byte
+	Payload        Payload
+
+	// Attributes are the attributes of the transaction, a random Nonce
+	// attribute is added if there is none.
+	Attributes []*Attribute
+	// LockTime is the lock time of the transaction, the inputs are not final
+	// if it is not zero so the lock time is enforced.
+	LockTime uint32
+
+	// ChangeProgramHash receives the change of the transaction.
+	ChangeProgramHash Uint168
+	// FeeRate is the fee rate target in sela per KB.
+	FeeRate Fixed64
+	// MinFee is the minimum fee of the transaction.
+	MinFee Fixed64
+	// DustThreshold is the value below which the change is left to the fee.
+	DustThreshold Fixed64
+	// CoinSelector chooses the coins to spend.
+	CoinSelector CoinSelector
+	// KeepOutputOrder disables sorting the inputs and outputs, which is
+	// required when the payload references outputs by index.
+	KeepOutputOrder bool
+
+	assetID  Uint256
+	outputs  []*Output
+	coins    []*Coin
+	accounts map[Uint168][]byte
+}
+
+// New creates a builder of a TransferAsset transaction spending the asset.
+func New(assetID Uint256) *Builder {
+	return &Builder{
+		TxType:        TransferAsset,
+		Payload:       new(PayloadTransferAsset),
+		DustThreshold: DefaultDustThreshold,
+		CoinSelector:  &BranchAndBound{Fallback: LargestFirst{}},
+		assetID:       assetID,
+		accounts:      make(map[Uint168][]byte),
+	}
+}
+
+// AddOutput adds an output paying the value to the program hash.
+func (b *Builder) AddOutput(programHash Uint168, value Fixed64) {
+	b.outputs = append(b.outputs, &Output{
+		AssetID:     b.assetID,
+		Value:       value,
+		ProgramHash: programHash,
+	})
+}
+
+// AddAccount adds the program code which can spend the coins of its program
+// hash, and returns the program hash.
+func (b *Builder) AddAccount(code []byte) (*Uint168, error) {
+	if _, err := signatureCount(code); err != nil {
+		return nil, err
+	}
+	programHash, err := GetProgramHash(code)
+	if err != nil {
+		return nil, err
+	}
+	b.accounts[*programHash] = code
+	return programHash, nil
+}
+
+// AddCoin adds a coin to be selected, the account of the coin must be added.
+func (b *Builder) AddCoin(coin *Coin) error {
+	if _, ok := b.accounts[coin.ProgramHash]; !ok {
+		return fmt.Errorf("account of coin %s not found", coin.ProgramHash.String())
+	}
+	b.coins = append(b.coins, coin)
+	return nil
+}
+
+// AddUnspents adds the account of the code and all of its unspent outputs.
+func (b *Builder) AddUnspents(source UnspentSource, code []byte) error {
+	programHash, err := b.AddAccount(code)
+	if err != nil {
+		return err
+	}
+	utxos, err := source.GetUnspentFromProgramHash(*programHash, b.assetID)
+	if err != nil {
+		return err
+	}
+	for _, utxo := range utxos {
+		b.coins = append(b.coins, &Coin{
+			OutPoint:    *NewOutPoint(utxo.TxId, uint16(utxo.Index)),
+			Value:       utxo.Value,
+			ProgramHash: *programHash,
+		})
+	}
+	return nil
+}
+
+// Build selects the coins and returns the unsigned transaction, which has a
+// program of empty parameter for each spending account to be signed.
+func (b *Builder) Build() (*Transaction, error) {
+	if len(b.outputs) == 0 {
+		return nil, errors.New("no outputs to build")
+	}
+	var amount Fixed64
+	for _, output := range b.outputs {
+		if output.Value <= 0 {
+			return nil, errors.New("invalid output value")
+		}
+		amount += output.Value
+	}
+
+	txn := &Transaction{
+		Version:        b.Version,
+		TxType:         b.TxType,
+		PayloadVersion: b.PayloadVersion,
+		Payload:        b.Payload,
+		Attributes:     b.attributes(),
+		LockTime:       b.LockTime,
+	}
+	txn.Outputs = append(txn.Outputs, b.outputs...)
+
+	// the fee of each coin covers the input and the program of its account
+	var programSize int
+	for _, code := range b.accounts {
+		size, err := estimateProgramSize(code)
+		if err != nil {
+			return nil, err
+		}
+		if size > programSize {
+			programSize = size
+		}
+	}
+	baseSize, err := EstimateSize(txn)
+	if err != nil {
+		return nil, err
+	}
+	target := &SelectionTarget{
+		Amount:     amount,
+		Fee:        b.fee(baseSize + countSlack),
+		InputFee:   b.feeOfSize(inputSize + programSize),
+		ChangeCost: b.feeOfSize(outputSize) + b.DustThreshold,
+	}
+	selected, err := b.CoinSelector.SelectCoins(b.coins, target)
+	if err != nil {
+		return nil, err
+	}
+
+	sequence := uint32(blockchain.MaxTxInSequenceNum)
+	if b.LockTime != 0 {
+		sequence = math.MaxUint32 - 1
+	}
+	var total Fixed64
+	used := make(map[Uint168]struct{})
+	for _, coin := range selected {
+		txn.Inputs = append(txn.Inputs, &Input{Previous: coin.OutPoint, Sequence: sequence})
+		total += coin.Value
+		used[coin.ProgramHash] = struct{}{}
+	}
+	for programHash := range used {
+		txn.Programs = append(txn.Programs, &Program{Code: b.accounts[programHash], Parameter: []byte{}})
+	}
+	if err := blockchain.SortPrograms(txn.Programs); err != nil {
+		return nil, err
+	}
+
+	// add the change if it is worth more than the dust threshold
+	size, err := EstimateSize(txn)
+	if err != nil {
+		return nil, err
+	}
+	fee := b.fee(size)
+	if total < amount+fee {
+		return nil, ErrInsufficientFunds
+	}
+	changeFee := b.fee(size+outputSize) - fee
+	if change := total - amount - fee - changeFee; change > b.DustThreshold {
+		if b.ChangeProgramHash == (Uint168{}) {
+			return nil, errors.New("change program hash not set")
+		}
+		txn.Outputs = append(txn.Outputs, &Output{
+			AssetID:     b.assetID,
+			Value:       change,
+			ProgramHash: b.ChangeProgramHash,
+		})
+	}
+
+	if !b.KeepOutputOrder {
+		sortInputs(txn.Inputs)
+		sortOutputs(txn.Outputs)
+	}
+	return txn, nil
+}
+
+func (b *Builder) attributes() []*Attribute {
+	attributes := make([]*Attribute, 0, len(b.Attributes)+1)
+	hasNonce := false
+	for _, attr := range b.Attributes {
+		if attr.Usage == Nonce {
+			hasNonce = true
+		}
+		attributes = append(attributes, attr)
+	}
+	if !hasNonce {
+		nonce := make([]byte, nonceSize)
+		rand.Read(nonce)
+		attr := NewAttribute(Nonce, nonce)
+		attributes = append(attributes, &attr)
+	}
+	return attributes
+}
+
+// fee returns the fee of a transaction of the size, which is not less than
+// the minimum fee.
+func (b *Builder) fee(size int) Fixed64 {
+	fee := b.feeOfSize(size)
+	if fee < b.MinFee {
+		return b.MinFee
+	}
+	return fee
+}
+
+// feeOfSize returns the fee of the size at the fee rate, rounded up.
+func (b *Builder) feeOfSize(size int) Fixed64 {
+	return (b.FeeRate*Fixed64(size) + 999) / 1000
+}
+
+// EstimateSize returns the size of the transaction once its programs are
+// signed, the parameters of the programs are replaced by the signatures.
+func EstimateSize(txn *Transaction) (int, error) {
+	buf := new(bytes.Buffer)
+	if err := txn.SerializeUnsigned(buf); err != nil {
+		return 0, err
+	}
+	size := buf.Len() + varIntSize(len(txn.Programs))
+	for _, program := range txn.Programs {
+		programSize, err := estimateProgramSize(program.Code)
+		if err != nil {
+			return 0, err
+		}
+		size += programSize
+	}
+	return size, nil
+}
+
+// estimateProgramSize returns the size of the program of the code with the
+// parameter signed.
+func estimateProgramSize(code []byte) (int, error) {
+	count, err := signatureCount(code)
+	if err != nil {
+		return 0, err
+	}
+	parameterSize := count * crypto.SignatureScriptLength
+	if IsHTLCScript(code) {
+		parameterSize += 1 + HTLCMaxPreimageLength
+	}
+	return varIntSize(len(code)) + len(code) + varIntSize(parameterSize) + parameterSize, nil
+}
+
+// signatureCount returns the number of signatures required by the code, the
+// multisig code is signed by M signatures.
+func signatureCount(code []byte) (int, error) {
+	if IsHTLCScript(code) {
+		return 1, nil
+	}
+	if len(code) == 0 {
+		return 0, errors.New("empty program code")
+	}
+	switch code[len(code)-1] {
+	case STANDARD:
+		return 1, nil
+	case MULTISIG:
+		return int(code[0]) - crypto.PUSH1 + 1, nil
+	}
+	return 0, fmt.Errorf("unsupported program code %x", code)
+}
+
+func varIntSize(n int) int {
+	switch {
+	case n < 0xfd:
+		return 1
+	case n <= math.MaxUint16:
+		return 3
+	case n <= math.MaxUint32:
+		return 5
+	}
+	return 9
+}
+
+// sortInputs sorts the inputs by outpoint.
+func sortInputs(inputs []*Input) {
+	sort.Slice(inputs, func(i, j int) bool {
+		return compareOutPoint(&inputs[i].Previous, &inputs[j].Previous) < 0
+	})
+}
+
+// sortOutputs sorts the outputs by value and then by program hash.
+func sortOutputs(outputs []*Output) {
+	sort.Slice(outputs, func(i, j int) bool {
+		if outputs[i].Value != outputs[j].Value {
+			return outputs[i].Value < outputs[j].Value
+		}
+		return bytes.Compare(outputs[i].ProgramHash[:], outputs[j].ProgramHash[:]) < 0
+	})
+}
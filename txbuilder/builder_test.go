@@ -0,0 +1,166 @@
+package txbuilder
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/wuyazero/Elastos.ELA/blockchain"
+	"github.com/wuyazero/Elastos.ELA/core"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
+)
+
+type testSource map[common.Uint168][]*blockchain.UTXO
+
+func (s testSource) GetUnspentFromProgramHash(programHash common.Uint168, assetid common.Uint256) ([]*blockchain.UTXO, error) {
+	return s[programHash], nil
+}
+
+type testAccount struct {
+	private     []byte
+	public      *crypto.PublicKey
+	code        []byte
+	programHash common.Uint168
+}
+
+func newTestAccount(t *testing.T) *testAccount {
+	private, public, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	code, err := crypto.CreateStandardRedeemScript(public)
+	assert.NoError(t, err)
+	programHash, err := crypto.ToProgramHash(code)
+	assert.NoError(t, err)
+	return &testAccount{private: private, public: public, code: code, programHash: *programHash}
+}
+
+func newTestUTXOs(values ...common.Fixed64) []*blockchain.UTXO {
+	utxos := make([]*blockchain.UTXO, 0, len(values))
+	for i, value := range values {
+		var txID common.Uint256
+		txID[0], txID[1] = byte(i), 0xff
+		utxos = append(utxos, &blockchain.UTXO{TxId: txID, Index: uint32(i), Value: value})
+	}
+	return utxos
+}
+
+func getBalance(txn *core.Transaction, programHash common.Uint168) common.Fixed64 {
+	var balance common.Fixed64
+	for _, output := range txn.Outputs {
+		if output.ProgramHash == programHash {
+			balance += output.Value
+		}
+	}
+	return balance
+}
+
+func TestBuilder_Build(t *testing.T) {
+	var assetID common.Uint256
+	sender := newTestAccount(t)
+	recipient := newTestAccount(t)
+	source := testSource{sender.programHash: newTestUTXOs(100000, 300000, 50000)}
+
+	builder := New(assetID)
+	builder.ChangeProgramHash = sender.programHash
+	builder.FeeRate = 1000
+	builder.MinFee = 100
+	assert.NoError(t, builder.AddUnspents(source, sender.code))
+	builder.AddOutput(recipient.programHash, 150000)
+
+	txn, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(txn.Inputs))
+	assert.Equal(t, uint32(math.MaxUint32), txn.Inputs[0].Sequence)
+	assert.Equal(t, 2, len(txn.Outputs))
+	assert.Equal(t, common.Fixed64(150000), getBalance(txn, recipient.programHash))
+	assert.Equal(t, 1, len(txn.Attributes))
+	assert.Equal(t, core.Nonce, txn.Attributes[0].Usage)
+
+	// the program is a placeholder to be signed
+	assert.Equal(t, 1, len(txn.Programs))
+	assert.Equal(t, sender.code, txn.Programs[0].Code)
+	assert.Equal(t, 0, len(txn.Programs[0].Parameter))
+
+	// the fee pays the fee rate of the signed size
+	size, err := EstimateSize(txn)
+	assert.NoError(t, err)
+	fee := 300000 - txn.Outputs[0].Value - txn.Outputs[1].Value
+	assert.Equal(t, common.Fixed64(size), fee)
+
+	buf := new(bytes.Buffer)
+	txn.SerializeUnsigned(buf)
+	signature, err := crypto.Sign(sender.private, buf.Bytes())
+	assert.NoError(t, err)
+	txn.Programs[0].Parameter = append([]byte{byte(len(signature))}, signature...)
+	assert.Equal(t, size, txn.GetSize())
+
+	// the outputs are sorted by value
+	assert.True(t, txn.Outputs[0].Value <= txn.Outputs[1].Value)
+
+	// the dust change is left to the fee
+	builder = New(assetID)
+	builder.ChangeProgramHash = sender.programHash
+	builder.MinFee = 100
+	assert.NoError(t, builder.AddUnspents(source, sender.code))
+	builder.AddOutput(recipient.programHash, 50000-100-DefaultDustThreshold)
+	txn, err = builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(txn.Inputs))
+	assert.Equal(t, 1, len(txn.Outputs))
+
+	// the lock time is enforced by the sequence
+	builder.LockTime = 100
+	txn, err = builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(math.MaxUint32-1), txn.Inputs[0].Sequence)
+
+	// insufficient funds
+	builder.AddOutput(recipient.programHash, 1000000)
+	_, err = builder.Build()
+	assert.Equal(t, ErrInsufficientFunds, err)
+}
+
+func TestBuilder_Multisig(t *testing.T) {
+	var assetID common.Uint256
+	var publicKeys []*crypto.PublicKey
+	for i := 0; i < 3; i++ {
+		_, public, err := crypto.GenerateKeyPair()
+		assert.NoError(t, err)
+		publicKeys = append(publicKeys, public)
+	}
+	code, err := crypto.CreateMultiSignRedeemScript(2, publicKeys)
+	assert.NoError(t, err)
+	recipient := newTestAccount(t)
+
+	builder := New(assetID)
+	builder.FeeRate = 1000
+	programHash, err := builder.AddAccount(code)
+	assert.NoError(t, err)
+	builder.ChangeProgramHash = *programHash
+	coins := newCoins(200000, 100000)
+	for _, coin := range coins {
+		coin.ProgramHash = *programHash
+		assert.NoError(t, builder.AddCoin(coin))
+	}
+	builder.AddOutput(recipient.programHash, 250000)
+
+	// coins of unknown accounts are rejected
+	assert.Error(t, builder.AddCoin(&Coin{ProgramHash: recipient.programHash, Value: 1}))
+
+	txn, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(txn.Inputs))
+	assert.Equal(t, 1, len(txn.Programs))
+	assert.Equal(t, code, txn.Programs[0].Code)
+
+	// the inputs are sorted by outpoint
+	assert.True(t, compareOutPoint(&txn.Inputs[0].Previous, &txn.Inputs[1].Previous) < 0)
+
+	// the size of the two signatures is estimated
+	size, err := EstimateSize(txn)
+	assert.NoError(t, err)
+	txn.Programs[0].Parameter = make([]byte, 2*crypto.SignatureScriptLength)
+	assert.Equal(t, size, txn.GetSize())
+}
@@ -0,0 +1,182 @@
+package txbuilder
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	. "github.com/wuyazero/Elastos.ELA/core"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+// DefaultBranchAndBoundTries is the default number of search steps of the
+// branch and bound coin selection.
+const DefaultBranchAndBoundTries = 100000
+
+var (
+	// ErrInsufficientFunds is returned when the coins are not enough to pay
+	// the outputs and the fee.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	// ErrNoExactMatch is returned by the branch and bound coin selection when
+	// no combination of coins needs no change.
+	ErrNoExactMatch = errors.New("no exact match of coins found")
+)
+
+// Coin is an unspent output can be spent by the builder.
+type Coin struct {
+	OutPoint    OutPoint
+	Value       Fixed64
+	ProgramHash Uint168
+}
+
+// SelectionTarget is the amount the selected coins must pay.
+type SelectionTarget struct {
+	// Amount is the total value of the outputs.
+	Amount Fixed64
+	// Fee is the fee of the transaction without inputs and change.
+	Fee Fixed64
+	// InputFee is the fee added by each selected coin.
+	InputFee Fixed64
+	// ChangeCost is the excess value below which no change is added, it is
+	// the fee of the change output plus the dust threshold.
+	ChangeCost Fixed64
+}
+
+// required returns the value the given number of coins must sum up to.
+func (t *SelectionTarget) required(coins int) Fixed64 {
+	return t.Amount + t.Fee + t.InputFee*Fixed64(coins)
+}
+
+// CoinSelector chooses the coins to spend from the available coins.
+type CoinSelector interface {
+	SelectCoins(coins []*Coin, target *SelectionTarget) ([]*Coin, error)
+}
+
+// sortCoins sorts the coins by value descending, coins of the same value are
+// sorted by outpoint so the selection is deterministic.
+func sortCoins(coins []*Coin) []*Coin {
+	sorted := make([]*Coin, len(coins))
+	copy(sorted, coins)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Value != sorted[j].Value {
+			return sorted[i].Value > sorted[j].Value
+		}
+		return compareOutPoint(&sorted[i].OutPoint, &sorted[j].OutPoint) < 0
+	})
+	return sorted
+}
+
+func compareOutPoint(a, b *OutPoint) int {
+	if c := bytes.Compare(a.TxID[:], b.TxID[:]); c != 0 {
+		return c
+	}
+	if a.Index != b.Index {
+		if a.Index < b.Index {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// LargestFirst selects the coins of largest value until the target is paid.
+type LargestFirst struct{}
+
+func (LargestFirst) SelectCoins(coins []*Coin, target *SelectionTarget) ([]*Coin, error) {
+	var sum Fixed64
+	selected := make([]*Coin, 0)
+	for _, coin := range sortCoins(coins) {
+		selected = append(selected, coin)
+		sum += coin.Value
+		if sum >= target.required(len(selected)) {
+			return selected, nil
+		}
+	}
+	return nil, ErrInsufficientFunds
+}
+
+// BranchAndBound searches the combination of coins which pays the target with
+// the least excess value not worth a change output, so the transaction needs
+// no change. The Fallback selector is used if no such combination is found.
+type BranchAndBound struct {
+	// MaxTries is the maximum number of search steps, zero means
+	// DefaultBranchAndBoundTries.
+	MaxTries int
+	// Fallback is the selector used when no exact match is found.
+	Fallback CoinSelector
+}
+
+func (s *BranchAndBound) SelectCoins(coins []*Coin, target *SelectionTarget) ([]*Coin, error) {
+	selected, err := s.search(coins, target)
+	if err == ErrNoExactMatch && s.Fallback != nil {
+		return s.Fallback.SelectCoins(coins, target)
+	}
+	return selected, err
+}
+
+func (s *BranchAndBound) search(coins []*Coin, target *SelectionTarget) ([]*Coin, error) {
+	maxTries := s.MaxTries
+	if maxTries <= 0 {
+		maxTries = DefaultBranchAndBoundTries
+	}
+
+	// the effective value is the value of a coin minus the fee of spending it
+	candidates := make([]*Coin, 0, len(coins))
+	var available Fixed64
+	for _, coin := range sortCoins(coins) {
+		if coin.Value > target.InputFee {
+			candidates = append(candidates, coin)
+			available += coin.Value - target.InputFee
+		}
+	}
+	lower := target.Amount + target.Fee
+	upper := lower + target.ChangeCost
+	if available < lower {
+		return nil, ErrInsufficientFunds
+	}
+
+	var tries int
+	var best []int
+	var bestSum Fixed64
+	selected := make([]int, 0, len(candidates))
+	var search func(index int, sum, remaining Fixed64)
+	search = func(index int, sum, remaining Fixed64) {
+		if tries >= maxTries || sum > upper {
+			return
+		}
+		tries++
+		if sum >= lower {
+			if best == nil || sum < bestSum {
+				best = append([]int{}, selected...)
+				bestSum = sum
+			}
+			return
+		}
+		if index == len(candidates) || sum+remaining < lower {
+			return
+		}
+
+		value := candidates[index].Value - target.InputFee
+		remaining -= value
+
+		// include the coin
+		selected = append(selected, index)
+		search(index+1, sum+value, remaining)
+		selected = selected[:len(selected)-1]
+
+		// exclude the coin
+		search(index+1, sum, remaining)
+	}
+	search(0, 0, available)
+
+	if best == nil {
+		return nil, ErrNoExactMatch
+	}
+	result := make([]*Coin, 0, len(best))
+	for _, index := range best {
+		result = append(result, candidates[index])
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package txbuilder
+
+import (
+	"testing"
+
+	"github.com/wuyazero/Elastos.ELA/core"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+)
+
+func newCoins(values ...common.Fixed64) []*Coin {
+	coins := make([]*Coin, 0, len(values))
+	for i, value := range values {
+		var txID common.Uint256
+		txID[0] = byte(i)
+		coins = append(coins, &Coin{
+			OutPoint: *core.NewOutPoint(txID, uint16(i)),
+			Value:    value,
+		})
+	}
+	return coins
+}
+
+func sumCoins(coins []*Coin) common.Fixed64 {
+	var sum common.Fixed64
+	for _, coin := range coins {
+		sum += coin.Value
+	}
+	return sum
+}
+
+func TestLargestFirst(t *testing.T) {
+	coins := newCoins(10, 50, 20, 40)
+
+	selected, err := LargestFirst{}.SelectCoins(coins, &SelectionTarget{Amount: 60})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(selected))
+	assert.Equal(t, common.Fixed64(50), selected[0].Value)
+	assert.Equal(t, common.Fixed64(40), selected[1].Value)
+
+	// the fee of each input is paid
+	selected, err = LargestFirst{}.SelectCoins(coins, &SelectionTarget{Amount: 85, Fee: 5, InputFee: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(selected))
+
+	_, err = LargestFirst{}.SelectCoins(coins, &SelectionTarget{Amount: 120, Fee: 1})
+	assert.Equal(t, ErrInsufficientFunds, err)
+}
+
+func TestBranchAndBound(t *testing.T) {
+	coins := newCoins(1, 2, 3, 5, 13)
+
+	// exact match needs no change
+	selector := &BranchAndBound{}
+	selected, err := selector.SelectCoins(coins, &SelectionTarget{Amount: 8})
+	assert.NoError(t, err)
+	assert.Equal(t, common.Fixed64(8), sumCoins(selected))
+
+	// the excess within the change cost is accepted
+	selected, err = selector.SelectCoins(coins, &SelectionTarget{Amount: 12, ChangeCost: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, common.Fixed64(13), sumCoins(selected))
+
+	// the input fee is taken from the effective value
+	selected, err = selector.SelectCoins(coins, &SelectionTarget{Amount: 6, InputFee: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, common.Fixed64(6), sumCoins(selected)-common.Fixed64(len(selected)))
+
+	// no exact match without fallback
+	coins = newCoins(10, 20)
+	_, err = selector.SelectCoins(coins, &SelectionTarget{Amount: 15})
+	assert.Equal(t, ErrNoExactMatch, err)
+
+	// fallback selector
+	selector.Fallback = LargestFirst{}
+	selected, err = selector.SelectCoins(coins, &SelectionTarget{Amount: 15})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(selected))
+	assert.Equal(t, common.Fixed64(20), selected[0].Value)
+
+	_, err = selector.SelectCoins(coins, &SelectionTarget{Amount: 31})
+	assert.Equal(t, ErrInsufficientFunds, err)
+}
@@ -0,0 +1,385 @@
+package txbuilder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/wuyazero/Elastos.ELA/blockchain"
+	. "github.com/wuyazero/Elastos.ELA/core"
+
+	. "github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
+)
+
+// PSTVersion is the version of the serialized partially signed transaction.
+const PSTVersion byte = 0x00
+
+// pstMagic is the prefix of a serialized partially signed transaction.
+var pstMagic = []byte{'p', 's', 't', 0xff}
+
+// PartialSignature is a signature of a co-signer of a program.
+type PartialSignature struct {
+	PublicKey []byte
+	Signature []byte
+}
+
+// PartiallySignedTransaction carries an unsigned transaction between the
+// co-signers, with the outputs referenced by its inputs and the signatures
+// collected for each of its programs, so it can be signed offline.
+type PartiallySignedTransaction struct {
+	// Transaction is the transaction to be signed, its programs are the
+	// redeem scripts of the spending accounts.
+	Transaction *Transaction
+	// References are the outputs referenced by the inputs, in input order.
+	References []*Output
+	// Signatures are the signatures of each program of the transaction.
+	Signatures [][]*PartialSignature
+}
+
+// NewPartiallySignedTransaction creates a partially signed transaction of the
+// transaction, the programs of the transaction are the redeem scripts to sign.
+func NewPartiallySignedTransaction(txn *Transaction, references map[*Input]*Output) (*PartiallySignedTransaction, error) {
+	if len(txn.Programs) == 0 {
+		return nil, errors.New("no programs to sign")
+	}
+	pst := &PartiallySignedTransaction{Transaction: new(Transaction)}
+	*pst.Transaction = *txn
+	pst.Transaction.Programs = make([]*Program, 0, len(txn.Programs))
+	for _, program := range txn.Programs {
+		if _, err := getPublicKeys(program.Code); err != nil {
+			return nil, err
+		}
+		pst.Transaction.Programs = append(pst.Transaction.Programs,
+			&Program{Code: program.Code, Parameter: []byte{}})
+	}
+	if err := blockchain.SortPrograms(pst.Transaction.Programs); err != nil {
+		return nil, err
+	}
+	for _, input := range txn.Inputs {
+		output, ok := references[input]
+		if !ok {
+			return nil, errors.New("referenced output of input not found")
+		}
+		pst.References = append(pst.References, output)
+	}
+	pst.Signatures = make([][]*PartialSignature, len(pst.Transaction.Programs))
+	return pst, nil
+}
+
+func (pst *PartiallySignedTransaction) data() []byte {
+	buf := new(bytes.Buffer)
+	pst.Transaction.SerializeUnsigned(buf)
+	return buf.Bytes()
+}
+
+func (pst *PartiallySignedTransaction) references() map[*Input]*Output {
+	references := make(map[*Input]*Output, len(pst.References))
+	for i, input := range pst.Transaction.Inputs {
+		references[input] = pst.References[i]
+	}
+	return references
+}
+
+// Sign signs the programs which the public key belongs to, and returns the
+// number of programs signed.
+func (pst *PartiallySignedTransaction) Sign(private []byte, public *crypto.PublicKey) (int, error) {
+	publicKey, err := public.EncodePoint(true)
+	if err != nil {
+		return 0, err
+	}
+	signature, err := crypto.Sign(private, pst.data())
+	if err != nil {
+		return 0, err
+	}
+	return pst.AddSignature(publicKey, signature)
+}
+
+// AddSignature verifies and adds the signature to the programs which the
+// public key belongs to, and returns the number of programs signed.
+func (pst *PartiallySignedTransaction) AddSignature(publicKey, signature []byte) (int, error) {
+	point, err := crypto.DecodePoint(publicKey)
+	if err != nil {
+		return 0, err
+	}
+	if err := crypto.Verify(*point, pst.data(), signature); err != nil {
+		return 0, errors.New("invalid signature")
+	}
+
+	var count int
+	for i, program := range pst.Transaction.Programs {
+		publicKeys, err := getPublicKeys(program.Code)
+		if err != nil {
+			return 0, err
+		}
+		if indexOfKey(publicKeys, publicKey) < 0 {
+			continue
+		}
+		if pst.addSignature(i, &PartialSignature{PublicKey: publicKey, Signature: signature}) {
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, errors.New("no programs signed by the public key")
+	}
+	return count, nil
+}
+
+// verifySignature checks the signature is signed on the data by a co-signer of
+// the program, so a bogus signature can not take the place of the genuine one.
+func (pst *PartiallySignedTransaction) verifySignature(index int, signature *PartialSignature, data []byte) error {
+	publicKeys, err := getPublicKeys(pst.Transaction.Programs[index].Code)
+	if err != nil {
+		return err
+	}
+	if indexOfKey(publicKeys, signature.PublicKey) < 0 {
+		return fmt.Errorf("public key not in program %d", index)
+	}
+	point, err := crypto.DecodePoint(signature.PublicKey)
+	if err != nil {
+		return err
+	}
+	if err := crypto.Verify(*point, data, signature.Signature); err != nil {
+		return fmt.Errorf("invalid signature of program %d", index)
+	}
+	return nil
+}
+
+func (pst *PartiallySignedTransaction) addSignature(index int, signature *PartialSignature) bool {
+	for _, s := range pst.Signatures[index] {
+		if bytes.Equal(s.PublicKey, signature.PublicKey) {
+			return false
+		}
+	}
+	pst.Signatures[index] = append(pst.Signatures[index], signature)
+	return true
+}
+
+// Combine merges the signatures of the other partially signed transactions of
+// the same transaction, nothing is merged if any of them is invalid.
+func (pst *PartiallySignedTransaction) Combine(others ...*PartiallySignedTransaction) error {
+	data := pst.data()
+	for _, other := range others {
+		if other.Transaction.Hash() != pst.Transaction.Hash() {
+			return errors.New("partially signed transactions of different transactions")
+		}
+		if len(other.Signatures) != len(pst.Signatures) ||
+			len(other.Transaction.Programs) != len(pst.Transaction.Programs) {
+			return errors.New("partially signed transactions of different programs")
+		}
+		for i, signatures := range other.Signatures {
+			if !bytes.Equal(other.Transaction.Programs[i].Code, pst.Transaction.Programs[i].Code) {
+				return errors.New("partially signed transactions of different programs")
+			}
+			for _, signature := range signatures {
+				if err := pst.verifySignature(i, signature, data); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, other := range others {
+		for i, signatures := range other.Signatures {
+			for _, signature := range signatures {
+				pst.addSignature(i, signature)
+			}
+		}
+	}
+	return nil
+}
+
+// IsComplete checks if every program has collected enough signatures.
+func (pst *PartiallySignedTransaction) IsComplete() bool {
+	for i, program := range pst.Transaction.Programs {
+		count, err := signatureCount(program.Code)
+		if err != nil || len(pst.Signatures[i]) < count {
+			return false
+		}
+	}
+	return true
+}
+
+// Finalize puts the collected signatures into the parameters of the programs
+// and verifies the transaction signatures, the invalid signatures are skipped.
+func (pst *PartiallySignedTransaction) Finalize() error {
+	data := pst.data()
+	for i, program := range pst.Transaction.Programs {
+		publicKeys, err := getPublicKeys(program.Code)
+		if err != nil {
+			return err
+		}
+		count, err := signatureCount(program.Code)
+		if err != nil {
+			return err
+		}
+
+		// the signatures are put in the order of the public keys
+		parameter := new(bytes.Buffer)
+		var signed int
+		for _, publicKey := range publicKeys {
+			for _, s := range pst.Signatures[i] {
+				if signed == count || !bytes.Equal(s.PublicKey, publicKey) {
+					continue
+				}
+				if pst.verifySignature(i, s, data) != nil {
+					continue
+				}
+				parameter.WriteByte(byte(len(s.Signature)))
+				parameter.Write(s.Signature)
+				signed++
+				break
+			}
+		}
+		if signed < count {
+			return fmt.Errorf("program %d signatures not enough, %d of %d", i, signed, count)
+		}
+		program.Parameter = parameter.Bytes()
+	}
+	return pst.verify()
+}
+
+// Extract returns the signed transaction of a finalized partially signed
+// transaction.
+func (pst *PartiallySignedTransaction) Extract() (*Transaction, error) {
+	if err := pst.verify(); err != nil {
+		return nil, err
+	}
+	return pst.Transaction, nil
+}
+
+func (pst *PartiallySignedTransaction) verify() error {
+	hashes, err := blockchain.GetTxProgramHashes(pst.Transaction, pst.references())
+	if err != nil {
+		return err
+	}
+	SortProgramHashes(hashes)
+	return blockchain.RunPrograms(pst.data(), hashes, pst.Transaction.Programs)
+}
+
+func (pst *PartiallySignedTransaction) Serialize(w io.Writer) error {
+	if _, err := w.Write(pstMagic); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{PSTVersion}); err != nil {
+		return err
+	}
+	if err := pst.Transaction.Serialize(w); err != nil {
+		return err
+	}
+	if err := WriteVarUint(w, uint64(len(pst.References))); err != nil {
+		return errors.New("[PST], references count serialize failed.")
+	}
+	for _, output := range pst.References {
+		if err := output.Serialize(w); err != nil {
+			return err
+		}
+	}
+	for _, signatures := range pst.Signatures {
+		if err := WriteVarUint(w, uint64(len(signatures))); err != nil {
+			return errors.New("[PST], signatures count serialize failed.")
+		}
+		for _, s := range signatures {
+			if err := WriteVarBytes(w, s.PublicKey); err != nil {
+				return errors.New("[PST], public key serialize failed.")
+			}
+			if err := WriteVarBytes(w, s.Signature); err != nil {
+				return errors.New("[PST], signature serialize failed.")
+			}
+		}
+	}
+	return nil
+}
+
+func (pst *PartiallySignedTransaction) Deserialize(r io.Reader) error {
+	header := make([]byte, len(pstMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return err
+	}
+	if !bytes.Equal(header[:len(pstMagic)], pstMagic) {
+		return errors.New("[PST], invalid magic")
+	}
+	if header[len(pstMagic)] != PSTVersion {
+		return errors.New("[PST], unknown version")
+	}
+
+	pst.Transaction = new(Transaction)
+	if err := pst.Transaction.Deserialize(r); err != nil {
+		return err
+	}
+	count, err := ReadVarUint(r, 0)
+	if err != nil {
+		return errors.New("[PST], references count deserialize failed.")
+	}
+	if count != uint64(len(pst.Transaction.Inputs)) {
+		return errors.New("[PST], references count not match inputs")
+	}
+	pst.References = make([]*Output, 0, count)
+	for i := uint64(0); i < count; i++ {
+		output := new(Output)
+		if err := output.Deserialize(r); err != nil {
+			return err
+		}
+		pst.References = append(pst.References, output)
+	}
+	data := pst.data()
+	pst.Signatures = make([][]*PartialSignature, len(pst.Transaction.Programs))
+	for i := range pst.Signatures {
+		count, err := ReadVarUint(r, 0)
+		if err != nil {
+			return errors.New("[PST], signatures count deserialize failed.")
+		}
+		for j := uint64(0); j < count; j++ {
+			s := new(PartialSignature)
+			if s.PublicKey, err = ReadVarBytes(r); err != nil {
+				return errors.New("[PST], public key deserialize failed.")
+			}
+			if s.Signature, err = ReadVarBytes(r); err != nil {
+				return errors.New("[PST], signature deserialize failed.")
+			}
+			if err := pst.verifySignature(i, s, data); err != nil {
+				return errors.New("[PST], " + err.Error())
+			}
+			if !pst.addSignature(i, s) {
+				return errors.New("[PST], duplicated signature")
+			}
+		}
+	}
+	return nil
+}
+
+// getPublicKeys returns the compressed public keys of the standard or
+// multisig code.
+func getPublicKeys(code []byte) ([][]byte, error) {
+	if len(code) == 0 {
+		return nil, errors.New("empty program code")
+	}
+	switch code[len(code)-1] {
+	case STANDARD:
+		if len(code) != crypto.PublicKeyScriptLength {
+			return nil, errors.New("invalid standard program code")
+		}
+		return [][]byte{code[1 : len(code)-1]}, nil
+	case MULTISIG:
+		scripts, err := crypto.ParseMultisigScript(code)
+		if err != nil {
+			return nil, err
+		}
+		publicKeys := make([][]byte, 0, len(scripts))
+		for _, script := range scripts {
+			publicKeys = append(publicKeys, script[1:])
+		}
+		return publicKeys, nil
+	}
+	return nil, fmt.Errorf("unsupported program code %x", code)
+}
+
+func indexOfKey(publicKeys [][]byte, publicKey []byte) int {
+	for i, key := range publicKeys {
+		if bytes.Equal(key, publicKey) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,129 @@
+package txbuilder
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wuyazero/Elastos.ELA/core"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wuyazero/Elastos.ELA.Utility/common"
+	"github.com/wuyazero/Elastos.ELA.Utility/crypto"
+)
+
+func copyPST(t *testing.T, pst *PartiallySignedTransaction) *PartiallySignedTransaction {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, pst.Serialize(buf))
+	result := new(PartiallySignedTransaction)
+	assert.NoError(t, result.Deserialize(buf))
+	return result
+}
+
+func TestPartiallySignedTransaction(t *testing.T) {
+	var assetID common.Uint256
+	accounts := make([]*testAccount, 0, 3)
+	publicKeys := make([]*crypto.PublicKey, 0, 3)
+	for i := 0; i < 3; i++ {
+		accounts = append(accounts, newTestAccount(t))
+		publicKeys = append(publicKeys, accounts[i].public)
+	}
+	code, err := crypto.CreateMultiSignRedeemScript(2, publicKeys)
+	assert.NoError(t, err)
+	recipient := newTestAccount(t)
+
+	builder := New(assetID)
+	programHash, err := builder.AddAccount(code)
+	assert.NoError(t, err)
+	builder.ChangeProgramHash = *programHash
+	coins := newCoins(200000)
+	coins[0].ProgramHash = *programHash
+	assert.NoError(t, builder.AddCoin(coins[0]))
+	builder.AddOutput(recipient.programHash, 150000)
+	txn, err := builder.Build()
+	assert.NoError(t, err)
+
+	references := map[*core.Input]*core.Output{
+		txn.Inputs[0]: {AssetID: assetID, Value: coins[0].Value, ProgramHash: *programHash},
+	}
+	pst, err := NewPartiallySignedTransaction(txn, references)
+	assert.NoError(t, err)
+	assert.False(t, pst.IsComplete())
+
+	// co-signers sign their own copies offline
+	pst1, pst2 := copyPST(t, pst), copyPST(t, pst)
+	assert.Equal(t, pst.Transaction.Hash(), pst1.Transaction.Hash())
+	count, err := pst1.Sign(accounts[0].private, accounts[0].public)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	_, err = pst2.Sign(accounts[2].private, accounts[2].public)
+	assert.NoError(t, err)
+	assert.False(t, pst1.IsComplete())
+
+	// signature of a key not in the programs
+	_, err = pst2.Sign(recipient.private, recipient.public)
+	assert.Error(t, err)
+
+	// invalid signature
+	publicKey, _ := accounts[1].public.EncodePoint(true)
+	_, err = pst2.AddSignature(publicKey, make([]byte, 64))
+	assert.EqualError(t, err, "invalid signature")
+
+	// not enough signatures
+	err = copyPST(t, pst1).Finalize()
+	assert.Error(t, err)
+
+	// a bogus signature is rejected by combine and deserialize, and nothing
+	// is merged from a combination including it
+	bogus := copyPST(t, pst)
+	bogus.Signatures[0] = append(bogus.Signatures[0],
+		&PartialSignature{PublicKey: publicKey, Signature: make([]byte, 64)})
+	assert.Error(t, pst1.Combine(pst2, bogus))
+	assert.Equal(t, 1, len(pst1.Signatures[0]))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, bogus.Serialize(buf))
+	assert.Error(t, new(PartiallySignedTransaction).Deserialize(buf))
+
+	// the bogus signature does not block the genuine one of the same key
+	pst3 := copyPST(t, pst)
+	assert.Error(t, pst3.Combine(bogus))
+	count, err = pst3.Sign(accounts[1].private, accounts[1].public)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, pst3.Combine(pst2))
+	assert.True(t, pst3.IsComplete())
+	assert.NoError(t, pst3.Finalize())
+
+	// invalid signatures put in directly are skipped when finalizing
+	pst4 := copyPST(t, pst2)
+	pst4.Signatures[0] = append(pst4.Signatures[0], bogus.Signatures[0][0])
+	assert.Error(t, pst4.Finalize())
+	assert.NoError(t, pst4.Combine(pst1))
+	assert.NoError(t, pst4.Finalize())
+
+	// combine and finalize
+	assert.NoError(t, pst1.Combine(pst2, copyPST(t, pst2)))
+	assert.Equal(t, 2, len(pst1.Signatures[0]))
+	assert.True(t, pst1.IsComplete())
+	assert.NoError(t, pst1.Finalize())
+	signed, err := pst1.Extract()
+	assert.NoError(t, err)
+	assert.Equal(t, 2*crypto.SignatureScriptLength, len(signed.Programs[0].Parameter))
+	assert.Equal(t, txn.Hash(), signed.Hash())
+
+	// the finalized transaction round trips
+	signed, err = copyPST(t, pst1).Extract()
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Hash(), signed.Hash())
+
+	// unsigned transaction can not be extracted
+	_, err = pst.Extract()
+	assert.Error(t, err)
+
+	// partially signed transactions of different transactions
+	txn2, err := builder.Build()
+	assert.NoError(t, err)
+	references = map[*core.Input]*core.Output{txn2.Inputs[0]: references[txn.Inputs[0]]}
+	other, err := NewPartiallySignedTransaction(txn2, references)
+	assert.NoError(t, err)
+	assert.Error(t, pst.Combine(other))
+}